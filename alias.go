@@ -0,0 +1,80 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/go-pogo/errors"
+)
+
+const WarnDeprecatedValue errors.Msg = "deprecated value"
+
+// Alias maps raw value From to its canonical raw value To. This allows
+// staged migrations of e.g. enum or bool tokens, without breaking existing
+// configurations.
+//
+//	rawconv.Alias{From: "master", To: "main", Deprecated: true}
+type Alias struct {
+	// Type limits the Alias to destinations of this type, or pointers to it.
+	// The Alias applies to destinations of any type, except arrays, slices
+	// and maps, when Type is nil.
+	Type reflect.Type
+	// From is the alternative raw value, it is matched case-insensitive.
+	From Value
+	// To is the canonical raw value which replaces From.
+	To Value
+	// Deprecated results in a Warning, with a DeprecatedValueError, whenever
+	// the Alias is used. See Options.OnWarning.
+	Deprecated bool
+}
+
+// DeprecatedValueError describes the use of a deprecated Alias.
+type DeprecatedValueError struct {
+	Alias Alias
+}
+
+func (e *DeprecatedValueError) Is(err error) bool { return err == WarnDeprecatedValue }
+
+func (e *DeprecatedValueError) Error() string {
+	return "value `" + e.Alias.From.String() + "` is deprecated, use `" +
+		e.Alias.To.String() + "` instead"
+}
+
+// alias returns the canonical value of v when it matches one of
+// Options.Aliases for typ.
+func (u *Unmarshaler) alias(v Value, typ reflect.Type, path string) Value {
+	elem := typ
+	for elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+
+	var collection *bool
+	for _, a := range u.Aliases {
+		if !strings.EqualFold(v.String(), a.From.String()) {
+			continue
+		}
+		if a.Type != nil && a.Type != elem {
+			continue
+		}
+		if a.Type == nil {
+			// lazy check, so the collection check is only done for matches
+			if collection == nil {
+				c := u.collection(typ)
+				collection = &c
+			}
+			if *collection {
+				continue
+			}
+		}
+
+		if a.Deprecated {
+			u.warn(path, typ, errors.WithStack(&DeprecatedValueError{Alias: a}))
+		}
+		return a.To
+	}
+	return v
+}
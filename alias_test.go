@@ -0,0 +1,59 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshaler_Aliases(t *testing.T) {
+	type branch string
+
+	var warnings []Warning
+	var u Unmarshaler
+	u.OnWarning = func(w Warning) { warnings = append(warnings, w) }
+	u.Aliases = []Alias{
+		{Type: reflect.TypeOf(branch("")), From: "master", To: "main", Deprecated: true},
+		{Type: reflect.TypeOf(true), From: "enabled", To: "true"},
+		{From: "none", To: ""},
+	}
+
+	t.Run("typed", func(t *testing.T) {
+		warnings = nil
+
+		var have []branch
+		assert.NoError(t, u.Unmarshal("main,Master,dev", reflect.ValueOf(&have)))
+		assert.Equal(t, []branch{"main", "main", "dev"}, have)
+
+		if assert.Len(t, warnings, 1) {
+			assert.Equal(t, "[1]", warnings[0].Path)
+			assert.ErrorIs(t, warnings[0].Err, WarnDeprecatedValue)
+			assert.Equal(t, "[1]: value `master` is deprecated, use `main` instead", warnings[0].String())
+		}
+	})
+	t.Run("type mismatch", func(t *testing.T) {
+		var have string
+		assert.NoError(t, u.Unmarshal("master", reflect.ValueOf(&have)))
+		assert.Equal(t, "master", have)
+	})
+	t.Run("bool", func(t *testing.T) {
+		var have *bool
+		assert.NoError(t, u.Unmarshal("enabled", reflect.ValueOf(&have)))
+		assert.Equal(t, ptr(true), have)
+	})
+	t.Run("any type", func(t *testing.T) {
+		have := 5
+		assert.NoError(t, u.Unmarshal("none", reflect.ValueOf(&have)))
+		assert.Equal(t, 5, have)
+	})
+	t.Run("collection items", func(t *testing.T) {
+		var have []string
+		assert.NoError(t, u.Unmarshal("none", reflect.ValueOf(&have)))
+		assert.Equal(t, []string{""}, have)
+	})
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+
+	"github.com/go-pogo/errors"
+)
+
+// MarshalAppend appends the string representation of the value to dst and
+// returns the extended buffer. Unlike Marshal, values of builtin kinds, and
+// the items of arrays, slices and maps, are formatted directly into dst
+// without intermediate string allocations.
+func (m *Marshaler) MarshalAppend(dst []byte, val reflect.Value) ([]byte, error) {
+	if len(m.middleware) != 0 {
+		return m.appendMarshal(context.Background(), dst, val, 0)
+	}
+	return m.appendValue(context.Background(), dst, val, 0)
+}
+
+// appendValue appends the string representation of val to dst.
+func (m *Marshaler) appendValue(ctx context.Context, dst []byte, val reflect.Value, depth int) ([]byte, error) {
+	if !val.IsValid() || m.Strict {
+		return m.appendMarshal(ctx, dst, val, depth)
+	}
+	if fn, _, _ := m.lookup(val.Type()); fn != nil {
+		return m.appendMarshal(ctx, dst, val, depth)
+	}
+	if err := ctx.Err(); err != nil {
+		return dst, errors.WithStack(err)
+	}
+
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return dst, nil
+		}
+		val = val.Elem()
+	}
+
+	switch val.Kind() {
+	case reflect.String:
+		return append(dst, val.String()...), nil
+
+	case reflect.Bool:
+		return append(dst, m.formatBool(val.Bool())...), nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.AppendInt(dst, val.Int(), m.formatBase()), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.AppendUint(dst, val.Uint(), m.formatBase()), nil
+
+	case reflect.Float32, reflect.Float64:
+		return strconv.AppendFloat(dst, val.Float(), 'g', -1, val.Type().Bits()), nil
+
+	case reflect.Array, reflect.Slice:
+		if val.Type() == jsonRawMessageType || m.isBinary(val.Type()) {
+			break
+		}
+		fallthrough
+
+	case reflect.Map:
+		return m.appendCollection(ctx, dst, val, depth)
+	}
+	return m.appendMarshal(ctx, dst, val, depth)
+}
+
+// appendMarshal appends the result of marshal to dst.
+func (m *Marshaler) appendMarshal(ctx context.Context, dst []byte, val reflect.Value, depth int) ([]byte, error) {
+	str, err := m.marshal(ctx, val, depth)
+	return append(dst, str...), err
+}
+
+// appendCollection appends the items of array, slice or map val to dst.
+func (m *Marshaler) appendCollection(ctx context.Context, dst []byte, val reflect.Value, depth int) ([]byte, error) {
+	if m.isNilCollection(val) {
+		return append(dst, m.NilToken...), nil
+	}
+	if !m.nestable(depth) {
+		return dst, errors.New(ErrMarshalNested)
+	}
+
+	brackets := depth > 0 && m.NestedBrackets
+	if brackets {
+		dst = append(dst, '[')
+	}
+
+	var err error
+	sep := m.itemSeparatorAt(depth)
+	if val.Kind() == reflect.Map {
+		keys, err := m.mapKeys(ctx, val, depth+1)
+		if err != nil {
+			return dst, err
+		}
+
+		kvSep := m.keyValueSeparator()
+		for i, key := range keys {
+			if i > 0 {
+				dst = append(dst, sep...)
+			}
+			if dst, err = m.appendKey(ctx, dst, key, depth+1); err != nil {
+				return dst, err
+			}
+			dst = append(dst, kvSep...)
+			if dst, err = m.appendItem(ctx, dst, val.MapIndex(key), depth+1); err != nil {
+				return dst, err
+			}
+		}
+	} else {
+		for i := 0; i < val.Len(); i++ {
+			if i > 0 {
+				dst = append(dst, sep...)
+			}
+			if dst, err = m.appendItem(ctx, dst, val.Index(i), depth+1); err != nil {
+				return dst, err
+			}
+		}
+	}
+
+	if brackets {
+		dst = append(dst, ']')
+	}
+	return dst, nil
+}
+
+// appendItem appends the string representation of the item val, of a
+// collection at depth-1, to dst. Leaf items are quoted when required.
+func (m *Marshaler) appendItem(ctx context.Context, dst []byte, val reflect.Value, depth int) ([]byte, error) {
+	if coll, ok := m.collection(val); ok {
+		return m.appendCollection(ctx, dst, coll, depth)
+	}
+	return m.appendQuoted(ctx, dst, val, depth)
+}
+
+// appendKey appends the string representation of map key val to dst.
+func (m *Marshaler) appendKey(ctx context.Context, dst []byte, val reflect.Value, depth int) ([]byte, error) {
+	if _, ok := m.collection(val); ok {
+		return m.appendValue(ctx, dst, val, depth)
+	}
+	return m.appendQuoted(ctx, dst, val, depth)
+}
+
+// appendQuoted appends the string representation of val to dst, and quotes or
+// escapes it according to the Options.
+func (m *Marshaler) appendQuoted(ctx context.Context, dst []byte, val reflect.Value, depth int) ([]byte, error) {
+	start := len(dst)
+	dst, err := m.appendValue(ctx, dst, val, depth)
+	if err != nil || (!m.Quote && !m.Escape) {
+		return dst, err
+	}
+
+	str := string(dst[start:])
+	if q := m.quote(str); q != str {
+		dst = append(dst[:start], q...)
+	}
+	return dst, nil
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshaler_MarshalAppend(t *testing.T) {
+	tests := map[string]struct {
+		opts  Options
+		input any
+		want  string
+	}{
+		"string":   {input: "foo", want: "foo"},
+		"bool":     {input: true, want: "true"},
+		"int":      {input: -8, want: "-8"},
+		"uint":     {input: uint16(8), want: "8"},
+		"float":    {input: float32(1.5), want: "1.5"},
+		"complex":  {input: 1 + 2i, want: "(1+2i)"},
+		"pointer":  {input: ptr(3), want: "3"},
+		"nil":      {input: (*int)(nil), want: ""},
+		"duration": {input: time.Second, want: "1s"},
+		"raw json": {input: json.RawMessage(`{"a":1}`), want: `{"a":1}`},
+		"slice":    {input: []int{1, 2, 3}, want: "1,2,3"},
+		"array":    {input: [2]bool{true, false}, want: "true,false"},
+		"map":      {input: map[string]int{"a": 1}, want: "a=1"},
+		"nested": {
+			opts:  Options{NestedBrackets: true},
+			input: [][]uint{{1, 2}, {3}},
+			want:  "[1,2],[3]",
+		},
+		"quote": {
+			opts:  Options{Quote: true},
+			input: []string{"x,y", " z"},
+			want:  `"x,y"," z"`,
+		},
+		"bool tokens": {
+			opts:  Options{BoolTrueTokens: []string{"yes"}},
+			input: []bool{true},
+			want:  "yes",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := Marshaler{Options: tc.opts}
+			have, err := m.MarshalAppend([]byte("prefix:"), reflect.ValueOf(tc.input))
+			assert.NoError(t, err)
+			assert.Equal(t, "prefix:"+tc.want, string(have))
+
+			str, err := m.Marshal(reflect.ValueOf(tc.input))
+			assert.NoError(t, err)
+			assert.Equal(t, Value(tc.want), str)
+		})
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		var m Marshaler
+		_, err := m.MarshalAppend(nil, reflect.ValueOf(make(chan int)))
+		assert.ErrorIs(t, err, &UnsupportedTypeError{Type: reflect.TypeOf(make(chan int))})
+	})
+	t.Run("allocations", func(t *testing.T) {
+		var m Marshaler
+		val := reflect.ValueOf([]int{1, 22, 333, 4444})
+		buf := make([]byte, 0, 64)
+
+		allocs := testing.AllocsPerRun(100, func() {
+			buf, _ = m.MarshalAppend(buf[:0], val)
+		})
+		assert.Equal(t, "1,22,333,4444", string(buf))
+		assert.Zero(t, allocs)
+	})
+}
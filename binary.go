@@ -0,0 +1,57 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"encoding"
+	"reflect"
+)
+
+var (
+	binaryUnmarshalerType = reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem()
+	binaryMarshalerType   = reflect.TypeOf((*encoding.BinaryMarshaler)(nil)).Elem()
+)
+
+// isBinaryInterface indicates if typ is encoding.BinaryUnmarshaler or
+// encoding.BinaryMarshaler. These interfaces are registered before any other
+// interface, so they are only used for types which do not implement any other
+// registered interface, and are never considered ambiguous.
+func isBinaryInterface(typ reflect.Type) bool {
+	return typ == binaryUnmarshalerType || typ == binaryMarshalerType
+}
+
+// binaryEncoding returns the BytesEncoding which is used to encode the result
+// of encoding.BinaryMarshaler and decode the input of
+// encoding.BinaryUnmarshaler.
+func (o Options) binaryEncoding() BytesEncoding {
+	if o.BinaryEncoding == BytesHex {
+		return BytesHex
+	}
+	return BytesBase64
+}
+
+func (o Options) unmarshalBinary(val Value, dest any) error {
+	if val.IsEmpty() {
+		return nil
+	}
+
+	b, err := o.binaryEncoding().Decode(val)
+	if err != nil {
+		return err
+	}
+	return dest.(encoding.BinaryUnmarshaler).UnmarshalBinary(b)
+}
+
+func (o Options) marshalBinary(v any) (string, error) {
+	b, err := v.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return "", err
+	}
+	return o.binaryEncoding().Encode(b), nil
+}
+
+func unmarshalBinary(val Value, dest any) error { return Options{}.unmarshalBinary(val, dest) }
+
+func marshalBinary(v any) (string, error) { return Options{}.marshalBinary(v) }
@@ -0,0 +1,90 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-pogo/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type binaryOnly struct{ b []byte }
+
+func (b binaryOnly) MarshalBinary() ([]byte, error) { return b.b, nil }
+
+func (b *binaryOnly) UnmarshalBinary(data []byte) error {
+	b.b = append([]byte(nil), data...)
+	return nil
+}
+
+type binaryAndText struct{ s string }
+
+func (b binaryAndText) MarshalBinary() ([]byte, error) { return []byte("binary"), nil }
+
+func (b *binaryAndText) UnmarshalBinary([]byte) error {
+	b.s = "binary"
+	return nil
+}
+
+func (b binaryAndText) MarshalText() ([]byte, error) { return []byte(b.s), nil }
+
+func (b *binaryAndText) UnmarshalText(text []byte) error {
+	b.s = string(text)
+	return nil
+}
+
+const errBinaryMarshal errors.Msg = "binary marshal error"
+
+type errBinary struct{}
+
+func (errBinary) MarshalBinary() ([]byte, error) { return nil, errors.New(errBinaryMarshal) }
+
+func TestBinary(t *testing.T) {
+	tests := map[string]struct {
+		enc  BytesEncoding
+		want Value
+	}{
+		"default": {want: "AAEC/w=="},
+		"base64":  {enc: BytesBase64, want: "AAEC/w=="},
+		"hex":     {enc: BytesHex, want: "000102ff"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := Options{BinaryEncoding: tc.enc}
+			m := Marshaler{Options: opts}
+			have, err := m.Marshal(reflect.ValueOf(binaryOnly{b: []byte{0, 1, 2, 255}}))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, have)
+
+			var dest binaryOnly
+			u := Unmarshaler{Options: opts}
+			assert.NoError(t, u.Unmarshal(have, reflect.ValueOf(&dest)))
+			assert.Equal(t, []byte{0, 1, 2, 255}, dest.b)
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		var dest binaryOnly
+		assert.ErrorIs(t, Unmarshal("not base64!", &dest), ErrParseFailure)
+	})
+	t.Run("text precedence", func(t *testing.T) {
+		u := Unmarshaler{Options: Options{Strict: true}}
+		var dest binaryAndText
+		assert.NoError(t, u.Unmarshal("foo", reflect.ValueOf(&dest)))
+		assert.Equal(t, "foo", dest.s)
+
+		m := Marshaler{Options: Options{Strict: true}}
+		have, err := m.Marshal(reflect.ValueOf(dest))
+		assert.NoError(t, err)
+		assert.Equal(t, Value("foo"), have)
+	})
+	t.Run("marshal error", func(t *testing.T) {
+		_, err := Marshal(errBinary{})
+		assert.ErrorIs(t, err, errBinaryMarshal)
+	})
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+
+	"github.com/go-pogo/errors"
+)
+
+// BytesEncoding defines how binary data, such as []byte, [N]byte and named
+// byte slice types, is represented as a raw string Value.
+type BytesEncoding uint8
+
+const (
+	// BytesRaw uses the raw string as bytes, this is the default.
+	BytesRaw BytesEncoding = iota
+	// BytesBase64 uses standard base64 encoding as defined in RFC 4648.
+	BytesBase64
+	// BytesHex uses hexadecimal encoding.
+	BytesHex
+	// BytesNumberList does not treat byte slices as binary data, instead they
+	// are handled as any other slice of numbers, e.g. "1,2,3".
+	BytesNumberList
+)
+
+// Encode encodes b to a raw string according to BytesEncoding.
+func (enc BytesEncoding) Encode(b []byte) string {
+	switch enc {
+	case BytesBase64:
+		return base64.StdEncoding.EncodeToString(b)
+	case BytesHex:
+		return hex.EncodeToString(b)
+	default:
+		return string(b)
+	}
+}
+
+// Decode decodes Value according to BytesEncoding.
+func (enc BytesEncoding) Decode(v Value) ([]byte, error) {
+	var b []byte
+	var err error
+
+	switch enc {
+	case BytesBase64:
+		b, err = base64.StdEncoding.DecodeString(v.String())
+	case BytesHex:
+		b, err = hex.DecodeString(v.String())
+	default:
+		return v.Bytes(), nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, ErrParseFailure)
+	}
+	return b, nil
+}
+
+const (
+	ErrInvalidJSON errors.Msg = "invalid json"
+	ErrBytesLength errors.Msg = "invalid number of bytes"
+)
+
+var jsonRawMessageType = reflect.TypeOf(json.RawMessage{})
+
+var byteType = reflect.TypeOf(byte(0))
+
+// isBinary indicates if typ is a byte slice or byte array, or a named type
+// with one of those as underlying type, which should be treated as binary
+// data according to BytesEncoding. Unnamed slices and arrays of a named byte
+// type, e.g. []Color, are not binary data.
+func (o Options) isBinary(typ reflect.Type) bool {
+	return (typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array) &&
+		typ.Elem().Kind() == reflect.Uint8 &&
+		(typ.Name() != "" || typ.Elem() == byteType) &&
+		o.BytesEncoding != BytesNumberList
+}
+
+// bytesOf returns the bytes of val, which is a byte slice or byte array.
+func bytesOf(val reflect.Value) []byte {
+	if val.Kind() == reflect.Slice {
+		return val.Bytes()
+	}
+
+	b := make([]byte, val.Len())
+	reflect.Copy(reflect.ValueOf(b), val)
+	return b
+}
+
+// decodeArray decodes Value according to BytesEncoding and copies the result
+// to byte array dest. It returns an ErrBytesLength error when the length of
+// the decoded bytes does not equal the length of dest.
+func (o Options) decodeArray(v Value, dest reflect.Value) error {
+	b, err := o.BytesEncoding.Decode(v)
+	if err != nil {
+		return err
+	}
+	if len(b) != dest.Len() {
+		return errors.Wrap(errors.New(ErrBytesLength), ErrValidationFailure)
+	}
+
+	reflect.Copy(dest, reflect.ValueOf(b))
+	return nil
+}
+
+// rawJSON returns the bytes of Value as json.RawMessage. When
+// Options.ValidateRawJSON is set, it returns an ErrInvalidJSON error when
+// Value does not contain valid json.
+func (o Options) rawJSON(v Value) (json.RawMessage, error) {
+	b := v.Bytes()
+	if o.ValidateRawJSON {
+		if err := json.Compact(new(bytes.Buffer), b); err != nil {
+			return nil, errors.Wrap(err, ErrInvalidJSON)
+		}
+	}
+	return b, nil
+}
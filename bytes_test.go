@@ -0,0 +1,172 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type token []byte
+
+func TestBytesEncoding(t *testing.T) {
+	tests := map[string]struct {
+		enc  BytesEncoding
+		raw  Value
+		want token
+	}{
+		"raw":    {enc: BytesRaw, raw: "hello, world", want: token("hello, world")},
+		"base64": {enc: BytesBase64, raw: "aGVsbG8sIHdvcmxk", want: token("hello, world")},
+		"hex":    {enc: BytesHex, raw: "68656c6c6f2c20776f726c64", want: token("hello, world")},
+	}
+
+	for name, tc := range tests {
+		t.Run(name+"/unmarshal", func(t *testing.T) {
+			u := Unmarshaler{Options: Options{BytesEncoding: tc.enc}}
+
+			var have token
+			assert.NoError(t, u.Unmarshal(tc.raw, reflect.ValueOf(&have)))
+			assert.Equal(t, tc.want, have)
+		})
+		t.Run(name+"/marshal", func(t *testing.T) {
+			m := Marshaler{Options: Options{BytesEncoding: tc.enc}}
+
+			have, err := m.Marshal(reflect.ValueOf(tc.want))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.raw, have)
+		})
+	}
+
+	t.Run("plain bytes", func(t *testing.T) {
+		for _, tc := range tests {
+			u := Unmarshaler{Options: Options{BytesEncoding: tc.enc}}
+
+			var have []byte
+			assert.NoError(t, u.Unmarshal(tc.raw, reflect.ValueOf(&have)))
+			assert.Equal(t, []byte(tc.want), have)
+
+			m := Marshaler{Options: u.Options}
+			val, err := m.Marshal(reflect.ValueOf(have))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.raw, val)
+		}
+
+		var have []uint8
+		assert.NoError(t, Unmarshal("a,b", &have))
+		assert.Equal(t, []uint8("a,b"), have)
+
+		type flag uint8
+		var flags []flag
+		assert.NoError(t, Unmarshal("1,2", &flags))
+		assert.Equal(t, []flag{1, 2}, flags, "slice of named byte type is not binary")
+	})
+	t.Run("array", func(t *testing.T) {
+		type digest [4]byte
+		want := digest{0xde, 0xad, 0xbe, 0xef}
+
+		tests := map[BytesEncoding]Value{
+			BytesRaw:    Value(want[:]),
+			BytesBase64: "3q2+7w==",
+			BytesHex:    "deadbeef",
+		}
+		for enc, raw := range tests {
+			u := Unmarshaler{Options: Options{BytesEncoding: enc}}
+
+			var have digest
+			assert.NoError(t, u.Unmarshal(raw, reflect.ValueOf(&have)))
+			assert.Equal(t, want, have)
+
+			m := Marshaler{Options: u.Options}
+			val, err := m.Marshal(reflect.ValueOf(have))
+			assert.NoError(t, err)
+			assert.Equal(t, raw, val)
+
+			buf, err := m.MarshalAppend(nil, reflect.ValueOf(have))
+			assert.NoError(t, err)
+			assert.Equal(t, raw.String(), string(buf))
+		}
+
+		u := Unmarshaler{Options: Options{BytesEncoding: BytesHex}}
+		have := want
+		for _, raw := range []Value{"dead", "deadbeef00"} {
+			err := u.Unmarshal(raw, reflect.ValueOf(&have))
+			assert.ErrorIs(t, err, ErrBytesLength)
+			assert.ErrorIs(t, err, ErrValidationFailure)
+			assert.Equal(t, want, have, "must be left untouched")
+		}
+		assert.Equal(t, BuiltinKind, u.Mechanism(reflect.TypeOf(have)))
+	})
+	t.Run("invalid", func(t *testing.T) {
+		u := Unmarshaler{Options: Options{BytesEncoding: BytesHex}}
+
+		var have token
+		assert.ErrorIs(t, u.Unmarshal("xyz", reflect.ValueOf(&have)), ErrParseFailure)
+	})
+	t.Run("number list", func(t *testing.T) {
+		u := Unmarshaler{Options: Options{BytesEncoding: BytesNumberList}}
+
+		var have token
+		assert.NoError(t, u.Unmarshal("1,2,3", reflect.ValueOf(&have)))
+		assert.Equal(t, token{1, 2, 3}, have)
+
+		var plain []byte
+		assert.NoError(t, u.Unmarshal("1,2,3", reflect.ValueOf(&plain)))
+		assert.Equal(t, []byte{1, 2, 3}, plain)
+
+		var array [3]byte
+		assert.NoError(t, u.Unmarshal("1,2,3", reflect.ValueOf(&array)))
+		assert.Equal(t, [3]byte{1, 2, 3}, array)
+
+		m := Marshaler{Options: u.Options}
+		val, err := m.Marshal(reflect.ValueOf(have))
+		assert.NoError(t, err)
+		assert.Equal(t, Value("1,2,3"), val)
+	})
+	t.Run("slice of tokens", func(t *testing.T) {
+		var have []token
+		assert.NoError(t, Unmarshal("foo,bar", &have))
+		assert.Equal(t, []token{token("foo"), token("bar")}, have)
+	})
+}
+
+func TestJSONRawMessage(t *testing.T) {
+	const raw = `{"foo": [1, 2, 3], "bar": "baz"}`
+
+	encodings := map[string]BytesEncoding{
+		"raw":         BytesRaw,
+		"base64":      BytesBase64,
+		"hex":         BytesHex,
+		"number list": BytesNumberList,
+	}
+	for name, enc := range encodings {
+		t.Run("passthrough "+name, func(t *testing.T) {
+			opts := Options{BytesEncoding: enc, ValidateRawJSON: true}
+			u := Unmarshaler{Options: opts}
+
+			var have json.RawMessage
+			assert.NoError(t, u.Unmarshal(raw, reflect.ValueOf(&have)))
+			assert.Equal(t, json.RawMessage(raw), have)
+
+			m := Marshaler{Options: opts}
+			val, err := m.Marshal(reflect.ValueOf(have))
+			assert.NoError(t, err)
+			assert.Equal(t, Value(raw), val)
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		var have json.RawMessage
+		assert.NoError(t, Unmarshal("{invalid", &have))
+		assert.Equal(t, json.RawMessage("{invalid"), have)
+
+		u := Unmarshaler{Options: Options{ValidateRawJSON: true}}
+		have = nil
+		assert.ErrorIs(t, u.Unmarshal("{invalid", reflect.ValueOf(&have)), ErrInvalidJSON)
+		assert.Nil(t, have)
+	})
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"github.com/go-pogo/errors"
+)
+
+// ChainUnmarshalFunc returns an UnmarshalFunc which tries each of fns in
+// order, until one of them does not return ErrSkip. When all of fns return
+// ErrSkip, so does the returned UnmarshalFunc, and the next candidate is
+// tried. This allows to register multiple small funcs for a single type.
+//
+//	rawconv.RegisterUnmarshalFunc(reflect.TypeOf(time.Time{}), rawconv.ChainUnmarshalFunc(
+//		parseRFC3339, parseUnixEpoch, parseDateOnly,
+//	))
+func ChainUnmarshalFunc(fns ...UnmarshalFunc) UnmarshalFunc {
+	return func(val Value, dest any) error {
+		for _, fn := range fns {
+			if err := fn(val, dest); !errors.Is(err, ErrSkip) {
+				return err
+			}
+		}
+		return errors.New(ErrSkip)
+	}
+}
+
+// ChainMarshalFunc returns a MarshalFunc which tries each of fns in order,
+// until one of them does not return ErrSkip. See ChainUnmarshalFunc for
+// details.
+func ChainMarshalFunc(fns ...MarshalFunc) MarshalFunc {
+	return func(v any) (string, error) {
+		for _, fn := range fns {
+			if str, err := fn(v); !errors.Is(err, ErrSkip) {
+				return str, err
+			}
+		}
+		return "", errors.New(ErrSkip)
+	}
+}
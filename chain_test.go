@@ -0,0 +1,93 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-pogo/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChainUnmarshalFunc(t *testing.T) {
+	layout := func(layout string) UnmarshalFunc {
+		return func(val Value, dest any) error {
+			x, err := time.Parse(layout, val.String())
+			if err != nil {
+				return ErrSkip
+			}
+			*dest.(*time.Time) = x
+			return nil
+		}
+	}
+	epoch := func(val Value, dest any) error {
+		if strings.Trim(val.String(), "0123456789") != "" {
+			return ErrSkip
+		}
+		x, err := strconv.ParseInt(val.String(), 10, 64)
+		*dest.(*time.Time) = time.Unix(x, 0).UTC()
+		return err
+	}
+
+	var u Unmarshaler
+	u.Register(reflect.TypeOf(time.Time{}), ChainUnmarshalFunc(layout(time.RFC3339), epoch, layout(time.DateOnly)))
+
+	tests := map[string]time.Time{
+		"1997-08-29T02:14:00Z": time.Date(1997, 8, 29, 2, 14, 0, 0, time.UTC),
+		"872820840":            time.Date(1997, 8, 29, 2, 14, 0, 0, time.UTC),
+		"1997-08-29":           time.Date(1997, 8, 29, 0, 0, 0, 0, time.UTC),
+	}
+	for input, want := range tests {
+		t.Run(input, func(t *testing.T) {
+			var have time.Time
+			assert.NoError(t, u.Unmarshal(Value(input), reflect.ValueOf(&have)))
+			assert.Equal(t, want, have)
+		})
+	}
+
+	t.Run("skip all", func(t *testing.T) {
+		// all funcs skip, fallback to the builtin time.Time func
+		var have time.Time
+		assert.NoError(t, u.Unmarshal("Fri, 29 Aug 1997 02:14:00 UTC", reflect.ValueOf(&have)))
+		assert.Equal(t, tests["1997-08-29T02:14:00Z"], have.UTC())
+	})
+	t.Run("error", func(t *testing.T) {
+		const errFail errors.Msg = "fail"
+		fn := ChainUnmarshalFunc(
+			func(Value, any) error { return errFail },
+			func(Value, any) error { return nil },
+		)
+		assert.ErrorIs(t, fn("", nil), errFail)
+		assert.ErrorIs(t, ChainUnmarshalFunc()("", nil), ErrSkip)
+	})
+}
+
+func TestChainMarshalFunc(t *testing.T) {
+	fn := ChainMarshalFunc(
+		func(v any) (string, error) {
+			if v.(int) < 0 {
+				return "negative", nil
+			}
+			return "", ErrSkip
+		},
+		func(v any) (string, error) {
+			if v.(int) == 0 {
+				return "zero", nil
+			}
+			return "", ErrSkip
+		},
+	)
+
+	var m Marshaler
+	m.Register(reflect.TypeOf(0), fn)
+
+	have, err := m.Marshal(reflect.ValueOf([]int{-1, 0, 1}))
+	assert.NoError(t, err)
+	assert.Equal(t, Value("negative,zero,1"), have)
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"hash"
+	"strings"
+
+	"github.com/go-pogo/errors"
+)
+
+const (
+	ErrChecksumMissing  errors.Msg = "checksum is missing"
+	ErrChecksumMismatch errors.Msg = "checksum does not match"
+)
+
+// ChecksumMode determines how values with a checksum are handled when
+// unmarshaling. A value with a checksum has the form
+// "<algorithm>:<hex checksum>:<payload>", e.g. "sha256:2c26b4...:foo".
+// Supported algorithms are sha256 and sha512.
+type ChecksumMode uint8
+
+const (
+	// ChecksumIgnore does not detect checksums, values are unmarshaled as is.
+	ChecksumIgnore ChecksumMode = iota
+	// ChecksumVerify verifies the checksum of values which have one, and
+	// unmarshals their payload. Values without checksum are unmarshaled as
+	// is.
+	ChecksumVerify
+	// ChecksumRequire is like ChecksumVerify but returns an
+	// ErrChecksumMissing error for values without checksum.
+	ChecksumRequire
+)
+
+var checksumAlgorithms = map[string]func() hash.Hash{
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// WithChecksum returns Value val prefixed with its sha256 checksum, in the
+// form which is verified by ChecksumVerify and ChecksumRequire.
+func WithChecksum(val Value) Value {
+	sum := sha256.Sum256(val.Bytes())
+	return Value("sha256:" + hex.EncodeToString(sum[:]) + ":" + val.String())
+}
+
+// verifyChecksum verifies the checksum of v, according to Options.Checksum,
+// and returns its payload.
+func (o Options) verifyChecksum(v Value) (Value, error) {
+	algo, sum, payload, ok := cutChecksum(v.String())
+	if !ok {
+		if o.Checksum == ChecksumRequire && !v.IsEmpty() {
+			return v, errors.New(ErrChecksumMissing)
+		}
+		return v, nil
+	}
+
+	want, err := hex.DecodeString(sum)
+	if err != nil {
+		return v, errors.Wrap(err, ErrChecksumMismatch)
+	}
+
+	h := checksumAlgorithms[algo]()
+	_, _ = h.Write([]byte(payload))
+	if subtle.ConstantTimeCompare(h.Sum(nil), want) != 1 {
+		return v, errors.New(ErrChecksumMismatch)
+	}
+	return Value(payload), nil
+}
+
+// cutChecksum splits str into its algorithm, checksum and payload parts. It
+// returns false when str does not start with a supported algorithm.
+func cutChecksum(str string) (algo, sum, payload string, ok bool) {
+	algo, rest, ok := strings.Cut(str, ":")
+	if !ok {
+		return "", "", "", false
+	}
+	if _, ok = checksumAlgorithms[algo]; !ok {
+		return "", "", "", false
+	}
+
+	sum, payload, ok = strings.Cut(rest, ":")
+	return algo, sum, payload, ok
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithChecksum(t *testing.T) {
+	assert.Equal(t,
+		Value("sha256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae:foo"),
+		WithChecksum("foo"),
+	)
+}
+
+func TestUnmarshaler_Checksum(t *testing.T) {
+	const sha512Foo = "sha512:f7fbba6e0636f890e56fbbf3283e524c6fa3204ae298382d624741d0dc6638326e282c41be5e4254d8820772c5518a2c5a8c0c7f7eda19594a7eb539453e1ed7:foo"
+
+	tests := map[string]struct {
+		mode    ChecksumMode
+		input   Value
+		want    any
+		wantErr error
+	}{
+		"ignore": {
+			mode:  ChecksumIgnore,
+			input: WithChecksum("foo"),
+			want:  WithChecksum("foo").String(),
+		},
+		"verify": {
+			mode:  ChecksumVerify,
+			input: WithChecksum("1,2:3"),
+			want:  []string{"1", "2:3"},
+		},
+		"verify sha512": {
+			mode:  ChecksumVerify,
+			input: sha512Foo,
+			want:  "foo",
+		},
+		"verify without checksum": {
+			mode:  ChecksumVerify,
+			input: "http://localhost",
+			want:  "http://localhost",
+		},
+		"verify mismatch": {
+			mode:    ChecksumVerify,
+			input:   Value(strings.Replace(WithChecksum("foo").String(), ":foo", ":bar", 1)),
+			want:    "",
+			wantErr: ErrChecksumMismatch,
+		},
+		"verify invalid hex": {
+			mode:    ChecksumVerify,
+			input:   "sha256:xyz:foo",
+			want:    "",
+			wantErr: ErrChecksumMismatch,
+		},
+		"require": {
+			mode:  ChecksumRequire,
+			input: WithChecksum("5s"),
+			want:  "5s",
+		},
+		"require missing": {
+			mode:    ChecksumRequire,
+			input:   "foo",
+			want:    "",
+			wantErr: ErrChecksumMissing,
+		},
+		"require empty": {
+			mode:  ChecksumRequire,
+			input: "",
+			want:  "",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			u := Unmarshaler{Options: Options{Checksum: tc.mode}}
+			rv := reflect.New(reflect.TypeOf(tc.want))
+
+			haveErr := u.Unmarshal(tc.input, rv)
+			assert.Equal(t, tc.want, rv.Elem().Interface())
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, haveErr, tc.wantErr)
+			} else {
+				assert.NoError(t, haveErr)
+			}
+		})
+	}
+}
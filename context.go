@@ -0,0 +1,82 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"context"
+	"reflect"
+)
+
+// UnmarshalFuncCtx is a context-aware UnmarshalFunc. It receives the context
+// which is passed to UnmarshalContext, or context.Background when unmarshaling
+// without context.
+type UnmarshalFuncCtx func(ctx context.Context, val Value, dest any) error
+
+// MarshalFuncCtx is a context-aware MarshalFunc. It receives the context which
+// is passed to MarshalContext, or context.Background when marshaling without
+// context.
+type MarshalFuncCtx func(ctx context.Context, v any) (string, error)
+
+// RegisterUnmarshalFuncCtx registers the UnmarshalFuncCtx for typ, making it
+// globally available for Unmarshal, UnmarshalContext and any Unmarshaler.
+// See RegisterUnmarshalFunc for details.
+func RegisterUnmarshalFuncCtx(typ reflect.Type, fn UnmarshalFuncCtx) {
+	unmarshaler.RegisterCtx(typ, fn)
+}
+
+// RegisterMarshalFuncCtx registers the MarshalFuncCtx for typ, making it
+// globally available for Marshal, MarshalContext and any Marshaler.
+// See RegisterMarshalFunc for details.
+func RegisterMarshalFuncCtx(typ reflect.Type, fn MarshalFuncCtx) {
+	marshaler.RegisterCtx(typ, fn)
+}
+
+// RegisterCtx registers the UnmarshalFuncCtx for typ but only for this
+// Unmarshaler. See RegisterUnmarshalFunc for details about precedence.
+func (u *Unmarshaler) RegisterCtx(typ reflect.Type, fn UnmarshalFuncCtx) *Unmarshaler {
+	u.register.addCtx(typ, func(val Value, dest any) error {
+		return fn(context.Background(), val, dest)
+	}, fn)
+	return u
+}
+
+// RegisterCtx registers the MarshalFuncCtx for typ but only for this
+// Marshaler. See RegisterMarshalFunc for details about precedence.
+func (m *Marshaler) RegisterCtx(typ reflect.Type, fn MarshalFuncCtx) *Marshaler {
+	m.register.addCtx(typ, func(v any) (string, error) {
+		return fn(context.Background(), v)
+	}, fn)
+	return m
+}
+
+// UnmarshalContext is like Unmarshal, but passes ctx to any registered
+// UnmarshalFuncCtx. It stops and returns the error of ctx once ctx is done.
+func UnmarshalContext(ctx context.Context, val Value, v any) error {
+	u := Unmarshaler{Options: GlobalOptions()}
+	return u.unmarshalPtr(ctx, val, v)
+}
+
+// UnmarshalContext is like Unmarshal, but passes ctx to any registered
+// UnmarshalFuncCtx. It stops and returns the error of ctx once ctx is done.
+func (u *Unmarshaler) UnmarshalContext(ctx context.Context, val Value, v reflect.Value) error {
+	if err := validDest(v); err != nil {
+		return err
+	}
+	return u.unmarshal(ctx, val, v, "", 0)
+}
+
+// MarshalContext is like Marshal, but passes ctx to any registered
+// MarshalFuncCtx. It stops and returns the error of ctx once ctx is done.
+func MarshalContext(ctx context.Context, v any) (Value, error) {
+	m := Marshaler{Options: GlobalOptions()}
+	return m.MarshalContext(ctx, reflect.ValueOf(v))
+}
+
+// MarshalContext is like Marshal, but passes ctx to any registered
+// MarshalFuncCtx. It stops and returns the error of ctx once ctx is done.
+func (m *Marshaler) MarshalContext(ctx context.Context, val reflect.Value) (Value, error) {
+	str, err := m.marshal(ctx, val, 0)
+	return Value(str), err
+}
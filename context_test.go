@@ -0,0 +1,109 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type ctxKey struct{}
+
+type secret string
+
+func TestUnmarshaler_UnmarshalContext(t *testing.T) {
+	secretType := reflect.TypeOf(secret(""))
+
+	var u Unmarshaler
+	u.RegisterCtx(secretType, func(ctx context.Context, val Value, dest any) error {
+		prefix, _ := ctx.Value(ctxKey{}).(string)
+		*dest.(*secret) = secret(prefix + val.String())
+		return nil
+	})
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "ctx:")
+
+	t.Run("context", func(t *testing.T) {
+		var have []secret
+		assert.NoError(t, u.UnmarshalContext(ctx, "foo,bar", reflect.ValueOf(&have)))
+		assert.Equal(t, []secret{"ctx:foo", "ctx:bar"}, have)
+	})
+	t.Run("without context", func(t *testing.T) {
+		var have secret
+		assert.NoError(t, u.Unmarshal("foo", reflect.ValueOf(&have)))
+		assert.Equal(t, secret("foo"), have)
+
+		assert.NoError(t, u.Func(secretType)("bar", &have))
+		assert.Equal(t, secret("bar"), have)
+	})
+	t.Run("plain func", func(t *testing.T) {
+		var have []int
+		assert.NoError(t, u.UnmarshalContext(ctx, "1,2", reflect.ValueOf(&have)))
+		assert.Equal(t, []int{1, 2}, have)
+	})
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		var have secret
+		assert.ErrorIs(t, u.UnmarshalContext(ctx, "foo", reflect.ValueOf(&have)), context.Canceled)
+		assert.Equal(t, secret(""), have)
+	})
+	t.Run("pointer type", func(t *testing.T) {
+		var u Unmarshaler
+		u.RegisterCtx(reflect.PtrTo(secretType), func(ctx context.Context, val Value, dest any) error {
+			prefix, _ := ctx.Value(ctxKey{}).(string)
+			*dest.(**secret) = ptr(secret(prefix + val.String()))
+			return nil
+		})
+
+		var have *secret
+		assert.NoError(t, u.UnmarshalContext(ctx, "foo", reflect.ValueOf(&have)))
+		assert.Equal(t, ptr(secret("ctx:foo")), have)
+	})
+}
+
+func TestMarshaler_MarshalContext(t *testing.T) {
+	secretType := reflect.TypeOf(secret(""))
+
+	var m Marshaler
+	m.RegisterCtx(secretType, func(ctx context.Context, v any) (string, error) {
+		prefix, _ := ctx.Value(ctxKey{}).(string)
+		return prefix + string(v.(secret)), nil
+	})
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "ctx:")
+
+	t.Run("context", func(t *testing.T) {
+		have, err := m.MarshalContext(ctx, reflect.ValueOf(map[string]secret{"a": "foo"}))
+		assert.NoError(t, err)
+		assert.Equal(t, Value("a=ctx:foo"), have)
+	})
+	t.Run("without context", func(t *testing.T) {
+		have, err := m.Marshal(reflect.ValueOf(secret("foo")))
+		assert.NoError(t, err)
+		assert.Equal(t, Value("foo"), have)
+	})
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := m.MarshalContext(ctx, reflect.ValueOf(secret("foo")))
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestUnmarshalContext(t *testing.T) {
+	var have int
+	assert.NoError(t, UnmarshalContext(context.Background(), "8", &have))
+	assert.Equal(t, 8, have)
+
+	str, err := MarshalContext(context.Background(), have)
+	assert.NoError(t, err)
+	assert.Equal(t, Value("8"), str)
+}
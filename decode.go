@@ -5,14 +5,17 @@
 package rawconv
 
 import (
+	"context"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/go-pogo/errors"
 )
 
 const (
-	ErrPointerExpected    errors.Msg = "expected a non-nil pointer to a value"
+	ErrPointerExpected    errors.Msg = "expected a pointer to a value"
+	ErrNilDestination     errors.Msg = "expected a non-nil destination"
 	ErrUnmarshalNested    errors.Msg = "cannot unmarshal nested array/slice/map"
 	ErrUnableToSet        errors.Msg = "unable to set value"
 	ErrUnableToAddr       errors.Msg = "unable to addr value"
@@ -22,8 +25,10 @@ const (
 	ErrUnmarshalFuncExec  errors.Msg = "error while executing UnmarshalFunc"
 )
 
-// Unmarshal parses Value and stores the result in the value pointed to by v.
-// If v is nil or not a pointer, Unmarshal returns an ErrPointerExpected error.
+// Unmarshal parses Value and stores the result in the value pointed to by v,
+// using the Options set with SetGlobalOptions.
+// If v is nil or a nil pointer, Unmarshal returns an ErrNilDestination error.
+// If v is not a pointer, Unmarshal returns an ErrPointerExpected error.
 // If v is not a supported type an UnsupportedTypeError is returned.
 // By default, the following types are supported:
 //   - string
@@ -35,17 +40,47 @@ const (
 //   - array, slice
 //   - map
 //   - time.Duration
+//   - time.Time
+//   - *time.Location
+//   - rawconv.ByteSize
+//   - rawconv.Tristate
+//   - rawconv.ContentEncoding, rawconv.Charset
+//   - fs.FileMode (octal)
+//   - rawconv.Null[T] and database/sql nullable types, e.g. sql.NullString
 //   - url.URL
+//   - regexp.Regexp
+//   - slog.Level (go1.21+)
+//   - netip.Addr
+//   - netip.AddrPort
+//   - netip.Prefix
+//   - big.Int
+//   - big.Float
+//   - big.Rat
+//   - json.RawMessage
 //   - encoding.TextUnmarshaler
+//   - encoding.BinaryUnmarshaler (see Options.BinaryEncoding)
 //
 // Use RegisterUnmarshalFunc to add additional (custom) types.
+//
+// Pointers are followed to the value they point to, regardless of their level
+// of indirection (e.g. *T, **T, ***T). Any nil pointer along the way is
+// allocated with a new zero value before the Value is parsed, while non-nil
+// pointers are reused. An empty Value does not allocate and leaves the
+// pointers untouched.
 func Unmarshal(val Value, v any) error {
-	rv := reflect.ValueOf(v)
-	if rv.Kind() != reflect.Ptr || rv.IsNil() {
-		return errors.New(ErrPointerExpected)
-	}
+	u := Unmarshaler{Options: GlobalOptions()}
+	return u.unmarshalPtr(context.Background(), val, v)
+}
 
-	return unmarshaler.unmarshal(val, rv, false)
+// UnmarshalAny unmarshals Value to the concrete value held by the interface v
+// points to, using the Options set with SetGlobalOptions. See
+// Unmarshaler.UnmarshalAny for additional details.
+//
+//	var port any = uint16(0)
+//	err := rawconv.UnmarshalAny("8080", &port) // port holds uint16(8080)
+func UnmarshalAny(val Value, v *any) error {
+	u := Unmarshaler{Options: GlobalOptions()}
+	return u.UnmarshalAny(val, v)
 }
 
 // UnmarshalFunc is a function which can unmarshal a Value to any type.
@@ -55,7 +90,10 @@ type UnmarshalFunc func(val Value, dest any) error
 // GetUnmarshalFunc returns the globally registered UnmarshalFunc for
 // reflect.Type typ or nil if there is none registered with
 // RegisterUnmarshalFunc.
-func GetUnmarshalFunc(typ reflect.Type) UnmarshalFunc { return unmarshaler.Func(typ) }
+func GetUnmarshalFunc(typ reflect.Type) UnmarshalFunc {
+	u := Unmarshaler{Options: GlobalOptions()}
+	return u.Func(typ)
+}
 
 // unmarshaler is the global Unmarshaler.
 var unmarshaler Unmarshaler
@@ -65,43 +103,242 @@ var unmarshaler Unmarshaler
 // when a type is not registered.
 type Unmarshaler struct {
 	Options
-	register register[UnmarshalFunc]
+	register   register[UnmarshalFunc]
+	middleware []func(next UnmarshalFunc) UnmarshalFunc
 }
 
 // Register the UnmarshalFunc for typ but only for this Unmarshaler.
+// See RegisterUnmarshalFunc for details about precedence.
 func (u *Unmarshaler) Register(typ reflect.Type, fn UnmarshalFunc) *Unmarshaler {
 	u.register.add(typ, fn)
 	return u
 }
 
+// Deregister removes the UnmarshalFunc which is registered for the exact type
+// typ to this Unmarshaler and returns it. It returns nil when no UnmarshalFunc
+// is registered for typ. Globally registered funcs are not affected.
+func (u *Unmarshaler) Deregister(typ reflect.Type) UnmarshalFunc {
+	return u.register.remove(typ)
+}
+
+// RegisterKind registers the UnmarshalFunc as fallback for all types of kind,
+// but only for this Unmarshaler. See RegisterUnmarshalKindFunc for details.
+func (u *Unmarshaler) RegisterKind(kind reflect.Kind, fn UnmarshalFunc) *Unmarshaler {
+	u.register.addKind(kind, fn)
+	return u
+}
+
 // Func returns the (globally) registered UnmarshalFunc for reflect.Type typ or
 // nil if there is none registered with Register or RegisterUnmarshalFunc.
 func (u *Unmarshaler) Func(typ reflect.Type) UnmarshalFunc {
+	fn, _ := u.lookup(typ)
+	return fn
+}
+
+// funcCtx returns the (globally) registered UnmarshalFunc for typ as
+// UnmarshalFuncCtx, or nil if there is none.
+func (u *Unmarshaler) funcCtx(typ reflect.Type) UnmarshalFuncCtx {
+	return unmarshalFuncCtx(u.lookup(typ))
+}
+
+func (u *Unmarshaler) lookup(typ reflect.Type) (UnmarshalFunc, any) {
 	if u.register.initialized() {
-		if fn := u.register.find(typ); fn != nil {
-			return fn
+		if fn, ctxFn, _ := u.register.resolve(typ, u.Options); fn != nil {
+			return fn, ctxFn
 		}
 	}
 	// fallback to global unmarshaler
-	return unmarshaler.register.find(typ)
+	if fn, ctxFn, _ := unmarshaler.register.resolve(typ, u.Options); fn != nil {
+		return fn, ctxFn
+	}
+	// fallback to funcs registered for the kind of typ
+	if u.register.initialized() {
+		if fn, ctxFn := u.register.resolveKind(typ); fn != nil {
+			return fn, ctxFn
+		}
+	}
+	return unmarshaler.register.resolveKind(typ)
+}
+
+// destType returns the type of the destination dest. This is the elem type
+// of dest when it is the unaddressable pointer which is passed to Unmarshal,
+// instead of an addressable value of a pointer type, e.g. a struct field.
+func destType(dest reflect.Value) reflect.Type {
+	if dest.Kind() == reflect.Ptr && !dest.CanAddr() && !dest.IsNil() {
+		return dest.Type().Elem()
+	}
+	return dest.Type()
+}
+
+// ptrType returns the pointer type for which the (globally) registered
+// UnmarshalFunc of typ is registered, or nil when it is not registered for a
+// pointer type.
+func (u *Unmarshaler) ptrType(typ reflect.Type) reflect.Type {
+	if typ.Kind() != reflect.Ptr {
+		return nil
+	}
+	if u.register.initialized() && u.register.find(typ) != nil {
+		return u.register.ptrType(typ)
+	}
+	return unmarshaler.register.ptrType(typ)
+}
+
+// exec executes fn with dest. When fn is registered for a pointer type, it
+// receives a pointer to a value of that pointer type, so it can set the
+// pointer itself instead of the value it points to.
+func (u *Unmarshaler) exec(ctx context.Context, fn UnmarshalFuncCtx, v Value, dest reflect.Value) error {
+	ptr := u.ptrType(destType(dest))
+	if ptr == nil {
+		return fn.Exec(ctx, v, dest)
+	}
+
+	for dest.Type() != ptr {
+		if dest.IsNil() {
+			if !dest.CanSet() {
+				return errors.New(ErrUnableToSet)
+			}
+			dest.Set(reflect.New(dest.Type().Elem()))
+		}
+		dest = dest.Elem()
+	}
+	if !dest.CanAddr() {
+		return errors.New(ErrUnableToAddr)
+	}
+	return fn.exec(ctx, v, dest.Addr())
 }
 
 // Unmarshal tries to unmarshal Value to a supported type which matches the
 // type of v, and sets the parsed value to it. See Unmarshal for additional
 // details.
 func (u *Unmarshaler) Unmarshal(val Value, v reflect.Value) error {
+	if err := validDest(v); err != nil {
+		return err
+	}
+	return u.unmarshal(context.Background(), val, v, "", 0)
+}
+
+// UnmarshalAny unmarshals Value to the concrete value held by the interface v
+// points to, e.g. an int within an any, and sets the result to the interface.
+// It returns an UnsupportedTypeError when the interface is nil.
+func (u *Unmarshaler) UnmarshalAny(val Value, v *any) error {
+	if v == nil {
+		return errors.New(ErrNilDestination)
+	}
+	return u.unmarshal(context.Background(), val, reflect.ValueOf(v).Elem(), "", 0)
+}
+
+// unmarshalPtr unmarshals Value to the value pointed to by v, see Unmarshal.
+func (u *Unmarshaler) unmarshalPtr(ctx context.Context, val Value, v any) error {
+	rv, err := ptrDest(v)
+	if err != nil {
+		return err
+	}
+	return u.unmarshal(ctx, val, rv, "", 0)
+}
+
+// validDest validates v can be used as destination by an Unmarshaler.
+func validDest(v reflect.Value) error {
+	if !v.IsValid() {
+		return errors.New(ErrNilDestination)
+	}
 	if v.Kind() != reflect.Ptr && !v.CanSet() {
 		return errors.New(ErrUnableToSet)
 	}
-	return u.unmarshal(val, v, false)
+	if v.Kind() == reflect.Ptr && v.IsNil() && !v.CanSet() {
+		return errors.New(ErrNilDestination)
+	}
+	return nil
+}
+
+// ptrDest returns the reflect.Value of v, which must be a non-nil pointer.
+func ptrDest(v any) (reflect.Value, error) {
+	if v == nil {
+		return reflect.Value{}, errors.New(ErrNilDestination)
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr {
+		return rv, errors.New(ErrPointerExpected)
+	}
+	if rv.IsNil() {
+		return rv, errors.New(ErrNilDestination)
+	}
+	return rv, nil
 }
 
-func (u *Unmarshaler) unmarshal(v Value, dest reflect.Value, nested bool) error {
-	if fn := u.Func(dest.Type()); fn != nil {
-		return fn.Exec(v, dest)
+func (u *Unmarshaler) unmarshal(ctx context.Context, v Value, dest reflect.Value, path string, depth int) error {
+	if err := ctx.Err(); err != nil {
+		return errors.WithStack(err)
+	}
+	if depth == 0 && len(u.middleware) != 0 {
+		return u.unmarshalMiddleware(ctx, v, dest, path)
+	}
+	return u.unmarshalValue(ctx, v, dest, path, depth)
+}
+
+// unmarshalValue verifies, decrypts and converts v to dest, without applying
+// any middleware.
+func (u *Unmarshaler) unmarshalValue(ctx context.Context, v Value, dest reflect.Value, path string, depth int) error {
+	raw := v
+	if depth == 0 && u.Checksum != ChecksumIgnore {
+		var err error
+		if v, err = u.verifyChecksum(v); err != nil {
+			return err
+		}
+	}
+	if depth == 0 && u.Decrypt != nil {
+		var err error
+		if v, err = u.decrypt(v); err != nil {
+			return err
+		}
+	}
+	if err := u.convert(ctx, v, dest, path, depth); err != nil {
+		return parseError(err, raw, dest.Type())
+	}
+	return nil
+}
+
+// convert converts Value v, which is already verified and decrypted, to dest.
+func (u *Unmarshaler) convert(ctx context.Context, v Value, dest reflect.Value, path string, depth int) error {
+	if u.Strict {
+		if err := ambiguousTypeErr(dest.Type(), &u.register, &unmarshaler.register); err != nil {
+			return err
+		}
+	}
+	if len(u.Aliases) != 0 && dest.Kind() != reflect.Interface {
+		v = u.alias(v, dest.Type(), path)
+	}
+	if u.isNull(v) && isNullable(dest.Type()) {
+		return setZero(dest)
+	}
+	if u.NilToken != "" && v.String() == u.NilToken && u.collection(dest.Type()) {
+		return setZero(dest)
+	}
+	if isBlank(v) && u.collection(dest.Type()) {
+		v = ""
+	}
+	if v.IsEmpty() {
+		if u.EmptyCollections != EmptyCollectionDefault && u.collection(dest.Type()) {
+			return u.setEmptyCollection(dest)
+		}
+		if u.EmptyValues != EmptySkip {
+			return u.setEmpty(dest)
+		}
+	}
+	if fn := u.funcCtx(destType(dest)); fn != nil {
+		err := u.exec(ctx, fn, v, dest)
+		if errors.Is(err, ErrSkip) {
+			err = u.execFallbacks(ctx, v, dest)
+		}
+		if !errors.Is(err, ErrSkip) {
+			return err
+		}
 	}
 
 	if v.IsEmpty() {
+		if u.OnSkipEmpty != nil {
+			u.OnSkipEmpty(path, dest.Type())
+		}
 		return nil
 	}
 
@@ -126,22 +363,22 @@ func (u *Unmarshaler) unmarshal(v Value, dest reflect.Value, nested bool) error
 		return nil
 
 	case reflect.Bool:
-		x, err := v.Bool()
+		x, err := u.parseBool(v)
 		dest.SetBool(x)
 		return err
 
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		x, err := intSize(v, dest.Type().Bits())
+		x, err := u.parseInt(v, dest.Type().Bits())
 		dest.SetInt(x)
 		return err
 
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		x, err := uintSize(v, dest.Type().Bits())
+		x, err := u.parseUint(v, dest.Type().Bits())
 		dest.SetUint(x)
 		return err
 
 	case reflect.Float32, reflect.Float64:
-		x, err := floatSize(v, dest.Type().Bits())
+		x, err := u.parseFloat(v, dest.Type().Bits())
 		dest.SetFloat(x)
 		return err
 
@@ -151,41 +388,64 @@ func (u *Unmarshaler) unmarshal(v Value, dest reflect.Value, nested bool) error
 		return err
 
 	case reflect.Array:
-		if nested {
+		if u.isBinary(dest.Type()) {
+			return u.decodeArray(v, dest)
+		}
+		if !u.nestable(depth) {
 			return errors.New(ErrUnmarshalNested)
 		}
 
-		parts := split(v.String(), u.itemSeparator())
+		parts := u.splitItems(v.String(), depth)
 		typ := dest.Type().Elem()
 
+		// unmarshal to a copy so dest is left untouched on failure
+		array := reflect.New(dest.Type()).Elem()
+		array.Set(dest)
+
 		partsLen, arrayLen := len(parts), dest.Len()
 		for i := 0; i < partsLen && i < arrayLen; i++ {
-			part := strings.TrimSpace(parts[i])
 			val := reflect.New(typ).Elem()
-			if err = u.unmarshal(Value(part), val, true); err != nil {
-				return err
+			if err = u.unmarshalElem(ctx, parts[i], val, u.indexPath(path, i), depth+1); err != nil {
+				return &UnmarshalError{Index: i, Err: err}
 			}
-			dest.Index(i).Set(val)
+			array.Index(i).Set(val)
 		}
 		if partsLen > arrayLen {
 			return errors.New(ErrArrayTooManyValues)
 		}
+
+		dest.Set(array)
 		return nil
 
 	case reflect.Slice:
-		if nested {
+		if dest.Type() == jsonRawMessageType {
+			b, err := u.rawJSON(v)
+			if err != nil {
+				return err
+			}
+			dest.SetBytes(b)
+			return nil
+		}
+		if u.isBinary(dest.Type()) {
+			b, err := u.BytesEncoding.Decode(v)
+			if err != nil {
+				return err
+			}
+			dest.SetBytes(b)
+			return nil
+		}
+		if !u.nestable(depth) {
 			return errors.New(ErrUnmarshalNested)
 		}
 
-		parts := split(v.String(), u.itemSeparator())
+		parts := u.splitItems(v.String(), depth)
 		slice := reflect.MakeSlice(dest.Type(), 0, len(parts))
 		typ := dest.Type().Elem()
 
-		for _, part := range parts {
-			part = strings.TrimSpace(part)
+		for i, part := range parts {
 			val := reflect.New(typ).Elem()
-			if err = u.unmarshal(Value(part), val, true); err != nil {
-				return err
+			if err = u.unmarshalElem(ctx, part, val, u.indexPath(path, i), depth+1); err != nil {
+				return &UnmarshalError{Index: i, Err: err}
 			}
 			slice = reflect.Append(slice, val)
 		}
@@ -194,52 +454,196 @@ func (u *Unmarshaler) unmarshal(v Value, dest reflect.Value, nested bool) error
 		return nil
 
 	case reflect.Map:
-		if nested {
+		if !u.nestable(depth) {
 			return errors.New(ErrUnmarshalNested)
 		}
 
-		parts := split(v.String(), u.itemSeparator())
-		if dest.IsNil() {
-			dest.Set(reflect.MakeMapWithSize(dest.Type(), len(parts)))
-		}
-
 		keyTyp := dest.Type().Key()
 		valTyp := dest.Type().Elem()
 
-		for _, part := range parts {
-			kv := strings.SplitN(part, u.keyValueSeparator(), 2)
+		// fail before the map is created or modified when its keys can
+		// never be unmarshaled
+		if u.mechanism(keyTyp, depth+1) == Unsupported {
+			if u.Mechanism(keyTyp) != Unsupported {
+				// the key is a collection which cannot be nested
+				return errors.New(ErrUnmarshalNested)
+			}
+			return errors.WithStack(&UnsupportedTypeError{Type: keyTyp})
+		}
+
+		// the number of parts is already limited by Options.MaxSplit
+		parts := u.splitItems(v.String(), depth)
+		// unmarshal to a new map so dest is left untouched on failure
+		m := reflect.MakeMapWithSize(dest.Type(), len(parts))
+
+		var seen map[any]struct{}
+		if u.OnWarning != nil {
+			seen = make(map[any]struct{}, len(parts))
+		}
+
+		for i, part := range parts {
+			kv := u.splitN(part, u.keyValueSeparator(), 2)
 			if len(kv) != 2 {
-				return errors.New(ErrMapInvalidFormat)
+				if !u.MapKeyOnly {
+					return &UnmarshalError{Index: i, Err: mapFormatErr(part, dest.Type())}
+				}
+				kv = append(kv, u.MapKeyOnlyValue)
 			}
 
+			elemPath := u.keyPath(path, kv[0])
+
 			key := reflect.New(keyTyp).Elem()
-			if err = u.unmarshal(Value(kv[0]), key, true); err != nil {
-				return err
+			if err = u.unmarshal(ctx, u.item(kv[0], keyTyp), key, elemPath, depth+1); err != nil {
+				return &UnmarshalError{Index: i, Key: kv[0], Err: err}
 			}
 			val := reflect.New(valTyp).Elem()
-			if err = u.unmarshal(Value(kv[1]), val, true); err != nil {
-				return err
+			if err = u.unmarshal(ctx, u.item(kv[1], valTyp), val, elemPath, depth+1); err != nil {
+				return &UnmarshalError{Index: i, Key: kv[0], Err: err}
 			}
 
-			dest.SetMapIndex(key, val)
+			if seen != nil {
+				k := key.Interface()
+				if _, ok := seen[k]; ok {
+					u.warn(elemPath, valTyp, errors.New(WarnDuplicateKey))
+				}
+				seen[k] = struct{}{}
+			}
+			m.SetMapIndex(key, val)
 		}
+
+		if dest.IsNil() {
+			dest.Set(m)
+			return nil
+		}
+		for iter := m.MapRange(); iter.Next(); {
+			dest.SetMapIndex(iter.Key(), iter.Value())
+		}
+		return nil
+
+	case reflect.Struct:
+		if !isNullable(dest.Type()) {
+			break
+		}
+		if err = u.convert(ctx, v, dest.Field(0), path, depth); err != nil {
+			return err
+		}
+		dest.Field(1).SetBool(true)
 		return nil
 
+	case reflect.Interface:
+		if dest.IsNil() {
+			break
+		}
+
+		// the concrete value of an interface cannot be set, instead a copy
+		// of it is modified and set to the interface
+		elem := dest.Elem()
+		cp := reflect.New(elem.Type()).Elem()
+		cp.Set(elem)
+		if err = u.convert(ctx, v, cp, path, depth); err != nil {
+			return err
+		}
+		dest.Set(cp)
+		return nil
+	}
+	return errors.WithStack(&UnsupportedTypeError{Type: ot})
+}
+
+// UnmarshalElem unmarshals val to v as if it is an item of a top level array
+// or slice. This means val is trimmed of leading and trailing whitespace, is
+// unquoted according to Options.Quote and Options.Escape, and can only be
+// unmarshaled to a nested collection when this is enabled with
+// Options.NestedBrackets or Options.NestedSeparators. Registered funcs apply
+// as usual. It allows funcs which unmarshal custom collection types to
+// handle their items the same way as the builtin array and slice handling.
+func (u *Unmarshaler) UnmarshalElem(val Value, v reflect.Value) error {
+	if err := validDest(v); err != nil {
+		return err
+	}
+	return u.unmarshalElem(context.Background(), val.String(), v, "", 1)
+}
+
+// unmarshalElem unmarshals the raw item str, of a collection at depth-1, to
+// dest.
+func (u *Unmarshaler) unmarshalElem(ctx context.Context, str string, dest reflect.Value, path string, depth int) error {
+	return u.unmarshal(ctx, u.item(strings.TrimSpace(str), dest.Type()), dest, path, depth)
+}
+
+// item returns the Value of an item of a collection, which is unmarshaled to
+// typ. The item is unquoted, unless typ is a collection itself.
+func (u *Unmarshaler) item(str string, typ reflect.Type) Value {
+	if (!u.Quote && !u.Escape) || u.collection(typ) {
+		return Value(str)
+	}
+	return Value(u.unquote(str))
+}
+
+// collection indicates if typ is an array, slice or map which has no
+// registered UnmarshalFunc and is not unmarshaled as raw or binary data.
+func (u *Unmarshaler) collection(typ reflect.Type) bool {
+	if u.Func(typ) != nil {
+		return false
+	}
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	switch typ.Kind() {
+	case reflect.Array, reflect.Slice:
+		return typ != jsonRawMessageType && !u.isBinary(typ)
+	case reflect.Map:
+		return true
 	default:
-		return errors.WithStack(&UnsupportedTypeError{Type: ot})
+		return false
 	}
 }
 
+// indexPath returns the path of the item at index i, within the collection at
+// path. It is only computed when Options.OnSkipEmpty or Options.OnWarning is
+// set.
+func (u *Unmarshaler) indexPath(path string, i int) string {
+	if u.OnSkipEmpty == nil && u.OnWarning == nil {
+		return ""
+	}
+	return path + "[" + strconv.Itoa(i) + "]"
+}
+
+// keyPath returns the path of the item with key, within the collection at
+// path. It is only computed when Options.OnSkipEmpty or Options.OnWarning is
+// set.
+func (u *Unmarshaler) keyPath(path, key string) string {
+	if u.OnSkipEmpty == nil && u.OnWarning == nil {
+		return ""
+	}
+	return path + "[" + key + "]"
+}
+
 // Exec executes the UnmarshalFunc by taking the address of dest, and passing it
 // as an interface to UnmarshalFunc. It will return an error when the address of
 // reflect.Value dest cannot be taken, or when it is unable to set.
 // Any error returned by UnmarshalFunc is wrapped with ErrParseFailure.
 func (fn UnmarshalFunc) Exec(v Value, dest reflect.Value) error {
+	return fn.ctx().Exec(context.Background(), v, dest)
+}
+
+// ctx returns fn as an UnmarshalFuncCtx which ignores its context.
+func (fn UnmarshalFunc) ctx() UnmarshalFuncCtx {
+	if fn == nil {
+		return nil
+	}
+	return func(_ context.Context, val Value, dest any) error {
+		return fn(val, dest)
+	}
+}
+
+// Exec executes the UnmarshalFuncCtx with ctx, see UnmarshalFunc.Exec for
+// details.
+func (fn UnmarshalFuncCtx) Exec(ctx context.Context, v Value, dest reflect.Value) error {
 	if dest.Kind() != reflect.Ptr {
 		if !dest.CanAddr() {
 			return errors.New(ErrUnableToAddr)
 		}
-		return fn.exec(v, dest.Addr())
+		return fn.exec(ctx, v, dest.Addr())
 	}
 
 	var err error
@@ -252,11 +656,11 @@ func (fn UnmarshalFunc) Exec(v Value, dest reflect.Value) error {
 		}
 	}
 
-	return fn.exec(v, dest)
+	return fn.exec(ctx, v, dest)
 }
 
-func (fn UnmarshalFunc) exec(val Value, dest reflect.Value) error {
-	if err := fn(val, dest.Interface()); err != nil {
+func (fn UnmarshalFuncCtx) exec(ctx context.Context, val Value, dest reflect.Value) error {
+	if err := fn(ctx, val, dest.Interface()); err != nil {
 		return errors.Wrap(err, ErrUnmarshalFuncExec)
 	}
 	return nil
@@ -274,6 +678,27 @@ func value(rv reflect.Value) (reflect.Value, error) {
 	return rv, nil
 }
 
-func split(str, sep string) []string {
-	return strings.Split(str, sep)
+// splitItems splits str into the items of a collection at depth. When
+// Options.NestedBrackets is set, the enclosing brackets of a nested
+// collection are removed. The number of items is limited by
+// Options.MaxSplit. See Options.splitN for details.
+func (u *Unmarshaler) splitItems(str string, depth int) []string {
+	sep, n := u.itemSeparatorAt(depth), -1
+	if u.MaxSplit > 0 {
+		n = u.MaxSplit
+	}
+	if !u.NestedBrackets {
+		return u.splitN(str, sep, n)
+	}
+
+	if depth > 0 {
+		str = strings.TrimSpace(str)
+		if len(str) >= 2 && str[0] == '[' && str[len(str)-1] == ']' {
+			str = str[1 : len(str)-1]
+			if str == "" {
+				return nil
+			}
+		}
+	}
+	return u.splitN(str, sep, n)
 }
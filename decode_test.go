@@ -5,27 +5,40 @@
 package rawconv
 
 import (
+	"math"
+	"math/big"
 	"net"
+	"net/netip"
 	"net/url"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/go-pogo/errors"
 	"github.com/stretchr/testify/assert"
 )
 
 func ptr[T any](v T) *T { return &v }
 
+func bigInt(s string) *big.Int {
+	x, _ := new(big.Int).SetString(s, 10)
+	return x
+}
+
 func TestUnmarshal(t *testing.T) {
-	tests := map[string]any{
-		"nil":           nil,
-		"not a pointer": struct{}{},
-		"nil pointer":   (*url.URL)(nil),
+	tests := map[string]struct {
+		dest    any
+		wantErr error
+	}{
+		"nil":           {dest: nil, wantErr: ErrNilDestination},
+		"not a pointer": {dest: struct{}{}, wantErr: ErrPointerExpected},
+		"nil pointer":   {dest: (*url.URL)(nil), wantErr: ErrNilDestination},
 	}
-	for name, val := range tests {
+	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			err := Unmarshal("", val)
-			assert.ErrorIs(t, err, ErrPointerExpected)
+			err := Unmarshal("", tc.dest)
+			assert.ErrorIs(t, err, tc.wantErr)
 		})
 	}
 }
@@ -102,6 +115,16 @@ func TestUnmarshaler_Unmarshal(t *testing.T) {
 		}, {
 			input: "1997-08-29T13:37:00Z",
 			want:  ptr(timeVal),
+		}, {
+			input: "1997-08-29",
+			want:  time.Date(1997, 8, 29, 0, 0, 0, 0, time.UTC),
+		}, {
+			input: "29 Aug 1997",
+			want:  time.Date(1997, 8, 29, 0, 0, 0, 0, time.UTC),
+		}, {
+			input:   "29/08/1997",
+			want:    time.Time{},
+			wantErr: ErrParseFailure,
 		}},
 		"url": {{
 			input: "http://localhost/",
@@ -114,6 +137,34 @@ func TestUnmarshaler_Unmarshal(t *testing.T) {
 			input: "192.168.1.1",
 			want:  net.IPv4(192, 168, 1, 1),
 		}},
+		"big": {{
+			input: "123456789012345678901234567890",
+			want:  bigInt("123456789012345678901234567890"),
+		}, {
+			input: "0xff",
+			want:  *big.NewInt(255),
+		}, {
+			input: "3/4",
+			want:  big.NewRat(3, 4),
+		}, {
+			input:   "12.3",
+			want:    big.Int{},
+			wantErr: ErrParseFailure,
+		}},
+		"netip": {{
+			input: "10.0.0.1",
+			want:  netip.MustParseAddr("10.0.0.1"),
+		}, {
+			input: "[::1]:8080",
+			want:  netip.MustParseAddrPort("[::1]:8080"),
+		}, {
+			input: "10.0.0.0/8",
+			want:  ptr(netip.MustParsePrefix("10.0.0.0/8")),
+		}, {
+			input:   "10.0.0.1",
+			want:    netip.AddrPort{},
+			wantErr: ErrParseFailure,
+		}},
 		"array": {{
 			input: "1,2,3",
 			want:  [3]int{1, 2, 3},
@@ -122,7 +173,7 @@ func TestUnmarshaler_Unmarshal(t *testing.T) {
 			want:  [3]string{"1", "2", "3"},
 		}, {
 			input:   "1,2,3",
-			want:    [1]int{1},
+			want:    [1]int{},
 			wantErr: ErrArrayTooManyValues,
 		}, {
 			input:   "1,2,3",
@@ -145,11 +196,11 @@ func TestUnmarshaler_Unmarshal(t *testing.T) {
 			want:  map[string]string{"key1": "value1", "key2": "value2"},
 		}, {
 			input:   "iets",
-			want:    map[string]map[string]string{},
+			want:    (map[string]map[string]string)(nil),
 			wantErr: ErrMapInvalidFormat,
 		}, {
 			input:   "iets=something",
-			want:    map[string]map[string]string{},
+			want:    (map[string]map[string]string)(nil),
 			wantErr: ErrUnmarshalNested,
 		}},
 	}
@@ -176,6 +227,14 @@ func TestUnmarshaler_Unmarshal(t *testing.T) {
 		haveErr := unmarshaler.Unmarshal("some value", reflect.ValueOf("some value"))
 		assert.ErrorIs(t, haveErr, ErrUnableToSet)
 	})
+	t.Run("invalid", func(t *testing.T) {
+		haveErr := unmarshaler.Unmarshal("some value", reflect.ValueOf(nil))
+		assert.ErrorIs(t, haveErr, ErrNilDestination)
+	})
+	t.Run("nil pointer", func(t *testing.T) {
+		haveErr := unmarshaler.Unmarshal("some value", reflect.ValueOf((*string)(nil)))
+		assert.ErrorIs(t, haveErr, ErrNilDestination)
+	})
 }
 
 func TestParseFunc_Exec(t *testing.T) {
@@ -208,3 +267,806 @@ func TestParseFunc_Exec(t *testing.T) {
 		assert.Equal(t, time.Second*10, slice[0])
 	})
 }
+
+func TestUnmarshaler_Unmarshal_pointers(t *testing.T) {
+	urlPtr, _ := url.ParseRequestURI("http://localhost/")
+	tests := map[string]struct {
+		input Value
+		want  any
+	}{
+		"string":   {input: "foobar", want: "foobar"},
+		"rune":     {input: "a", want: 'a'},
+		"bool":     {input: "true", want: true},
+		"int":      {input: "-10", want: int8(-10)},
+		"uint":     {input: "1337", want: uint16(1337)},
+		"float":    {input: "3.14", want: 3.14},
+		"complex":  {input: "(3.14+2.72i)", want: complex(3.14, 2.72)},
+		"duration": {input: "10s", want: time.Second * 10},
+		"url":      {input: "http://localhost/", want: *urlPtr},
+		"ip":       {input: "192.168.1.1", want: net.IPv4(192, 168, 1, 1)},
+		"array":    {input: "1,2,3", want: [3]int{1, 2, 3}},
+		"slice":    {input: "1,2,3", want: []uint{1, 2, 3}},
+		"map":      {input: "a=1", want: map[string]int{"a": 1}},
+	}
+
+	for name, tc := range tests {
+		for depth := 1; depth <= 3; depth++ {
+			rt := reflect.TypeOf(tc.want)
+			for i := 0; i < depth; i++ {
+				rt = reflect.New(rt).Type()
+			}
+
+			t.Run(name+"/"+rt.String(), func(t *testing.T) {
+				rv := reflect.New(rt)
+				assert.NoError(t, unmarshaler.Unmarshal(tc.input, rv))
+
+				have := rv.Elem()
+				for have.Kind() == reflect.Ptr {
+					assert.False(t, have.IsNil())
+					have = have.Elem()
+				}
+				assert.Equal(t, tc.want, have.Interface())
+
+				val, err := Marshal(rv.Elem().Interface())
+				assert.NoError(t, err)
+				assert.Equal(t, tc.input, val)
+			})
+		}
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		var have ***int
+		assert.NoError(t, Unmarshal("", &have))
+		assert.Nil(t, have)
+	})
+	t.Run("reuse", func(t *testing.T) {
+		var x int
+		p := &x
+		pp := &p
+		assert.NoError(t, Unmarshal("42", &pp))
+		assert.Same(t, &x, *pp)
+		assert.Equal(t, 42, x)
+	})
+}
+
+func TestUnmarshaler_OnSkipEmpty(t *testing.T) {
+	type skipped struct {
+		path string
+		typ  reflect.Type
+	}
+
+	var have []skipped
+	var u Unmarshaler
+	u.OnSkipEmpty = func(path string, typ reflect.Type) {
+		have = append(have, skipped{path: path, typ: typ})
+	}
+
+	var list []int
+	assert.NoError(t, u.Unmarshal("1,,3", reflect.ValueOf(&list)))
+	assert.Equal(t, []int{1, 0, 3}, list)
+
+	var m map[string]float64
+	assert.NoError(t, u.Unmarshal("a=1.5,b=", reflect.ValueOf(&m)))
+	assert.Equal(t, map[string]float64{"a": 1.5, "b": 0}, m)
+
+	var x *int
+	assert.NoError(t, u.Unmarshal("", reflect.ValueOf(&x)))
+	assert.Nil(t, x)
+
+	assert.Equal(t, []skipped{
+		{path: "[1]", typ: reflect.TypeOf(0)},
+		{path: "[b]", typ: reflect.TypeOf(0.0)},
+		{path: "", typ: reflect.TypeOf(&x)},
+	}, have)
+}
+
+func TestUnmarshaler_TimeLayout(t *testing.T) {
+	want := time.Date(1997, 8, 29, 13, 37, 0, 0, time.UTC)
+
+	t.Run("layout", func(t *testing.T) {
+		var u Unmarshaler
+		u.TimeLayout = "02/01/2006 15:04"
+
+		var have time.Time
+		assert.NoError(t, u.Unmarshal("29/08/1997 13:37", reflect.ValueOf(&have)))
+		assert.Equal(t, want, have)
+		assert.NoError(t, u.Unmarshal("1997-08-29T13:37:00Z", reflect.ValueOf(&have)))
+		assert.Equal(t, want, have)
+	})
+	t.Run("layouts", func(t *testing.T) {
+		var u Unmarshaler
+		u.TimeLayouts = []string{"02/01/2006 15:04"}
+
+		var have time.Time
+		assert.NoError(t, u.Unmarshal("29/08/1997 13:37", reflect.ValueOf(&have)))
+		assert.Equal(t, want, have)
+		assert.ErrorIs(t, u.Unmarshal("1997-08-29T13:37:00Z", reflect.ValueOf(&have)), ErrParseFailure)
+	})
+	t.Run("global", func(t *testing.T) {
+		var have time.Time
+		assert.ErrorIs(t, Unmarshal("29/08/1997 13:37", &have), ErrParseFailure)
+	})
+	t.Run("epoch seconds", func(t *testing.T) {
+		var u Unmarshaler
+		u.TimeEpoch = EpochSeconds
+
+		var have []time.Time
+		assert.NoError(t, u.Unmarshal("872861820, 1997-08-29T13:37:00Z", reflect.ValueOf(&have)))
+		assert.Equal(t, []time.Time{want, want}, have)
+	})
+	t.Run("epoch millis", func(t *testing.T) {
+		var u Unmarshaler
+		u.TimeEpoch = EpochMillis
+
+		var have time.Time
+		assert.NoError(t, u.Unmarshal("872861820000", reflect.ValueOf(&have)))
+		assert.Equal(t, want, have)
+	})
+	t.Run("epoch disabled", func(t *testing.T) {
+		var have time.Time
+		assert.ErrorIs(t, Unmarshal("872861820", &have), ErrParseFailure)
+	})
+}
+
+func TestUnmarshaler_IntBase(t *testing.T) {
+	var u Unmarshaler
+	u.IntBase = 16
+
+	var i int16
+	assert.NoError(t, u.Unmarshal("-7f", reflect.ValueOf(&i)))
+	assert.Equal(t, int16(-127), i)
+
+	var have []uint32
+	assert.NoError(t, u.Unmarshal("ff,1A,0", reflect.ValueOf(&have)))
+	assert.Equal(t, []uint32{255, 26, 0}, have)
+
+	assert.ErrorIs(t, u.Unmarshal("0xff", reflect.ValueOf(&i)), ErrParseFailure)
+	assert.ErrorIs(t, u.Unmarshal("fffff", reflect.ValueOf(&i)), ErrValidationFailure)
+
+	t.Run("default", func(t *testing.T) {
+		var u Unmarshaler
+		assert.NoError(t, u.Unmarshal("0xff", reflect.ValueOf(&i)))
+		assert.Equal(t, int16(255), i)
+		assert.ErrorIs(t, u.Unmarshal("ff", reflect.ValueOf(&i)), ErrParseFailure)
+	})
+}
+
+func TestUnmarshaler_MaxSplit(t *testing.T) {
+	tests := map[string]struct {
+		opts  Options
+		input Value
+		want  any
+	}{
+		"slice": {
+			opts:  Options{ItemsSeparator: ":", MaxSplit: 2},
+			input: "key:value:with:colons",
+			want:  []string{"key", "value:with:colons"},
+		},
+		"array": {
+			opts:  Options{ItemsSeparator: ":", MaxSplit: 2},
+			input: "http:localhost:8080",
+			want:  [2]string{"http", "localhost:8080"},
+		},
+		"map": {
+			opts:  Options{MaxSplit: 2},
+			input: "a=1,b=2,c=3",
+			want:  map[string]string{"a": "1", "b": "2,c=3"},
+		},
+		"less items": {
+			opts:  Options{MaxSplit: 3},
+			input: "1,2",
+			want:  []int{1, 2},
+		},
+		"quoted": {
+			opts:  Options{Quote: true, MaxSplit: 2},
+			input: `"a,b",c,d`,
+			want:  []string{"a,b", "c,d"},
+		},
+		"no limit": {
+			opts:  Options{MaxSplit: -1},
+			input: "1,2,3",
+			want:  []int{1, 2, 3},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			u := Unmarshaler{Options: tc.opts}
+			rv := reflect.New(reflect.TypeOf(tc.want))
+			assert.NoError(t, u.Unmarshal(tc.input, rv))
+			assert.Equal(t, tc.want, rv.Elem().Interface())
+		})
+	}
+
+	t.Run("reader", func(t *testing.T) {
+		u := Unmarshaler{Options: Options{MaxSplit: 2}}
+		var have []string
+		assert.NoError(t, u.UnmarshalReader(strings.NewReader("a,b,c"), reflect.ValueOf(&have)))
+		assert.Equal(t, []string{"a", "b,c"}, have)
+	})
+}
+
+func TestUnmarshaler_MapKeyOnly(t *testing.T) {
+	tests := map[string]struct {
+		opts    Options
+		input   Value
+		want    any
+		wantErr error
+	}{
+		"disabled": {
+			input:   "a,b=2,c",
+			want:    map[string]string{},
+			wantErr: ErrMapInvalidFormat,
+		},
+		"empty default": {
+			opts:  Options{MapKeyOnly: true},
+			input: "a,b=2,c",
+			want:  map[string]string{"a": "", "b": "2", "c": ""},
+		},
+		"default value": {
+			opts:  Options{MapKeyOnly: true, MapKeyOnlyValue: "true"},
+			input: "debug,verbose=false",
+			want:  map[string]bool{"debug": true, "verbose": false},
+		},
+		"invalid default value": {
+			opts:    Options{MapKeyOnly: true, MapKeyOnlyValue: "x"},
+			input:   "a",
+			want:    map[string]int{},
+			wantErr: ErrParseFailure,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			u := Unmarshaler{Options: tc.opts}
+			rv := reflect.New(reflect.TypeOf(tc.want))
+			haveErr := u.Unmarshal(tc.input, rv)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, haveErr, tc.wantErr)
+				return
+			}
+			assert.NoError(t, haveErr)
+			assert.Equal(t, tc.want, rv.Elem().Interface())
+		})
+	}
+}
+
+func TestOptions_ExtendedDuration(t *testing.T) {
+	t.Run("unmarshal", func(t *testing.T) {
+		tests := map[string]struct {
+			opts    Options
+			input   Value
+			want    time.Duration
+			wantErr error
+		}{
+			"disabled": {
+				input:   "2d",
+				wantErr: ErrParseFailure,
+			},
+			"extended": {
+				opts:  Options{ExtendedDuration: true},
+				input: "1w2d",
+				want:  9 * 24 * time.Hour,
+			},
+			"unit": {
+				opts:  Options{DurationUnit: time.Second},
+				input: "30",
+				want:  30 * time.Second,
+			},
+			"unit with explicit unit": {
+				opts:  Options{DurationUnit: time.Second},
+				input: "30m",
+				want:  30 * time.Minute,
+			},
+			"unit overflow": {
+				opts:    Options{DurationUnit: time.Hour},
+				input:   "9223372036854775807",
+				wantErr: ErrValidationFailure,
+			},
+		}
+		for name, tc := range tests {
+			t.Run(name, func(t *testing.T) {
+				u := Unmarshaler{Options: tc.opts}
+				var have []time.Duration
+				haveErr := u.Unmarshal(tc.input, reflect.ValueOf(&have))
+				if tc.wantErr != nil {
+					assert.ErrorIs(t, haveErr, tc.wantErr)
+					return
+				}
+				assert.NoError(t, haveErr)
+				assert.Equal(t, []time.Duration{tc.want}, have)
+			})
+		}
+	})
+	t.Run("marshal", func(t *testing.T) {
+		input := []time.Duration{36 * time.Hour, time.Minute}
+
+		m := Marshaler{Options: Options{ExtendedDuration: true}}
+		have, err := m.Marshal(reflect.ValueOf(input))
+		assert.NoError(t, err)
+		assert.Equal(t, Value("1d12h0m0s,1m0s"), have)
+
+		have, err = marshaler.Marshal(reflect.ValueOf(input))
+		assert.NoError(t, err)
+		assert.Equal(t, Value("36h0m0s,1m0s"), have)
+	})
+}
+
+func TestUnmarshaler_OnOverflow(t *testing.T) {
+	tests := map[string]struct {
+		input Value
+		want  map[OverflowMode]any
+	}{
+		"int8 max": {
+			input: "300",
+			want:  map[OverflowMode]any{OverflowClamp: int8(127), OverflowTruncate: int8(44)},
+		},
+		"int8 min": {
+			input: "-300",
+			want:  map[OverflowMode]any{OverflowClamp: int8(-128), OverflowTruncate: int8(-44)},
+		},
+		"uint16": {
+			input: "65537",
+			want:  map[OverflowMode]any{OverflowClamp: uint16(65535), OverflowTruncate: uint16(1)},
+		},
+		"float32": {
+			input: "1e39",
+			want:  map[OverflowMode]any{OverflowClamp: float32(math.MaxFloat32), OverflowTruncate: float32(math.Inf(1))},
+		},
+		"float64": {
+			input: "-1e309",
+			want:  map[OverflowMode]any{OverflowClamp: -math.MaxFloat64, OverflowTruncate: nil},
+		},
+		"int64": {
+			input: "9223372036854775808",
+			want:  map[OverflowMode]any{OverflowClamp: int64(math.MaxInt64), OverflowTruncate: nil},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			typ := reflect.TypeOf(tc.want[OverflowClamp])
+			for _, mode := range []OverflowMode{OverflowError, OverflowClamp, OverflowTruncate} {
+				u := Unmarshaler{Options: Options{OnOverflow: mode}}
+				rv := reflect.New(typ)
+				haveErr := u.Unmarshal(tc.input, rv)
+
+				want, ok := tc.want[mode]
+				if !ok || want == nil {
+					assert.ErrorIs(t, haveErr, ErrValidationFailure, "mode %d", mode)
+					continue
+				}
+				assert.NoError(t, haveErr, "mode %d", mode)
+				assert.Equal(t, want, rv.Elem().Interface(), "mode %d", mode)
+			}
+		})
+	}
+
+	t.Run("syntax error", func(t *testing.T) {
+		u := Unmarshaler{Options: Options{OnOverflow: OverflowClamp}}
+		var have float32
+		assert.ErrorIs(t, u.Unmarshal("x", reflect.ValueOf(&have)), ErrParseFailure)
+	})
+}
+
+func TestUnmarshaler_Unmarshal_nested(t *testing.T) {
+	brackets := Options{NestedBrackets: true}
+	separators := Options{NestedSeparators: []string{"|", "/"}}
+
+	tests := map[string]struct {
+		opts    Options
+		input   Value
+		want    any
+		wantErr error
+	}{
+		"brackets slice of slices": {
+			opts:  brackets,
+			input: "[1,2],[3], [4, 5,6]",
+			want:  [][]int{{1, 2}, {3}, {4, 5, 6}},
+		},
+		"brackets empty nested slice": {
+			opts:  brackets,
+			input: "[],[1]",
+			want:  [][]int{{}, {1}},
+		},
+		"brackets without brackets": {
+			opts:  brackets,
+			input: "1,2",
+			want:  [][]int{{1}, {2}},
+		},
+		"brackets deeply nested": {
+			opts:  brackets,
+			input: "[[1,2],[3]],[[4]]",
+			want:  [][][]int{{{1, 2}, {3}}, {{4}}},
+		},
+		"brackets array of arrays": {
+			opts:  brackets,
+			input: "[1,2],[3,4]",
+			want:  [2][2]int{{1, 2}, {3, 4}},
+		},
+		"brackets map of slices": {
+			opts:  brackets,
+			input: "a=[1,2],b=[3]",
+			want:  map[string][]int{"a": {1, 2}, "b": {3}},
+		},
+		"brackets slice of maps": {
+			opts:  brackets,
+			input: "[a=1,b=2],[c=3]",
+			want:  []map[string]int{{"a": 1, "b": 2}, {"c": 3}},
+		},
+		"separators slice of slices": {
+			opts:  separators,
+			input: "1|2,3,4|5|6",
+			want:  [][]int{{1, 2}, {3}, {4, 5, 6}},
+		},
+		"separators deeply nested": {
+			opts:  separators,
+			input: "1/2|3,4",
+			want:  [][][]int{{{1, 2}, {3}}, {{4}}},
+		},
+		"separators map of slices": {
+			opts:  separators,
+			input: "a=1|2,b=3",
+			want:  map[string][]int{"a": {1, 2}, "b": {3}},
+		},
+		"separators too deep": {
+			opts:    Options{NestedSeparators: []string{"|"}},
+			input:   "1",
+			want:    [][][]int{},
+			wantErr: ErrUnmarshalNested,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			u := Unmarshaler{Options: tc.opts}
+			rv := reflect.New(reflect.TypeOf(tc.want))
+
+			haveErr := u.Unmarshal(tc.input, rv)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, haveErr, tc.wantErr)
+				return
+			}
+
+			assert.NoError(t, haveErr)
+			assert.Equal(t, tc.want, rv.Elem().Interface())
+		})
+	}
+}
+
+func TestUnmarshaler_UnmarshalElem(t *testing.T) {
+	t.Run("trim and unquote", func(t *testing.T) {
+		u := Unmarshaler{Options: Options{Quote: true}}
+		var have string
+		assert.NoError(t, u.UnmarshalElem(` "a,b" `, reflect.ValueOf(&have)))
+		assert.Equal(t, "a,b", have)
+	})
+	t.Run("nested not allowed", func(t *testing.T) {
+		var u Unmarshaler
+		var have []int
+		assert.ErrorIs(t, u.UnmarshalElem("1,2", reflect.ValueOf(&have)), ErrUnmarshalNested)
+	})
+	t.Run("nested brackets", func(t *testing.T) {
+		u := Unmarshaler{Options: Options{NestedBrackets: true}}
+		var have []int
+		assert.NoError(t, u.UnmarshalElem("[1, 2]", reflect.ValueOf(&have)))
+		assert.Equal(t, []int{1, 2}, have)
+	})
+	t.Run("custom collection", func(t *testing.T) {
+		type set map[int]struct{}
+
+		var u Unmarshaler
+		u.Register(reflect.TypeOf(set{}), func(val Value, dest any) error {
+			s := make(set)
+			for _, part := range strings.Split(val.String(), ";") {
+				var i int
+				if err := u.UnmarshalElem(Value(part), reflect.ValueOf(&i)); err != nil {
+					return err
+				}
+				s[i] = struct{}{}
+			}
+			*dest.(*set) = s
+			return nil
+		})
+
+		var have set
+		assert.NoError(t, u.Unmarshal("1; 2 ;3", reflect.ValueOf(&have)))
+		assert.Equal(t, set{1: {}, 2: {}, 3: {}}, have)
+	})
+	t.Run("invalid dest", func(t *testing.T) {
+		var u Unmarshaler
+		var have int
+		assert.ErrorIs(t, u.UnmarshalElem("1", reflect.ValueOf(have)), ErrUnableToSet)
+	})
+}
+
+func TestUnmarshalError(t *testing.T) {
+	tests := map[string]struct {
+		input   Value
+		want    any
+		wantErr *UnmarshalError
+		wantMsg string
+	}{
+		"slice": {
+			input:   "1,2,x",
+			want:    []int{},
+			wantErr: &UnmarshalError{Index: 2},
+			wantMsg: "index 2: ",
+		},
+		"array": {
+			input:   "x,2",
+			want:    [2]int{},
+			wantErr: &UnmarshalError{Index: 0},
+			wantMsg: "index 0: ",
+		},
+		"map key": {
+			input:   "1=a,x=b",
+			want:    map[int]string{},
+			wantErr: &UnmarshalError{Index: 1, Key: "x"},
+			wantMsg: "key `x`: ",
+		},
+		"map value": {
+			input:   "a=1,b=x",
+			want:    map[string]int{},
+			wantErr: &UnmarshalError{Index: 1, Key: "b"},
+			wantMsg: "key `b`: ",
+		},
+		"map format": {
+			input:   "a=1,b",
+			want:    map[string]int{},
+			wantErr: &UnmarshalError{Index: 1},
+			wantMsg: "index 1: ",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			haveErr := Unmarshal(tc.input, reflect.New(reflect.TypeOf(tc.want)).Interface())
+
+			var ue *UnmarshalError
+			if assert.ErrorAs(t, haveErr, &ue) {
+				assert.Equal(t, tc.wantErr.Index, ue.Index)
+				assert.Equal(t, tc.wantErr.Key, ue.Key)
+				assert.NotNil(t, ue.Err)
+				assert.Contains(t, ue.Error(), tc.wantMsg)
+			}
+		})
+	}
+
+	t.Run("nested", func(t *testing.T) {
+		u := Unmarshaler{Options: Options{NestedBrackets: true}}
+		var have [][]int
+		haveErr := u.Unmarshal("[1],[2,x]", reflect.ValueOf(&have))
+
+		var ue *UnmarshalError
+		if assert.ErrorAs(t, haveErr, &ue) {
+			assert.Equal(t, 1, ue.Index)
+			assert.ErrorAs(t, ue.Err, &ue)
+			assert.Equal(t, 1, ue.Index)
+		}
+		assert.ErrorIs(t, haveErr, ErrParseFailure)
+	})
+}
+
+func TestParseError(t *testing.T) {
+	t.Run("leaf", func(t *testing.T) {
+		var have int
+		haveErr := Unmarshal("abc", &have)
+		assert.ErrorIs(t, haveErr, ErrParseFailure)
+
+		var pe *ParseError
+		if assert.ErrorAs(t, haveErr, &pe) {
+			assert.Equal(t, Value("abc"), pe.Value)
+			assert.Equal(t, reflect.TypeOf(0), pe.Type)
+			assert.ErrorIs(t, pe.Err, ErrParseFailure)
+			assert.True(t, strings.HasPrefix(pe.Error(), `cannot parse "abc" as int: `))
+		}
+	})
+	t.Run("validation", func(t *testing.T) {
+		var have uint8
+		var pe *ParseError
+		haveErr := Unmarshal("256", &have)
+		assert.ErrorIs(t, haveErr, ErrValidationFailure)
+		assert.ErrorAs(t, haveErr, &pe)
+	})
+	t.Run("item", func(t *testing.T) {
+		var have []time.Duration
+		haveErr := Unmarshal("1s,x", &have)
+
+		var pe *ParseError
+		if assert.ErrorAs(t, haveErr, &pe) {
+			assert.Equal(t, Value("x"), pe.Value)
+			assert.Equal(t, reflect.TypeOf(time.Second), pe.Type)
+		}
+		var ue *UnmarshalError
+		assert.ErrorAs(t, haveErr, &ue)
+	})
+	t.Run("field", func(t *testing.T) {
+		var have struct{ Timeout time.Duration }
+		haveErr := UnmarshalStruct(map[string]Value{"Timeout": "abc"}, &have)
+		assert.ErrorContains(t, haveErr, "field `Timeout`")
+
+		var pe *ParseError
+		if assert.ErrorAs(t, haveErr, &pe) {
+			assert.ErrorContains(t, pe, `cannot parse "abc" as time.Duration`)
+		}
+		var fe *FieldError
+		if assert.ErrorAs(t, haveErr, &fe) {
+			assert.Equal(t, "Timeout", fe.Field)
+		}
+	})
+	t.Run("map item", func(t *testing.T) {
+		var have map[string]int
+		haveErr := Unmarshal("a=1,b=2,oops,d=4", &have)
+		assert.ErrorIs(t, haveErr, ErrMapInvalidFormat)
+
+		var ue *UnmarshalError
+		if assert.ErrorAs(t, haveErr, &ue) {
+			assert.Equal(t, 2, ue.Index)
+			assert.Equal(t, "index 2: cannot parse \"oops\" as map[string]int: invalid map format", ue.Error())
+		}
+
+		_, haveErr = Value("a=1, b").Map("", "")
+		var pe *ParseError
+		if assert.ErrorAs(t, haveErr, &pe) {
+			assert.Equal(t, Value("b"), pe.Value)
+		}
+	})
+	t.Run("other errors", func(t *testing.T) {
+		var have int
+		assert.False(t, errors.As(Unmarshal("1", have), new(*ParseError)))
+	})
+}
+
+func TestErrorToJSON(t *testing.T) {
+	assert.Nil(t, ErrorToJSON(nil))
+
+	tests := map[string]struct {
+		err  func() error
+		want string
+	}{
+		"leaf": {
+			err: func() error {
+				var have int
+				return Unmarshal("abc", &have)
+			},
+			want: `{"value":"abc","type":"int","reason":"invalid syntax","position":""}`,
+		},
+		"field item": {
+			err: func() error {
+				var have struct {
+					Limits map[string]uint8 `raw:"limits"`
+				}
+				return UnmarshalStruct(map[string]Value{"limits": "a=1,b=300"}, &have)
+			},
+			want: `{"value":"300","type":"uint8","reason":"value out of range","position":"limits[b]"}`,
+		},
+		"unsupported": {
+			err: func() error {
+				var have chan int
+				return Unmarshal("x", &have)
+			},
+			want: `{"value":"","type":"*chan int","reason":"type ` + "`*chan int`" + ` is not supported","position":""}`,
+		},
+		"other": {
+			err: func() error {
+				return errors.New(ErrPointerExpected)
+			},
+			want: `{"value":"","type":"","reason":"` + string(ErrPointerExpected) + `","position":""}`,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.JSONEq(t, tc.want, string(ErrorToJSON(tc.err())))
+		})
+	}
+}
+
+func TestUnmarshalAny(t *testing.T) {
+	tests := map[string]struct {
+		input any
+		val   Value
+		want  any
+	}{
+		"int":      {input: 0, val: "8", want: 8},
+		"uint16":   {input: uint16(1), val: "8080", want: uint16(8080)},
+		"duration": {input: time.Duration(0), val: "5s", want: 5 * time.Second},
+		"slice":    {input: []string{"x"}, val: "a,b", want: []string{"a", "b"}},
+		"empty":    {input: 3, val: "", want: 3},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			have := tc.input
+			assert.NoError(t, UnmarshalAny(tc.val, &have))
+			assert.Equal(t, tc.want, have)
+		})
+	}
+
+	t.Run("pointer", func(t *testing.T) {
+		var x int
+		var have any = &x
+		assert.NoError(t, UnmarshalAny("3", &have))
+		assert.Equal(t, 3, x)
+		assert.Same(t, &x, have)
+	})
+	t.Run("via Unmarshal", func(t *testing.T) {
+		var have any = 1.5
+		assert.NoError(t, Unmarshal("2.5", &have))
+		assert.Equal(t, 2.5, have)
+	})
+	t.Run("checksum", func(t *testing.T) {
+		u := Unmarshaler{Options: Options{Checksum: ChecksumRequire}}
+		var have any = ""
+		assert.NoError(t, u.UnmarshalAny(WithChecksum("foo"), &have))
+		assert.Equal(t, "foo", have)
+	})
+	t.Run("nil interface", func(t *testing.T) {
+		var have any
+		assert.ErrorIs(t, UnmarshalAny("1", &have),
+			&UnsupportedTypeError{Type: reflect.TypeOf((*any)(nil)).Elem()},
+		)
+		assert.ErrorIs(t, UnmarshalAny("1", nil), ErrNilDestination)
+	})
+	t.Run("error", func(t *testing.T) {
+		var have any = 1
+		assert.ErrorIs(t, UnmarshalAny("x", &have), ErrParseFailure)
+		assert.Equal(t, 1, have)
+	})
+}
+
+func TestUnmarshaler_UnsupportedMapKey(t *testing.T) {
+	type key struct{ a, b int }
+
+	have := map[key]string{{1, 2}: "x"}
+	err := unmarshaler.Unmarshal("a=b,c=d", reflect.ValueOf(&have))
+	assert.ErrorIs(t, err, &UnsupportedTypeError{Type: reflect.TypeOf(key{})})
+	assert.Equal(t, map[key]string{{1, 2}: "x"}, have)
+
+	var nilMap map[key]string
+	assert.Error(t, unmarshaler.Unmarshal("a=b", reflect.ValueOf(&nilMap)))
+	assert.Nil(t, nilMap)
+
+	t.Run("nested", func(t *testing.T) {
+		var have map[[2]int]string
+		assert.ErrorIs(t, unmarshaler.Unmarshal("1=a", reflect.ValueOf(&have)), ErrUnmarshalNested)
+		assert.Nil(t, have)
+
+		u := Unmarshaler{Options: Options{NestedBrackets: true}}
+		assert.NoError(t, u.Unmarshal("[1,2]=a", reflect.ValueOf(&have)))
+		assert.Equal(t, map[[2]int]string{{1, 2}: "a"}, have)
+	})
+	t.Run("null", func(t *testing.T) {
+		var have map[Null[int]]string
+		assert.NoError(t, unmarshaler.Unmarshal("1=a", reflect.ValueOf(&have)))
+		assert.Equal(t, map[Null[int]]string{NullOf(1): "a"}, have)
+	})
+}
+
+func TestUnmarshaler_Unmarshal_noPartialWrites(t *testing.T) {
+	t.Run("array", func(t *testing.T) {
+		have := [3]int{7, 8, 9}
+		assert.Error(t, unmarshaler.Unmarshal("1,x", reflect.ValueOf(&have)))
+		assert.Equal(t, [3]int{7, 8, 9}, have)
+
+		assert.ErrorIs(t, unmarshaler.Unmarshal("1,2,3,4", reflect.ValueOf(&have)), ErrArrayTooManyValues)
+		assert.Equal(t, [3]int{7, 8, 9}, have)
+
+		assert.NoError(t, unmarshaler.Unmarshal("1,2", reflect.ValueOf(&have)))
+		assert.Equal(t, [3]int{1, 2, 9}, have)
+	})
+	t.Run("slice", func(t *testing.T) {
+		have := []int{7}
+		assert.Error(t, unmarshaler.Unmarshal("1,x", reflect.ValueOf(&have)))
+		assert.Equal(t, []int{7}, have)
+	})
+	t.Run("map", func(t *testing.T) {
+		have := map[string]int{"a": 7}
+		assert.Error(t, unmarshaler.Unmarshal("b=1,c=x", reflect.ValueOf(&have)))
+		assert.Equal(t, map[string]int{"a": 7}, have)
+
+		assert.NoError(t, unmarshaler.Unmarshal("a=1,b=2", reflect.ValueOf(&have)))
+		assert.Equal(t, map[string]int{"a": 1, "b": 2}, have)
+
+		var nilMap map[string]int
+		assert.Error(t, unmarshaler.Unmarshal("b=x", reflect.ValueOf(&nilMap)))
+		assert.Nil(t, nilMap)
+	})
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"strings"
+
+	"github.com/go-pogo/errors"
+)
+
+const ErrDecryptFailure errors.Msg = "failed to decrypt"
+
+// DefaultDecryptPrefix is the prefix of encrypted values when
+// Options.DecryptPrefix is empty.
+const DefaultDecryptPrefix = "enc:"
+
+// DecryptFunc decrypts the ciphertext of an encrypted value, the ciphertext
+// does not contain the prefix that marks the value as encrypted.
+type DecryptFunc func(ciphertext string) (Value, error)
+
+// decryptPrefix returns Options.DecryptPrefix or DefaultDecryptPrefix when
+// it's empty.
+func (o Options) decryptPrefix() string {
+	if o.DecryptPrefix == "" {
+		return DefaultDecryptPrefix
+	}
+	return o.DecryptPrefix
+}
+
+// decrypt passes the ciphertext of v to Options.Decrypt when v starts with
+// the decrypt prefix. Any error is wrapped with ErrDecryptFailure.
+func (o Options) decrypt(v Value) (Value, error) {
+	ciphertext, ok := strings.CutPrefix(v.String(), o.decryptPrefix())
+	if !ok {
+		return v, nil
+	}
+
+	plain, err := o.Decrypt(ciphertext)
+	if err != nil {
+		return v, errors.Wrap(err, ErrDecryptFailure)
+	}
+	return plain, nil
+}
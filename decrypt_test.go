@@ -0,0 +1,88 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-pogo/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshaler_Decrypt(t *testing.T) {
+	const errInvalid errors.Msg = "invalid ciphertext"
+
+	reverse := func(ciphertext string) (Value, error) {
+		if ciphertext == "" {
+			return "", errors.New(errInvalid)
+		}
+		r := []rune(ciphertext)
+		for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+			r[i], r[j] = r[j], r[i]
+		}
+		return Value(r), nil
+	}
+
+	tests := map[string]struct {
+		opts    Options
+		input   Value
+		want    any
+		wantErr error
+	}{
+		"encrypted": {
+			opts:  Options{Decrypt: reverse},
+			input: "enc:oof",
+			want:  "foo",
+		},
+		"encrypted slice": {
+			opts:  Options{Decrypt: reverse},
+			input: "enc:3,2,1",
+			want:  []int{1, 2, 3},
+		},
+		"plain": {
+			opts:  Options{Decrypt: reverse},
+			input: "oof",
+			want:  "oof",
+		},
+		"custom prefix": {
+			opts:  Options{Decrypt: reverse, DecryptPrefix: "sops:"},
+			input: "sops:s5",
+			want:  "5s",
+		},
+		"without decrypt": {
+			input: "enc:oof",
+			want:  "enc:oof",
+		},
+		"with checksum": {
+			opts:  Options{Decrypt: reverse, Checksum: ChecksumRequire},
+			input: WithChecksum("enc:oof"),
+			want:  "foo",
+		},
+		"failure": {
+			opts:    Options{Decrypt: reverse},
+			input:   "enc:",
+			want:    "",
+			wantErr: errInvalid,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			u := Unmarshaler{Options: tc.opts}
+			rv := reflect.New(reflect.TypeOf(tc.want))
+
+			haveErr := u.Unmarshal(tc.input, rv)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, haveErr, tc.wantErr)
+				assert.ErrorIs(t, haveErr, ErrDecryptFailure)
+				return
+			}
+
+			assert.NoError(t, haveErr)
+			assert.Equal(t, tc.want, rv.Elem().Interface())
+		})
+	}
+}
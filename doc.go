@@ -18,8 +18,25 @@ Out of the box, this package supports all logical base types and some common typ
   - array, slice
   - map
   - time.Duration
+  - time.Time
+  - *time.Location
+  - rawconv.ByteSize
+  - rawconv.Tristate
+  - rawconv.ContentEncoding, rawconv.Charset
+  - fs.FileMode
+  - rawconv.Null[T], sql.NullString, sql.NullInt64, etc.
   - url.URL
+  - regexp.Regexp
+  - slog.Level (go1.21+)
+  - netip.Addr
+  - netip.AddrPort
+  - netip.Prefix
+  - big.Int
+  - big.Float
+  - big.Rat
+  - json.RawMessage
   - encoding.TextUnmarshaler, encoding.TextMarshaler
+  - encoding.BinaryUnmarshaler, encoding.BinaryMarshaler
 
 # Array, slice and map conversions
 
@@ -27,27 +44,83 @@ Conversions to array, slice or map are done by splitting the raw string. The
 separator can be set via the Options type and defaults to DefaultItemsSeparator.
 For maps there is also a separator for the key-value pairs, which defaults to
 DefaultKeyValueSeparator.
+The Options used by the package-level functions, such as Unmarshal and Marshal,
+can be set with SetGlobalOptions.
 
 Values within the array, slice, or map are unmarshaled using the called
 Unmarshaler. This is also done for keys of maps.
 
-> Nested arrays, slices and maps are not supported.
+A []byte, or named type with an underlying byte slice, e.g. type Token []byte,
+is not split but treated as binary data instead. Its raw representation is
+determined by Options.BytesEncoding and defaults to BytesRaw. The same goes
+for byte arrays, e.g. [32]byte, where the decoded bytes must match the length
+of the array.
+
+Nested arrays, slices and maps are not supported by default. They can be
+enabled with Options.NestedBrackets, where each nested collection is enclosed by
+square brackets (e.g. "[1,2],[3,4]"), and/or Options.NestedSeparators, which
+sets a different items separator for each nested depth level (e.g. "1|2,3|4").
+
+Items which contain a separator can be quoted with double quotes
+(e.g. `"a,b",c`) when Options.Quote is set, or escaped with a backslash
+(e.g. `a\,b,c`) when Options.Escape is set.
 
 # Structs
 
-This package does not contain any logic for traversing struct types, because the
-implementation would really depend on the use case. However, it is possible to
-incorporate this package in your own struct unmarshaling logic.
+Use UnmarshalStruct and MarshalStruct to convert between the fields of a struct
+and a map[string]Value. The name of each field is determined by its `raw:"name"`
+tag, or its field name when there is no tag. Fields with tag `raw:"-"` and
+unexported fields are ignored. The fields of embedded structs are treated as
+fields of the outer struct.
+
+Use UnmarshalStructAtomic to only set the fields when all values unmarshal
+successfully, preventing partially updated structs. The returned Revert func
+restores the previous values of the fields.
+
+Use UnmarshalStructReport to get a JSON-able Report of the outcome of every
+field and key, for auditing purposes. The raw values of fields with tag option
+`secret`, e.g. `raw:"password,secret"`, are redacted in the Report.
+
+Use UnmarshalURLValues and MarshalURLValues to convert between a struct or map
+and url.Values, e.g. the query parameters of a http.Request.
 
 # Custom types
 
 Custom types are supported in two ways; by implementing the
 encoding.TextUnmarshaler and/or encoding.TextMarshaler interfaces, or by
 registering a MarshalFunc with RegisterMarshalFunc and/or an UnmarshalFunc with
-RegisterUnmarshalFunc.
+RegisterUnmarshalFunc. A func which is registered for an array, slice or map
+type takes precedence over the builtin splitting of its items.
+Funcs registered with RegisterMarshalKindFunc and/or RegisterUnmarshalKindFunc
+serve as fallback for all types of a reflect.Kind, e.g. every named integer
+enum type. Funcs are looked up by exact type first, then by interface and
+finally by kind. A func can return ErrSkip to let the next candidate, or the
+builtin logic, handle the value. Use ChainUnmarshalFunc and ChainMarshalFunc to
+combine multiple small funcs for a single type.
 
 If you do not wish to globally expose your MarshalFunc or UnmarshalFunc
 implementations, it is possible to register them to a new Marshaler and/or
 Unmarshaler and use those instances in your application instead.
+
+Packages which provide support for additional types can expose a Registerer,
+or a Register(Registrar) func, so their funcs can be registered either globally
+via GlobalRegistrar, or to specific instances via NewRegistrar.
+
+Support for additional type families is available as opt-in subpackages,
+which register their types globally when imported for their side effects:
+
+  - github.com/go-pogo/rawconv/types/httpx
+  - github.com/go-pogo/rawconv/types/netx
+  - github.com/go-pogo/rawconv/types/timex
+  - github.com/go-pogo/rawconv/types/tlsx
+
+The types of packages math/big and net/netip remain supported by package
+rawconv itself, because it already supported them before these subpackages
+existed. Moving them would silently break code which relies on their support
+without importing a subpackage, while package rawconv would still depend on
+them for Value methods such as Value.BigInt and Value.Addr. The nullable types of package database/sql
+are handled by the decoder and encoder themselves, instead of by registered
+funcs. The single types regexp.Regexp and slog.Level do not justify a
+subpackage of their own.
 */
 package rawconv
@@ -0,0 +1,122 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/go-pogo/errors"
+)
+
+const ErrEmptyValue errors.Msg = "empty value"
+
+// EmptyMode determines how empty values are handled when unmarshaling.
+type EmptyMode uint8
+
+const (
+	// EmptySkip leaves the destination of an empty value untouched, unless
+	// its type has a registered UnmarshalFunc which handles it.
+	EmptySkip EmptyMode = iota
+	// EmptySetZero sets the destination of an empty value to its zero value.
+	EmptySetZero
+	// EmptyError returns an ErrEmptyValue error for empty values.
+	EmptyError
+)
+
+// EmptyCollectionMode determines how empty values are handled when
+// unmarshaling them to a slice, map or array. A value which only contains
+// whitespace is considered empty for these kinds.
+type EmptyCollectionMode uint8
+
+const (
+	// EmptyCollectionDefault handles empty collections like any other empty
+	// value, according to Options.EmptyValues.
+	EmptyCollectionDefault EmptyCollectionMode = iota
+	// EmptyCollectionNil sets a slice or map to nil, and an array to its zero
+	// value.
+	EmptyCollectionNil
+	// EmptyCollectionNonNil sets a slice or map to an empty, non-nil, slice
+	// or map, and an array to its zero value. Because an empty slice or map
+	// is marshaled to an empty value, this makes round-tripping them
+	// lossless.
+	EmptyCollectionNonNil
+)
+
+// setEmpty handles an empty value for dest according to Options.EmptyValues,
+// which must not be EmptySkip.
+func (o Options) setEmpty(dest reflect.Value) error {
+	if o.EmptyValues == EmptyError {
+		return errors.New(ErrEmptyValue)
+	}
+	return setZero(dest)
+}
+
+// setEmptyCollection handles an empty value for collection dest according to
+// Options.EmptyCollections, which must not be EmptyCollectionDefault.
+func (o Options) setEmptyCollection(dest reflect.Value) error {
+	if o.EmptyCollections == EmptyCollectionNil {
+		return setZero(dest)
+	}
+
+	for dest.Kind() == reflect.Ptr {
+		if dest.IsNil() {
+			if !dest.CanSet() {
+				return errors.New(ErrUnableToSet)
+			}
+			dest.Set(reflect.New(dest.Type().Elem()))
+		}
+		dest = dest.Elem()
+	}
+
+	switch dest.Kind() {
+	case reflect.Slice:
+		dest.Set(reflect.MakeSlice(dest.Type(), 0, 0))
+	case reflect.Map:
+		dest.Set(reflect.MakeMap(dest.Type()))
+	default:
+		dest.Set(reflect.Zero(dest.Type()))
+	}
+	return nil
+}
+
+// setZero sets the first settable value of dest, or the value it points to,
+// to its zero value.
+func setZero(dest reflect.Value) error {
+	for !dest.CanSet() && dest.Kind() == reflect.Ptr && !dest.IsNil() {
+		dest = dest.Elem()
+	}
+	if !dest.CanSet() {
+		return errors.New(ErrUnableToSet)
+	}
+
+	dest.Set(reflect.Zero(dest.Type()))
+	return nil
+}
+
+// skipsEmpty indicates if unmarshaling an empty value leaves a destination of
+// typ untouched.
+func (u *Unmarshaler) skipsEmpty(typ reflect.Type) bool {
+	if isNullable(typ) {
+		return false
+	}
+	if u.EmptyCollections != EmptyCollectionDefault && u.collection(typ) {
+		return false
+	}
+	return u.EmptyValues == EmptySkip && u.Func(typ) == nil
+}
+
+// isNilCollection indicates if val is a nil slice or map, which is marshaled
+// as Options.NilToken.
+func (o Options) isNilCollection(val reflect.Value) bool {
+	return o.NilToken != "" &&
+		(val.Kind() == reflect.Slice || val.Kind() == reflect.Map) &&
+		val.IsNil()
+}
+
+// isBlank indicates if Value v is not empty, but only contains whitespace.
+func isBlank(v Value) bool {
+	return v != "" && strings.TrimSpace(v.String()) == ""
+}
@@ -0,0 +1,233 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshaler_EmptyValues(t *testing.T) {
+	type fixture struct {
+		Int   int
+		Str   string
+		Time  time.Time
+		Slice []int
+		Ptr   *int
+	}
+
+	one := 1
+	initial := fixture{
+		Int:   1,
+		Str:   "foo",
+		Time:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Slice: []int{1},
+		Ptr:   &one,
+	}
+
+	tests := map[string]struct {
+		mode    EmptyMode
+		want    fixture
+		wantErr error
+	}{
+		"skip": {
+			mode: EmptySkip,
+			want: initial,
+		},
+		"set zero": {
+			mode: EmptySetZero,
+			want: fixture{},
+		},
+		"error": {
+			mode:    EmptyError,
+			want:    initial,
+			wantErr: ErrEmptyValue,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			u := Unmarshaler{Options: Options{EmptyValues: tc.mode}}
+
+			have := initial
+			rv := reflect.ValueOf(&have).Elem()
+			for i := 0; i < rv.NumField(); i++ {
+				err := u.Unmarshal("", rv.Field(i).Addr())
+				if tc.wantErr != nil {
+					assert.ErrorIs(t, err, tc.wantErr)
+				} else {
+					assert.NoError(t, err)
+				}
+			}
+			assert.Equal(t, tc.want, have)
+		})
+	}
+
+	t.Run("items", func(t *testing.T) {
+		u := Unmarshaler{Options: Options{EmptyValues: EmptySetZero}}
+		have := []string{"x", "x", "x"}
+		assert.NoError(t, u.Unmarshal("a,,c", reflect.ValueOf(&have)))
+		assert.Equal(t, []string{"a", "", "c"}, have)
+
+		u.EmptyValues = EmptyError
+		err := u.Unmarshal("a,,c", reflect.ValueOf(&have))
+		assert.ErrorIs(t, err, ErrEmptyValue)
+
+		var unmarshalErr *UnmarshalError
+		assert.ErrorAs(t, err, &unmarshalErr)
+		assert.Equal(t, 1, unmarshalErr.Index)
+	})
+	t.Run("struct atomic", func(t *testing.T) {
+		u := Unmarshaler{Options: Options{EmptyValues: EmptySetZero}}
+		have := initial
+		_, err := u.UnmarshalStructAtomic(map[string]Value{"Int": "", "Str": ""}, reflect.ValueOf(&have))
+		assert.NoError(t, err)
+		assert.Zero(t, have.Int)
+		assert.Zero(t, have.Str)
+		assert.Equal(t, initial.Time, have.Time)
+	})
+}
+
+func TestUnmarshaler_EmptyCollections(t *testing.T) {
+	type fixture struct {
+		Slice []int
+		Map   map[string]int
+		Array [2]int
+		Ptr   *[]int
+	}
+
+	initial := func() fixture {
+		return fixture{
+			Slice: []int{1},
+			Map:   map[string]int{"a": 1},
+			Array: [2]int{1, 2},
+			Ptr:   &[]int{1},
+		}
+	}
+
+	tests := map[string]struct {
+		mode   EmptyCollectionMode
+		values EmptyMode
+		want   fixture
+	}{
+		"default": {
+			mode: EmptyCollectionDefault,
+			want: initial(),
+		},
+		"default set zero": {
+			mode:   EmptyCollectionDefault,
+			values: EmptySetZero,
+			want:   fixture{},
+		},
+		"nil": {
+			mode: EmptyCollectionNil,
+			want: fixture{},
+		},
+		"non-nil": {
+			mode:   EmptyCollectionNonNil,
+			values: EmptyError,
+			want: fixture{
+				Slice: []int{},
+				Map:   map[string]int{},
+				Ptr:   &[]int{},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		for _, input := range []Value{"", " ", "\t "} {
+			t.Run(name+"/"+strconv.Quote(input.String()), func(t *testing.T) {
+				u := Unmarshaler{Options: Options{
+					EmptyCollections: tc.mode,
+					EmptyValues:      tc.values,
+				}}
+
+				have := initial()
+				rv := reflect.ValueOf(&have).Elem()
+				for i := 0; i < rv.NumField(); i++ {
+					assert.NoError(t, u.Unmarshal(input, rv.Field(i).Addr()))
+				}
+				assert.Equal(t, tc.want, have)
+			})
+		}
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		val, err := Marshal([]string{})
+		assert.NoError(t, err)
+		assert.Equal(t, Value(""), val)
+
+		u := Unmarshaler{Options: Options{EmptyCollections: EmptyCollectionNonNil}}
+		var have []string
+		assert.NoError(t, u.Unmarshal(val, reflect.ValueOf(&have)))
+		assert.NotNil(t, have)
+		assert.Empty(t, have)
+	})
+	t.Run("nested", func(t *testing.T) {
+		u := Unmarshaler{Options: Options{
+			EmptyCollections: EmptyCollectionNonNil,
+			NestedSeparators: []string{"|"},
+		}}
+
+		var have [][]int
+		assert.NoError(t, u.Unmarshal("1|2, ,3", reflect.ValueOf(&have)))
+		assert.Equal(t, [][]int{{1, 2}, {}, {3}}, have)
+	})
+	t.Run("blank string item", func(t *testing.T) {
+		var have []string
+		assert.NoError(t, Unmarshal(" ", &have))
+		assert.Nil(t, have)
+	})
+}
+
+func TestOptions_NilToken(t *testing.T) {
+	opts := Options{
+		NilToken:         "<nil>",
+		EmptyCollections: EmptyCollectionNonNil,
+		NestedSeparators: []string{"|"},
+	}
+
+	tests := map[string]struct {
+		input any
+		want  Value
+	}{
+		"nil slice":   {input: []int(nil), want: "<nil>"},
+		"empty slice": {input: []int{}, want: ""},
+		"nil map":     {input: map[string]int(nil), want: "<nil>"},
+		"empty map":   {input: map[string]int{}, want: ""},
+		"nested":      {input: [][]int{nil, {}, {1, 2}}, want: "<nil>,,1|2"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := Marshaler{Options: opts}
+			have, err := m.Marshal(reflect.ValueOf(tc.input))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, have)
+
+			var sb strings.Builder
+			assert.NoError(t, m.MarshalTo(&sb, reflect.ValueOf(tc.input)))
+			assert.Equal(t, tc.want.String(), sb.String())
+
+			// prefill dest so unmarshaling a nil collection is noticeable
+			typ := reflect.TypeOf(tc.input)
+			dest := reflect.New(typ)
+			if typ.Kind() == reflect.Map {
+				dest.Elem().Set(reflect.MakeMap(typ))
+			} else {
+				dest.Elem().Set(reflect.MakeSlice(typ, 1, 1))
+			}
+
+			u := Unmarshaler{Options: opts}
+			assert.NoError(t, u.Unmarshal(have, dest))
+			assert.Equal(t, tc.input, dest.Elem().Interface())
+		})
+	}
+}
@@ -5,19 +5,23 @@
 package rawconv
 
 import (
+	"context"
+	"io"
 	"reflect"
+	"sort"
 	"strconv"
-	"strings"
 
 	"github.com/go-pogo/errors"
 )
 
 const ErrMarshalNested errors.Msg = "cannot marshal nested array/slice/map"
 
-// Marshal formats the value pointed to by v to a raw string Value.
+// Marshal formats the value pointed to by v to a raw string Value, using the
+// Options set with SetGlobalOptions.
 // If v is not a supported type an UnsupportedTypeError is returned.
 // By default, the following types are supported:
 //   - encoding.TextMarshaler
+//   - encoding.BinaryMarshaler (see Options.BinaryEncoding)
 //   - string
 //   - bool
 //   - int, int8, int16, int32, int64
@@ -27,18 +31,38 @@ const ErrMarshalNested errors.Msg = "cannot marshal nested array/slice/map"
 //   - array, slice
 //   - map
 //   - time.Duration
+//   - time.Time
+//   - *time.Location
+//   - rawconv.ByteSize
+//   - rawconv.Tristate
+//   - rawconv.ContentEncoding, rawconv.Charset
+//   - fs.FileMode (octal)
+//   - rawconv.Null[T] and database/sql nullable types, e.g. sql.NullString
 //   - url.URL
+//   - regexp.Regexp
+//   - slog.Level (go1.21+)
+//   - netip.Addr
+//   - netip.AddrPort
+//   - netip.Prefix
+//   - big.Int
+//   - big.Float
+//   - big.Rat
+//   - json.RawMessage
 //
 // Use RegisterMarshalFunc to add additional (custom) types.
 func Marshal(v any) (Value, error) {
-	return marshaler.Marshal(reflect.ValueOf(v))
+	m := Marshaler{Options: GlobalOptions()}
+	return m.Marshal(reflect.ValueOf(v))
 }
 
 type MarshalFunc func(v any) (string, error)
 
 // GetMarshalFunc returns the globally registered MarshalFunc for reflect.Type
 // typ or nil if there is none registered with RegisterMarshalFunc.
-func GetMarshalFunc(typ reflect.Type) MarshalFunc { return marshaler.Func(typ) }
+func GetMarshalFunc(typ reflect.Type) MarshalFunc {
+	m := Marshaler{Options: GlobalOptions()}
+	return m.Func(typ)
+}
 
 // marshaler is the global Marshaler.
 var marshaler Marshaler
@@ -48,37 +72,124 @@ var marshaler Marshaler
 // fallback to the global Marshaler when a type is not registered.
 type Marshaler struct {
 	Options
-	register register[MarshalFunc]
+	register   register[MarshalFunc]
+	middleware []func(next MarshalFunc) MarshalFunc
 }
 
 // Register the MarshalFunc for typ but only for this Marshaler.
+// See RegisterMarshalFunc for details about precedence.
 func (m *Marshaler) Register(typ reflect.Type, fn MarshalFunc) *Marshaler {
 	m.register.add(typ, fn)
 	return m
 }
 
+// Deregister removes the MarshalFunc which is registered for the exact type
+// typ to this Marshaler and returns it. It returns nil when no MarshalFunc is
+// registered for typ. Globally registered funcs are not affected.
+func (m *Marshaler) Deregister(typ reflect.Type) MarshalFunc {
+	return m.register.remove(typ)
+}
+
+// RegisterKind registers the MarshalFunc as fallback for all types of kind,
+// but only for this Marshaler. See RegisterUnmarshalKindFunc for details.
+func (m *Marshaler) RegisterKind(kind reflect.Kind, fn MarshalFunc) *Marshaler {
+	m.register.addKind(kind, fn)
+	return m
+}
+
 // Func returns the (globally) registered MarshalFunc for reflect.Type typ or
 // nil if there is none registered with Register or RegisterMarshalFunc.
+// A MarshalFunc which is registered for an interface that is only implemented
+// by a pointer to typ, is called with a pointer to a copy of the value.
 func (m *Marshaler) Func(typ reflect.Type) MarshalFunc {
+	fn, _, addr := m.lookup(typ)
+	if addr {
+		return fn.addr()
+	}
+	return fn
+}
+
+// funcCtx returns the (globally) registered MarshalFunc for typ as
+// MarshalFuncCtx, or nil if there is none. It also indicates if the func must
+// be called with a pointer to the value.
+func (m *Marshaler) funcCtx(typ reflect.Type) (MarshalFuncCtx, bool) {
+	fn, ctxFn, addr := m.lookup(typ)
+	return marshalFuncCtx(fn, ctxFn), addr
+}
+
+func (m *Marshaler) lookup(typ reflect.Type) (MarshalFunc, any, bool) {
 	if m.register.initialized() {
-		if fn := m.register.find(typ); fn != nil {
-			return fn
+		if fn, ctxFn, addr := m.register.resolve(typ, m.Options); fn != nil {
+			return fn, ctxFn, addr
 		}
 	}
 	// fallback to global marshaler
-	return marshaler.register.find(typ)
+	if fn, ctxFn, addr := marshaler.register.resolve(typ, m.Options); fn != nil {
+		return fn, ctxFn, addr
+	}
+	// fallback to funcs registered for the kind of typ
+	if m.register.initialized() {
+		if fn, ctxFn := m.register.resolveKind(typ); fn != nil {
+			return fn, ctxFn, false
+		}
+	}
+	fn, ctxFn := marshaler.register.resolveKind(typ)
+	return fn, ctxFn, false
+}
+
+// ptrType returns the pointer type for which the (globally) registered
+// MarshalFunc of typ is registered, or nil when it is not registered for a
+// pointer type.
+func (m *Marshaler) ptrType(typ reflect.Type) reflect.Type {
+	if typ.Kind() != reflect.Ptr {
+		return nil
+	}
+	if m.register.initialized() && m.register.find(typ) != nil {
+		return m.register.ptrType(typ)
+	}
+	return marshaler.register.ptrType(typ)
 }
 
 // Marshal returns the string representation of the value.
 // If the underlying reflect.Value is nil, it returns an empty string.
 func (m *Marshaler) Marshal(val reflect.Value) (Value, error) {
-	str, err := m.marshal(val, false)
+	str, err := m.marshal(context.Background(), val, 0)
 	return Value(str), err
 }
 
-func (m *Marshaler) marshal(val reflect.Value, nested bool) (string, error) {
-	if fn := m.Func(val.Type()); fn != nil {
-		return fn.exec(val)
+func (m *Marshaler) marshal(ctx context.Context, val reflect.Value, depth int) (string, error) {
+	if depth != 0 || len(m.middleware) == 0 {
+		return m.marshalValue(ctx, val, depth)
+	}
+	return m.marshalMiddleware(ctx, val)
+}
+
+// marshalValue marshals val without applying any middleware.
+func (m *Marshaler) marshalValue(ctx context.Context, val reflect.Value, depth int) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", errors.WithStack(err)
+	}
+	if m.Strict {
+		if err := ambiguousTypeErr(val.Type(), &m.register, &marshaler.register); err != nil {
+			return "", err
+		}
+	}
+	if fn, addr := m.funcCtx(val.Type()); fn != nil {
+		var str string
+		var err error
+		if ptr := m.ptrType(val.Type()); ptr != nil {
+			str, err = fn.execPtr(ctx, val, ptr)
+		} else if addr {
+			str, err = fn.execAddr(ctx, val)
+		} else {
+			str, err = fn.exec(ctx, val)
+		}
+		if errors.Is(err, ErrSkip) {
+			str, err = m.execFallbacks(ctx, val)
+		}
+		if !errors.Is(err, ErrSkip) {
+			return str, err
+		}
 	}
 
 	ot := val.Type()
@@ -94,75 +205,225 @@ func (m *Marshaler) marshal(val reflect.Value, nested bool) (string, error) {
 		return val.String(), nil
 
 	case reflect.Bool:
-		return strconv.FormatBool(val.Bool()), nil
+		return m.formatBool(val.Bool()), nil
 
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return strconv.FormatInt(val.Int(), 10), nil
+		return strconv.FormatInt(val.Int(), m.formatBase()), nil
 
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return strconv.FormatUint(val.Uint(), 10), nil
+		return strconv.FormatUint(val.Uint(), m.formatBase()), nil
 
 	case reflect.Float32, reflect.Float64:
-		return strconv.FormatFloat(val.Float(), 'g', -1, 64), nil
+		return strconv.FormatFloat(val.Float(), 'g', -1, val.Type().Bits()), nil
 
 	case reflect.Complex64, reflect.Complex128:
-		return strconv.FormatComplex(val.Complex(), 'g', -1, 128), nil
+		return strconv.FormatComplex(val.Complex(), 'g', -1, val.Type().Bits()), nil
 
 	case reflect.Array, reflect.Slice:
-		if nested {
-			return "", errors.New(ErrMarshalNested)
+		if val.Type() == jsonRawMessageType {
+			return string(val.Bytes()), nil
 		}
+		if m.isBinary(val.Type()) {
+			return m.BytesEncoding.Encode(bytesOf(val)), nil
+		}
+		fallthrough
 
-		sep := m.itemSeparator()
+	case reflect.Map:
+		b, err := m.appendCollection(ctx, nil, val, depth)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
 
-		var buf strings.Builder
-		for i := 0; i < val.Len(); i++ {
-			v, err := m.marshal(val.Index(i), true)
-			if err != nil {
-				return "", err
-			}
+	case reflect.Struct:
+		if !isNullable(val.Type()) {
+			break
+		}
+		if !val.Field(1).Bool() {
+			return m.NilToken, nil
+		}
+		return m.marshalValue(ctx, val.Field(0), depth)
+	}
+	return "", errors.WithStack(&UnsupportedTypeError{Type: ot})
+}
 
-			if i > 0 {
-				buf.WriteString(sep)
-			}
-			buf.WriteString(v)
+// MarshalTo writes the string representation of the value to w. Unlike
+// Marshal, the items of arrays, slices and maps are written to w one by one,
+// without building the string representation of the collection as a whole in
+// memory. When an error occurs, w may already contain part of the result.
+func (m *Marshaler) MarshalTo(w io.Writer, val reflect.Value) error {
+	ctx := context.Background()
+	if coll, ok := m.collection(val); ok && len(m.middleware) == 0 {
+		return m.writeCollection(ctx, w, coll, 0)
+	}
+
+	str, err := m.marshal(ctx, val, 0)
+	if err != nil {
+		return err
+	}
+	return writeString(w, str)
+}
+
+// collection returns the (dereferenced) value of val when it is an array,
+// slice or map which has no registered MarshalFunc and is not marshaled as
+// raw or binary data.
+func (m *Marshaler) collection(val reflect.Value) (reflect.Value, bool) {
+	if !val.IsValid() {
+		return val, false
+	}
+	if fn, _, _ := m.lookup(val.Type()); fn != nil {
+		return val, false
+	}
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return val, false
 		}
-		return buf.String(), nil
+		val = val.Elem()
+	}
 
+	switch val.Kind() {
+	case reflect.Array, reflect.Slice:
+		return val, val.Type() != jsonRawMessageType && !m.isBinary(val.Type())
 	case reflect.Map:
-		if nested {
-			return "", errors.New(ErrMarshalNested)
-		}
+		return val, true
+	default:
+		return val, false
+	}
+}
 
-		sep1 := m.keyValueSeparator()
-		sep2 := m.itemSeparator()
+// mapKeys returns the keys of map val in a deterministic order. Keys of an
+// ordered kind are sorted by their value, all other keys are sorted by their
+// string representation.
+func (m *Marshaler) mapKeys(ctx context.Context, val reflect.Value, depth int) ([]reflect.Value, error) {
+	keys := val.MapKeys()
+	if len(keys) < 2 {
+		return keys, nil
+	}
 
-		var buf strings.Builder
-		var firstDone bool
-		for iter := val.MapRange(); iter.Next(); {
-			v, err := m.marshal(iter.Value(), true)
-			if err != nil {
-				return "", err
-			}
-			k, err := m.marshal(iter.Key(), true)
+	switch val.Type().Key().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		sort.Slice(keys, func(i, j int) bool { return keys[i].Int() < keys[j].Int() })
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		sort.Slice(keys, func(i, j int) bool { return keys[i].Uint() < keys[j].Uint() })
+	case reflect.Float32, reflect.Float64:
+		sort.Slice(keys, func(i, j int) bool { return keys[i].Float() < keys[j].Float() })
+	case reflect.String:
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	case reflect.Bool:
+		sort.Slice(keys, func(i, j int) bool { return !keys[i].Bool() && keys[j].Bool() })
+
+	default:
+		strs := make([]string, len(keys))
+		for i, key := range keys {
+			b, err := m.appendKey(ctx, nil, key, depth)
 			if err != nil {
-				return "", err
+				return nil, err
 			}
+			strs[i] = string(b)
+		}
+		sort.Sort(keySorter{keys, strs})
+	}
+	return keys, nil
+}
 
-			if firstDone {
-				buf.WriteString(sep2)
-			}
+type keySorter struct {
+	keys []reflect.Value
+	strs []string
+}
+
+func (s keySorter) Len() int           { return len(s.keys) }
+func (s keySorter) Less(i, j int) bool { return s.strs[i] < s.strs[j] }
+func (s keySorter) Swap(i, j int) {
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+	s.strs[i], s.strs[j] = s.strs[j], s.strs[i]
+}
 
-			buf.WriteString(k)
-			buf.WriteString(sep1)
-			buf.WriteString(v)
-			firstDone = true
+// writeCollection writes the items of array, slice or map val to w.
+func (m *Marshaler) writeCollection(ctx context.Context, w io.Writer, val reflect.Value, depth int) error {
+	if m.isNilCollection(val) {
+		return writeString(w, m.NilToken)
+	}
+	if !m.nestable(depth) {
+		return errors.New(ErrMarshalNested)
+	}
+
+	brackets := depth > 0 && m.NestedBrackets
+	if brackets {
+		if err := writeString(w, "["); err != nil {
+			return err
 		}
-		return buf.String(), nil
+	}
 
-	default:
-		return "", errors.WithStack(&UnsupportedTypeError{Type: ot})
+	// buf is reused to format the items, before they are written to w
+	var buf []byte
+	var err error
+
+	sep := m.itemSeparatorAt(depth)
+	if val.Kind() == reflect.Map {
+		keys, err := m.mapKeys(ctx, val, depth+1)
+		if err != nil {
+			return err
+		}
+
+		kvSep := m.keyValueSeparator()
+		for i, key := range keys {
+			buf = buf[:0]
+			if i > 0 {
+				buf = append(buf, sep...)
+			}
+			if buf, err = m.appendKey(ctx, buf, key, depth+1); err != nil {
+				return err
+			}
+			buf = append(buf, kvSep...)
+			if err = writeBytes(w, buf); err != nil {
+				return err
+			}
+			if buf, err = m.writeItem(ctx, w, buf, val.MapIndex(key), depth+1); err != nil {
+				return err
+			}
+		}
+	} else {
+		for i := 0; i < val.Len(); i++ {
+			if i > 0 {
+				if err = writeString(w, sep); err != nil {
+					return err
+				}
+			}
+			if buf, err = m.writeItem(ctx, w, buf, val.Index(i), depth+1); err != nil {
+				return err
+			}
+		}
 	}
+
+	if brackets {
+		return writeString(w, "]")
+	}
+	return nil
+}
+
+// writeItem writes the string representation of the item val, of a
+// collection at depth-1, to w. Leaf items are formatted using buf, which is
+// returned for reuse.
+func (m *Marshaler) writeItem(ctx context.Context, w io.Writer, buf []byte, val reflect.Value, depth int) ([]byte, error) {
+	if coll, ok := m.collection(val); ok {
+		return buf, m.writeCollection(ctx, w, coll, depth)
+	}
+
+	buf, err := m.appendQuoted(ctx, buf[:0], val, depth)
+	if err != nil {
+		return buf, err
+	}
+	return buf, writeBytes(w, buf)
+}
+
+func writeString(w io.Writer, str string) error {
+	_, err := io.WriteString(w, str)
+	return errors.WithStack(err)
+}
+
+func writeBytes(w io.Writer, b []byte) error {
+	_, err := w.Write(b)
+	return errors.WithStack(err)
 }
 
 // Exec executes the MarshalFunc for the given reflect.Value.
@@ -172,6 +433,26 @@ func (fn MarshalFunc) Exec(val reflect.Value) (Value, error) {
 }
 
 func (fn MarshalFunc) exec(val reflect.Value) (string, error) {
+	return fn.ctx().exec(context.Background(), val)
+}
+
+// ctx returns fn as a MarshalFuncCtx which ignores its context.
+func (fn MarshalFunc) ctx() MarshalFuncCtx {
+	if fn == nil {
+		return nil
+	}
+	return func(_ context.Context, v any) (string, error) {
+		return fn(v)
+	}
+}
+
+// Exec executes the MarshalFuncCtx with ctx for the given reflect.Value.
+func (fn MarshalFuncCtx) Exec(ctx context.Context, val reflect.Value) (Value, error) {
+	str, err := fn.exec(ctx, val)
+	return Value(str), err
+}
+
+func (fn MarshalFuncCtx) exec(ctx context.Context, val reflect.Value) (string, error) {
 	for val.Kind() == reflect.Ptr {
 		if val.IsNil() {
 			return "", nil
@@ -179,10 +460,77 @@ func (fn MarshalFunc) exec(val reflect.Value) (string, error) {
 		val = val.Elem()
 	}
 
-	str, err := fn(val.Interface())
+	str, err := fn(ctx, val.Interface())
 	if err != nil {
-		return str, errors.WithStack(err)
+		return str, funcErr(err)
 	}
 
 	return str, nil
 }
+
+// execPtr executes the MarshalFuncCtx with the pointer of val, or the
+// pointer val points to, which is of type ptr. It returns an empty string
+// when a pointer is nil.
+func (fn MarshalFuncCtx) execPtr(ctx context.Context, val reflect.Value, ptr reflect.Type) (string, error) {
+	for val.Type() != ptr {
+		if val.IsNil() {
+			return "", nil
+		}
+		val = val.Elem()
+	}
+	if val.IsNil() {
+		return "", nil
+	}
+
+	str, err := fn(ctx, val.Interface())
+	if err != nil {
+		return str, funcErr(err)
+	}
+	return str, nil
+}
+
+// execAddr executes the MarshalFunc with a pointer to the value of val. When
+// the value is not addressable, a pointer to a copy of the value is used.
+func (fn MarshalFunc) execAddr(val reflect.Value) (string, error) {
+	return fn.ctx().execAddr(context.Background(), val)
+}
+
+// execAddr executes the MarshalFuncCtx with a pointer to the value of val,
+// see MarshalFunc.execAddr.
+func (fn MarshalFuncCtx) execAddr(ctx context.Context, val reflect.Value) (string, error) {
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return "", nil
+		}
+		val = val.Elem()
+	}
+	if !val.CanAddr() {
+		ptr := reflect.New(val.Type())
+		ptr.Elem().Set(val)
+		val = ptr.Elem()
+	}
+
+	str, err := fn(ctx, val.Addr().Interface())
+	if err != nil {
+		return str, funcErr(err)
+	}
+
+	return str, nil
+}
+
+// funcErr adds a stack trace to err, which is returned by a MarshalFunc.
+func funcErr(err error) error {
+	//goland:noinspection GoTypeAssertionOnErrors
+	if msg, ok := err.(errors.Msg); ok {
+		return errors.New(msg)
+	}
+	return errors.WithStack(err)
+}
+
+// addr returns a MarshalFunc which calls fn with a pointer to a copy of the
+// value it receives.
+func (fn MarshalFunc) addr() MarshalFunc {
+	return func(v any) (string, error) {
+		return fn.execAddr(reflect.ValueOf(v))
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/go-pogo/errors"
+)
+
+const ErrInvalidEnumValue errors.Msg = "invalid enum value"
+
+// RegisterEnum registers the UnmarshalFunc and MarshalFunc of Enum e for type
+// T, making them globally available. See Enum for details.
+//
+//	type Level int
+//	rawconv.RegisterEnum(rawconv.Enum[Level]{
+//		Names: map[string]Level{"debug": 0, "info": 1, "error": 2},
+//		CaseInsensitive: true,
+//	})
+func RegisterEnum[T comparable](e Enum[T]) {
+	typ := typeFor[T]()
+	RegisterUnmarshalFunc(typ, e.UnmarshalFunc())
+	RegisterMarshalFunc(typ, e.MarshalFunc())
+}
+
+const panicEnumAmbiguous = "rawconv: Enum names must be unique when matched case-insensitive"
+
+// Enum describes the raw names of the values of an enum-like type T.
+type Enum[T comparable] struct {
+	// Names contains the values of T by their raw name. Multiple names, e.g.
+	// aliases, may refer to the same value, in which case the first name in
+	// sorted order is used when marshaling.
+	Names map[string]T
+	// CaseInsensitive matches names case-insensitive when unmarshaling.
+	CaseInsensitive bool
+}
+
+// UnmarshalFunc returns an UnmarshalFunc which sets the value of the name
+// that matches Value. It returns an EnumError when there is no such name.
+// An empty Value is skipped, unless it is one of the names. It panics when
+// CaseInsensitive is set and Names contains multiple names which only differ
+// in case, but refer to different values.
+func (e Enum[T]) UnmarshalFunc() UnmarshalFunc {
+	values := make(map[string]T, len(e.Names))
+	for name, x := range e.Names {
+		if e.CaseInsensitive {
+			name = strings.ToLower(name)
+			if y, ok := values[name]; ok && y != x {
+				panic(panicEnumAmbiguous)
+			}
+		}
+		values[name] = x
+	}
+
+	allowed := e.names()
+	return func(val Value, dest any) error {
+		name := val.String()
+		if e.CaseInsensitive {
+			name = strings.ToLower(name)
+		}
+
+		x, ok := values[name]
+		if !ok {
+			if val.IsEmpty() {
+				return nil
+			}
+			return errors.WithStack(&EnumError{Allowed: allowed})
+		}
+
+		*dest.(*T) = x
+		return nil
+	}
+}
+
+// MarshalFunc returns a MarshalFunc which formats a value of T as its name.
+// It returns an ErrInvalidEnumValue error when the value has no name.
+func (e Enum[T]) MarshalFunc() MarshalFunc {
+	names := make(map[T]string, len(e.Names))
+	for _, name := range e.names() {
+		x := e.Names[name]
+		if _, ok := names[x]; !ok {
+			names[x] = name
+		}
+	}
+
+	return func(v any) (string, error) {
+		if name, ok := names[v.(T)]; ok {
+			return name, nil
+		}
+		return "", errors.New(ErrInvalidEnumValue)
+	}
+}
+
+// names returns the sorted names of the Enum.
+func (e Enum[T]) names() []string {
+	names := make([]string, 0, len(e.Names))
+	for name := range e.Names {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// EnumError is returned when a Value does not match any of the names of an
+// Enum. It matches both ErrInvalidEnumValue and ErrParseFailure.
+type EnumError struct {
+	// Allowed contains the sorted names of the Enum.
+	Allowed []string
+}
+
+func (e *EnumError) Is(err error) bool {
+	return err == ErrInvalidEnumValue || err == ErrParseFailure
+}
+
+func (e *EnumError) Error() string {
+	return string(ErrInvalidEnumValue) + ", must be one of: " + strings.Join(e.Allowed, ", ")
+}
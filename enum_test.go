@@ -0,0 +1,124 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-pogo/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type color uint8
+
+const (
+	red color = iota + 1
+	green
+	blue
+)
+
+func TestEnum(t *testing.T) {
+	enum := Enum[color]{Names: map[string]color{
+		"red":   red,
+		"green": green,
+		"blue":  blue,
+		"azure": blue,
+	}}
+
+	var u Unmarshaler
+	u.Register(reflect.TypeOf(red), enum.UnmarshalFunc())
+	var m Marshaler
+	m.Register(reflect.TypeOf(red), enum.MarshalFunc())
+
+	t.Run("unmarshal", func(t *testing.T) {
+		var have []color
+		assert.NoError(t, u.Unmarshal("red,azure,green", reflect.ValueOf(&have)))
+		assert.Equal(t, []color{red, blue, green}, have)
+	})
+	t.Run("marshal", func(t *testing.T) {
+		have, err := m.Marshal(reflect.ValueOf([]color{blue, red}))
+		assert.NoError(t, err)
+		assert.Equal(t, Value("azure,red"), have, "first name in sorted order")
+
+		_, err = m.Marshal(reflect.ValueOf(color(9)))
+		assert.ErrorIs(t, err, ErrInvalidEnumValue)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		have := green
+		err := u.Unmarshal("Red", reflect.ValueOf(&have))
+		assert.ErrorIs(t, err, ErrInvalidEnumValue)
+		assert.ErrorIs(t, err, ErrParseFailure)
+		assert.Equal(t, green, have)
+
+		var ee *EnumError
+		if assert.ErrorAs(t, err, &ee) {
+			assert.Equal(t, []string{"azure", "blue", "green", "red"}, ee.Allowed)
+			assert.Equal(t, "invalid enum value, must be one of: azure, blue, green, red", ee.Error())
+		}
+		var pe *ParseError
+		if assert.ErrorAs(t, err, &pe) {
+			assert.Equal(t, Value("Red"), pe.Value)
+			assert.Equal(t, reflect.TypeOf(red), pe.Type)
+		}
+	})
+	t.Run("empty", func(t *testing.T) {
+		have := green
+		assert.NoError(t, u.Unmarshal("", reflect.ValueOf(&have)))
+		assert.Equal(t, green, have)
+
+		fn := Enum[color]{Names: map[string]color{"": 0, "red": red}}.UnmarshalFunc()
+		assert.NoError(t, fn("", &have))
+		assert.Equal(t, color(0), have)
+	})
+	t.Run("case insensitive", func(t *testing.T) {
+		fn := Enum[color]{Names: enum.Names, CaseInsensitive: true}.UnmarshalFunc()
+
+		var have color
+		assert.NoError(t, fn("GrEeN", &have))
+		assert.Equal(t, green, have)
+
+		assert.PanicsWithValue(t, panicEnumAmbiguous, func() {
+			Enum[color]{
+				Names:           map[string]color{"red": red, "RED": blue},
+				CaseInsensitive: true,
+			}.UnmarshalFunc()
+		})
+		assert.NotPanics(t, func() {
+			Enum[color]{
+				Names:           map[string]color{"red": red, "RED": red},
+				CaseInsensitive: true,
+			}.UnmarshalFunc()
+		})
+	})
+}
+
+func TestRegisterEnum(t *testing.T) {
+	typ := reflect.TypeOf(red)
+	t.Cleanup(func() {
+		DeregisterUnmarshalFunc(typ)
+		DeregisterMarshalFunc(typ)
+	})
+
+	RegisterEnum(Enum[color]{
+		Names:           map[string]color{"red": red, "green": green, "blue": blue},
+		CaseInsensitive: true,
+	})
+
+	have, err := As[color]("BLUE")
+	assert.NoError(t, err)
+	assert.Equal(t, blue, have)
+
+	val, err := From(green)
+	assert.NoError(t, err)
+	assert.Equal(t, Value("green"), val)
+
+	_, err = As[color]("purple")
+	assert.True(t, errors.Is(err, ErrInvalidEnumValue))
+	assert.JSONEq(t,
+		`{"value":"purple","type":"rawconv.color","reason":"invalid enum value, must be one of: blue, green, red","position":""}`,
+		string(ErrorToJSON(err)),
+	)
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-pogo/errors"
+)
+
+const (
+	ErrInvalidLine errors.Msg = "invalid key value line"
+	ErrInvalidKey  errors.Msg = "invalid key"
+)
+
+// Decoder reads newline separated key value pairs, in env-file style, from
+// an input stream. Empty lines and lines starting with # are ignored, as is
+// an "export " prefix before a key. Keys and values are separated by the
+// key value separator of its Options. A value enclosed by double quotes is
+// unquoted using strconv.Unquote, a value enclosed by single quotes is used
+// literally.
+//
+//	KEY=value
+//	# comment
+//	export OTHER="multi\nline"
+type Decoder struct {
+	Options
+	scanner *bufio.Scanner
+	line    int
+	key     string
+	val     Value
+	err     error
+}
+
+// NewDecoder returns a new Decoder which reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, math.MaxInt)
+	return &Decoder{scanner: scanner}
+}
+
+// Next advances the Decoder to the next key value pair, which is then
+// available through Token. It returns false when there are no more pairs or
+// when an error occurred, which is returned by Err.
+func (d *Decoder) Next() bool {
+	if d.err != nil {
+		return false
+	}
+
+	for d.scanner.Scan() {
+		d.line++
+		line := strings.TrimSpace(d.scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		d.key, d.val, d.err = d.parseLine(line)
+		if d.err != nil {
+			d.err = errors.Wrapf(d.err, "line %d", d.line)
+			return false
+		}
+		return true
+	}
+
+	d.err = errors.WithStack(d.scanner.Err())
+	d.key, d.val = "", ""
+	return false
+}
+
+func (d *Decoder) parseLine(line string) (string, Value, error) {
+	line = strings.TrimPrefix(line, "export ")
+	key, val, ok := strings.Cut(line, d.keyValueSeparator())
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return "", "", errors.New(ErrInvalidLine)
+	}
+
+	val = strings.TrimSpace(val)
+	if n := len(val); n >= 2 {
+		switch {
+		case val[0] == '"' && val[n-1] == '"':
+			str, err := strconv.Unquote(val)
+			if err != nil {
+				return "", "", errors.Wrap(err, ErrInvalidLine)
+			}
+			val = str
+		case val[0] == '\'' && val[n-1] == '\'':
+			val = val[1 : n-1]
+		}
+	}
+	return key, Value(val), nil
+}
+
+// Token returns the key value pair the Decoder is currently at.
+func (d *Decoder) Token() (key string, val Value) { return d.key, d.val }
+
+// Err returns the first error that occurred while decoding.
+func (d *Decoder) Err() error { return d.err }
+
+// Decode reads all remaining key value pairs and adds them to values. A key
+// which occurs more than once is overwritten by its last occurrence.
+func (d *Decoder) Decode(values map[string]Value) error {
+	if values == nil {
+		return errors.New(ErrNilDestination)
+	}
+	for d.Next() {
+		values[d.key] = d.val
+	}
+	return d.err
+}
+
+// Encoder writes key value pairs, in env-file style, to an output stream.
+// Values which cannot be written as is, e.g. because they contain a newline
+// or leading whitespace, are quoted using strconv.Quote so they are read back
+// unchanged by a Decoder.
+type Encoder struct {
+	Options
+	w io.Writer
+}
+
+// NewEncoder returns a new Encoder which writes to w.
+func NewEncoder(w io.Writer) *Encoder { return &Encoder{w: w} }
+
+// Encode writes all values, sorted by their key.
+func (e *Encoder) Encode(values map[string]Value) error {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if err := e.EncodeToken(key, values[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EncodeToken writes a single key value pair. It returns an ErrInvalidKey
+// error when key is empty, contains the key value separator, whitespace or
+// starts with #.
+func (e *Encoder) EncodeToken(key string, val Value) error {
+	sep := e.keyValueSeparator()
+	if key == "" || key[0] == '#' ||
+		strings.Contains(key, sep) ||
+		strings.ContainsAny(key, " \t\r\n") {
+		return errors.Wrapf(errors.New(ErrInvalidKey), "key `%s`", key)
+	}
+
+	str := val.String()
+	if needsEnvQuote(str) {
+		str = strconv.Quote(str)
+	}
+	return writeString(e.w, key+sep+str+"\n")
+}
+
+// needsEnvQuote indicates if str must be quoted to be read back unchanged by
+// a Decoder.
+func needsEnvQuote(str string) bool {
+	if str == "" {
+		return false
+	}
+	if strings.TrimSpace(str) != str || strings.ContainsAny(str, "\r\n") {
+		return true
+	}
+	c := str[0]
+	return (c == '"' || c == '\'') && len(str) >= 2 && str[len(str)-1] == c
+}
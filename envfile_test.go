@@ -0,0 +1,102 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecoder(t *testing.T) {
+	const input = `
+# comment
+FOO=bar
+  export BAZ = qux  
+EMPTY=
+DOUBLE="multi\nline \"quoted\""
+SINGLE='  literal \n '
+URL=http://localhost?a=b
+FOO=override
+`
+
+	t.Run("Decode", func(t *testing.T) {
+		have := make(map[string]Value)
+		assert.NoError(t, NewDecoder(strings.NewReader(input)).Decode(have))
+		assert.Equal(t, map[string]Value{
+			"FOO":    "override",
+			"BAZ":    "qux",
+			"EMPTY":  "",
+			"DOUBLE": "multi\nline \"quoted\"",
+			"SINGLE": `  literal \n `,
+			"URL":    "http://localhost?a=b",
+		}, have)
+	})
+	t.Run("Next", func(t *testing.T) {
+		var keys []string
+		dec := NewDecoder(strings.NewReader(input))
+		for dec.Next() {
+			key, _ := dec.Token()
+			keys = append(keys, key)
+		}
+		assert.NoError(t, dec.Err())
+		assert.Equal(t, []string{"FOO", "BAZ", "EMPTY", "DOUBLE", "SINGLE", "URL", "FOO"}, keys)
+	})
+	t.Run("separator", func(t *testing.T) {
+		dec := NewDecoder(strings.NewReader("a: 1\nb: 2"))
+		dec.KeyValueSeparator = ":"
+
+		have := make(map[string]Value)
+		assert.NoError(t, dec.Decode(have))
+		assert.Equal(t, map[string]Value{"a": "1", "b": "2"}, have)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		for _, input := range []string{"FOO=bar\nnope", "=value", `A="unterminated\"`} {
+			dec := NewDecoder(strings.NewReader(input))
+			err := dec.Decode(make(map[string]Value))
+			assert.ErrorIs(t, err, ErrInvalidLine, input)
+			assert.False(t, dec.Next())
+		}
+	})
+	t.Run("nil map", func(t *testing.T) {
+		assert.ErrorIs(t, NewDecoder(strings.NewReader("")).Decode(nil), ErrNilDestination)
+	})
+}
+
+func TestEncoder(t *testing.T) {
+	values := map[string]Value{
+		"B":      "plain value",
+		"A":      "",
+		"SPACES": "  padded ",
+		"MULTI":  "line1\nline2",
+		"QUOTED": `"quoted"`,
+		"SINGLE": `'single'`,
+		"HALF":   `"half`,
+	}
+
+	var sb strings.Builder
+	assert.NoError(t, NewEncoder(&sb).Encode(values))
+	assert.Equal(t, `A=
+B=plain value
+HALF="half
+MULTI="line1\nline2"
+QUOTED="\"quoted\""
+SINGLE="'single'"
+SPACES="  padded "
+`, sb.String())
+
+	t.Run("round trip", func(t *testing.T) {
+		have := make(map[string]Value)
+		assert.NoError(t, NewDecoder(strings.NewReader(sb.String())).Decode(have))
+		assert.Equal(t, values, have)
+	})
+	t.Run("invalid key", func(t *testing.T) {
+		for _, key := range []string{"", "A=B", "A B", "#A"} {
+			err := NewEncoder(new(strings.Builder)).EncodeToken(key, "x")
+			assert.ErrorIs(t, err, ErrInvalidKey, key)
+		}
+	})
+}
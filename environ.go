@@ -0,0 +1,91 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"os"
+	"reflect"
+	"strings"
+)
+
+// LookupFunc retrieves the value of the environment variable named by key,
+// the returned bool indicates if the variable is present. os.LookupEnv is a
+// LookupFunc.
+type LookupFunc func(key string) (string, bool)
+
+// UnmarshalEnviron unmarshals the environment variables, which start with
+// prefix, to the fields of the struct pointed to by v, using the Options set
+// with SetGlobalOptions. See UnmarshalEnvironFunc for additional details.
+//
+//	type Config struct {
+//		Port    uint16        `raw:"PORT"`
+//		Timeout time.Duration `raw:"TIMEOUT"`
+//	}
+//
+//	var conf Config
+//	err := rawconv.UnmarshalEnviron("APP_", &conf) // reads APP_PORT and APP_TIMEOUT
+func UnmarshalEnviron(prefix string, v any) error {
+	return UnmarshalEnvironFunc(prefix, os.LookupEnv, v)
+}
+
+// UnmarshalEnvironFunc unmarshals the values returned by lookup, for keys
+// which consist of prefix and the name of a field, to the fields of the
+// struct pointed to by v, using the Options set with SetGlobalOptions.
+//
+// The fields and their names are determined the same way as with
+// UnmarshalStruct. When there is no variable for the exact name of a field,
+// its upper cased name is tried, e.g. the name of field Timeout without a tag
+// results in keys "APP_Timeout" and "APP_TIMEOUT". Fields without a matching
+// variable are left untouched.
+func UnmarshalEnvironFunc(prefix string, lookup LookupFunc, v any) error {
+	rv, err := ptrDest(v)
+	if err != nil {
+		return err
+	}
+
+	u := Unmarshaler{Options: GlobalOptions()}
+	return u.unmarshalEnviron(prefix, lookup, rv)
+}
+
+// UnmarshalEnviron unmarshals the values returned by lookup to the fields of
+// struct v. When lookup is nil, os.LookupEnv is used. See
+// UnmarshalEnvironFunc for additional details.
+func (u *Unmarshaler) UnmarshalEnviron(prefix string, lookup LookupFunc, v reflect.Value) error {
+	if err := validDest(v); err != nil {
+		return err
+	}
+	return u.unmarshalEnviron(prefix, lookup, v)
+}
+
+func (u *Unmarshaler) unmarshalEnviron(prefix string, lookup LookupFunc, v reflect.Value) error {
+	v, err := structDest(v)
+	if err != nil {
+		return err
+	}
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+
+	fields := structFields(v.Type())
+	values := make(map[string]Value, len(fields))
+	for _, field := range fields {
+		if val, ok := lookupEnv(lookup, prefix, field.name); ok {
+			values[field.name] = Value(val)
+		}
+	}
+	return u.unmarshalStruct(values, v, nil)
+}
+
+// lookupEnv looks up the variable prefix+name, or prefix+strings.ToUpper(name)
+// when the former is not present.
+func lookupEnv(lookup LookupFunc, prefix, name string) (string, bool) {
+	if val, ok := lookup(prefix + name); ok {
+		return val, true
+	}
+	if upper := strings.ToUpper(name); upper != name {
+		return lookup(prefix + upper)
+	}
+	return "", false
+}
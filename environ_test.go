@@ -0,0 +1,81 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type environFixture struct {
+	Port    uint16        `raw:"PORT"`
+	Hosts   []string      `raw:"HOSTS"`
+	Timeout time.Duration // Timeout or TIMEOUT
+	Debug   bool          `raw:"debug"`
+	Name    string
+}
+
+func TestUnmarshalEnviron(t *testing.T) {
+	t.Setenv("RAWCONV_TEST_PORT", "8080")
+	t.Setenv("RAWCONV_TEST_HOSTS", "a,b")
+	t.Setenv("RAWCONV_TEST_TIMEOUT", "5s")
+	t.Setenv("RAWCONV_TEST_debug", "true")
+	t.Setenv("PORT", "1234")
+
+	have := environFixture{Name: "untouched"}
+	assert.NoError(t, UnmarshalEnviron("RAWCONV_TEST_", &have))
+	assert.Equal(t, environFixture{
+		Port:    8080,
+		Hosts:   []string{"a", "b"},
+		Timeout: 5 * time.Second,
+		Debug:   true,
+		Name:    "untouched",
+	}, have)
+}
+
+func TestUnmarshalEnvironFunc(t *testing.T) {
+	lookup := func(env map[string]string) LookupFunc {
+		return func(key string) (string, bool) {
+			val, ok := env[key]
+			return val, ok
+		}
+	}
+
+	t.Run("exact name first", func(t *testing.T) {
+		var have environFixture
+		assert.NoError(t, UnmarshalEnvironFunc("", lookup(map[string]string{
+			"Timeout": "1s",
+			"TIMEOUT": "2s",
+			"NAME":    "foo",
+		}), &have))
+		assert.Equal(t, environFixture{Timeout: time.Second, Name: "foo"}, have)
+	})
+	t.Run("error", func(t *testing.T) {
+		var have environFixture
+		err := UnmarshalEnvironFunc("APP_", lookup(map[string]string{
+			"APP_PORT": "x",
+		}), &have)
+		assert.ErrorIs(t, err, ErrParseFailure)
+		assert.ErrorContains(t, err, "field `PORT`")
+	})
+	t.Run("invalid dest", func(t *testing.T) {
+		var have environFixture
+		assert.ErrorIs(t, UnmarshalEnvironFunc("", nil, have), ErrPointerExpected)
+
+		var port int
+		assert.ErrorIs(t, UnmarshalEnvironFunc("", nil, &port), ErrStructExpected)
+	})
+	t.Run("unmarshaler", func(t *testing.T) {
+		u := Unmarshaler{Options: Options{ItemsSeparator: ";"}}
+		var have environFixture
+		assert.NoError(t, u.UnmarshalEnviron("", lookup(map[string]string{
+			"HOSTS": "a;b",
+		}), reflect.ValueOf(&have)))
+		assert.Equal(t, []string{"a", "b"}, have.Hosts)
+	})
+}
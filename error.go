@@ -5,8 +5,10 @@
 package rawconv
 
 import (
+	"encoding/json"
 	"reflect"
 	"strconv"
+	"strings"
 
 	"github.com/go-pogo/errors"
 )
@@ -25,11 +27,51 @@ func (e *UnsupportedTypeError) Error() string {
 	return "type `" + e.Type.String() + "` is not supported"
 }
 
+// AmbiguousTypeError is returned in Options.Strict mode, when a type is not
+// registered itself but matches multiple registered interfaces with
+// different funcs.
+type AmbiguousTypeError struct {
+	Type       reflect.Type
+	Candidates []reflect.Type
+}
+
+func (e *AmbiguousTypeError) Is(err error) bool {
+	//goland:noinspection GoTypeAssertionOnErrors
+	t, ok := err.(*AmbiguousTypeError)
+	return ok && e.Type == t.Type
+}
+
+func (e *AmbiguousTypeError) Error() string {
+	var buf strings.Builder
+	buf.WriteString("type `")
+	buf.WriteString(e.Type.String())
+	buf.WriteString("` matches multiple interfaces: ")
+	for i, c := range e.Candidates {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString("`" + c.String() + "`")
+	}
+	return buf.String()
+}
+
 const (
 	ErrParseFailure      errors.Msg = "failed to parse"
 	ErrValidationFailure errors.Msg = "failed to validate"
 )
 
+// parseErr wraps a non-nil err, returned by one of the strconv parse funcs,
+// with its kind. It returns nil without allocating when err is nil.
+func parseErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	if kind := errKind(err); kind != nil {
+		return errors.Wrap(err, kind)
+	}
+	return errors.WithStack(err)
+}
+
 func errKind(err error) error {
 	var numErr *strconv.NumError
 	if errors.As(err, &numErr) {
@@ -41,3 +83,161 @@ func errKind(err error) error {
 	}
 	return nil
 }
+
+// ParseError is returned when a raw Value fails to unmarshal to a value of
+// (non-pointer) Type, because it cannot be parsed or fails validation. It
+// wraps the underlying error, which is (wrapped by) ErrParseFailure,
+// ErrValidationFailure or, for a map item without key value separator,
+// ErrMapInvalidFormat. Value is the raw value as it was passed to the
+// Unmarshaler, before it is verified or decrypted.
+type ParseError struct {
+	Value Value
+	Type  reflect.Type
+	Err   error
+}
+
+func (e *ParseError) Unwrap() error { return e.Err }
+
+func (e *ParseError) Error() string {
+	var buf strings.Builder
+	buf.WriteString("cannot parse ")
+	buf.WriteString(strconv.Quote(e.Value.String()))
+	if e.Type != nil {
+		buf.WriteString(" as ")
+		buf.WriteString(e.Type.String())
+	}
+	if e.Err != nil {
+		buf.WriteString(": ")
+		buf.WriteString(e.Err.Error())
+	}
+	return buf.String()
+}
+
+// parseError wraps err in a ParseError when it is a parse or validation
+// failure, which is not already wrapped in a ParseError by a nested item.
+func parseError(err error, val Value, typ reflect.Type) error {
+	if !errors.Is(err, ErrParseFailure) && !errors.Is(err, ErrValidationFailure) {
+		return err
+	}
+
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		return err
+	}
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return &ParseError{Value: val, Type: typ, Err: err}
+}
+
+// UnmarshalError is returned when an item of an array, slice or map fails to
+// unmarshal. Index is the position of the item within the raw value, Key
+// contains the raw key of a map entry and is empty for arrays and slices.
+// It is also returned by MarshalURLValues, when an entry of a map or an item
+// of a collection fails to marshal.
+type UnmarshalError struct {
+	Index int
+	Key   string
+	Err   error
+}
+
+func (e *UnmarshalError) Unwrap() error { return e.Err }
+
+func (e *UnmarshalError) Error() string {
+	var buf strings.Builder
+	if e.Key != "" {
+		buf.WriteString("key `")
+		buf.WriteString(e.Key)
+		buf.WriteString("`")
+	} else {
+		buf.WriteString("index ")
+		buf.WriteString(strconv.Itoa(e.Index))
+	}
+	if e.Err != nil {
+		buf.WriteString(": ")
+		buf.WriteString(e.Err.Error())
+	}
+	return buf.String()
+}
+
+// FieldError is returned when the value of a struct field fails to
+// (un)marshal. Field is the name of the field, as determined by its tag.
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+// fieldErr wraps err, which occurred while (un)marshaling the field with
+// name, in a FieldError.
+func fieldErr(name string, err error) error {
+	return errors.WithStack(&FieldError{Field: name, Err: err})
+}
+
+func (e *FieldError) Unwrap() error { return e.Err }
+
+func (e *FieldError) Error() string {
+	if e.Err == nil {
+		return "field `" + e.Field + "`"
+	}
+	return "field `" + e.Field + "`: " + e.Err.Error()
+}
+
+type errorJSON struct {
+	Value    string `json:"value"`
+	Type     string `json:"type"`
+	Reason   string `json:"reason"`
+	Position string `json:"position"`
+}
+
+// ErrorToJSON returns a json document which describes err in a machine
+// readable way, e.g. to return it from an HTTP API which validates user
+// supplied settings. It returns nil when err is nil.
+//
+//	{"value":"x","type":"int","reason":"invalid syntax","position":"Ports[1]"}
+//
+// Value and type are taken from the ParseError, UnsupportedTypeError or
+// AmbiguousTypeError err wraps, reason is the message of the root cause of
+// err. Position is the location of the failed value, composed of the names of
+// the FieldErrors and the indexes and keys of the UnmarshalErrors err wraps,
+// similar to the path argument of Options.OnSkipEmpty.
+func ErrorToJSON(err error) []byte {
+	if err == nil {
+		return nil
+	}
+
+	var doc errorJSON
+	var pos strings.Builder
+	for _, e := range errors.UnwrapAll(err) {
+		//goland:noinspection GoTypeAssertionOnErrors
+		switch e := e.(type) {
+		case *FieldError:
+			if pos.Len() != 0 {
+				pos.WriteByte('.')
+			}
+			pos.WriteString(e.Field)
+		case *UnmarshalError:
+			pos.WriteByte('[')
+			if e.Key != "" {
+				pos.WriteString(e.Key)
+			} else {
+				pos.WriteString(strconv.Itoa(e.Index))
+			}
+			pos.WriteByte(']')
+		case *ParseError:
+			doc.Value = e.Value.String()
+			if e.Type != nil {
+				doc.Type = e.Type.String()
+			}
+		case *UnsupportedTypeError:
+			doc.Type = e.Type.String()
+		case *AmbiguousTypeError:
+			doc.Type = e.Type.String()
+		}
+	}
+
+	doc.Reason = errors.Cause(err).Error()
+	doc.Position = pos.String()
+
+	b, _ := json.Marshal(doc)
+	return b
+}
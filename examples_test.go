@@ -5,12 +5,15 @@
 package rawconv
 
 import (
+	"encoding/json"
 	"fmt"
+	"net"
 	"net/url"
 	"reflect"
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/go-pogo/errors"
 )
 
 // Below example demonstrates how to unmarshal a raw string into a
@@ -95,3 +98,180 @@ func ExampleUnmarshaler_Register() {
 	//  something: (string) (len=10) "some value"
 	// }
 }
+
+func ExampleUnmarshaler_Unmarshal_map() {
+	var u Unmarshaler
+	u.ItemsSeparator = ";"
+	u.KeyValueSeparator = ":"
+
+	var target map[string]int
+	if err := u.Unmarshal("foo:1;bar:2", reflect.ValueOf(&target)); err != nil {
+		panic(err)
+	}
+
+	fmt.Println(target["foo"], target["bar"])
+	// Output: 1 2
+}
+
+func ExampleUnmarshaler_Unmarshal_timeLayout() {
+	var u Unmarshaler
+	u.TimeLayout = "02/01/2006"
+
+	var target time.Time
+	if err := u.Unmarshal("29/08/1997", reflect.ValueOf(&target)); err != nil {
+		panic(err)
+	}
+
+	fmt.Println(target.Format(time.DateOnly))
+	// Output: 1997-08-29
+}
+
+func ExampleMarshaler_Marshal_bytesEncoding() {
+	type token []byte
+
+	var m Marshaler
+	m.BytesEncoding = BytesHex
+
+	val, err := m.Marshal(reflect.ValueOf(token("rawconv")))
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(val.String())
+	// Output: 726177636f6e76
+}
+
+func ExampleMarshaler_Marshal_rawJSON() {
+	var m Marshaler
+	val, err := m.Marshal(reflect.ValueOf(json.RawMessage(`{"foo":"bar"}`)))
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(val.String())
+	// Output: {"foo":"bar"}
+}
+
+func ExampleIntFormat() {
+	type rgb uint32
+
+	f := IntFormat{Prefix: "#", Base: 16, Width: 6, ZeroPad: true}
+
+	var m Marshaler
+	m.Register(reflect.TypeOf(rgb(0)), f.Marshal)
+
+	val, err := m.Marshal(reflect.ValueOf(rgb(0x00ff00)))
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(val.String())
+	// Output: #00ff00
+}
+
+func ExampleValue_Time() {
+	t, err := Value("29 Aug 1997").Time()
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(t.Format(time.RFC3339))
+	// Output: 1997-08-29T00:00:00Z
+}
+
+func ExampleOptions_onSkipEmpty() {
+	var u Unmarshaler
+	u.OnSkipEmpty = func(path string, typ reflect.Type) {
+		fmt.Println("skipped", path, typ)
+	}
+
+	var target []int
+	if err := u.Unmarshal("1,,3", reflect.ValueOf(&target)); err != nil {
+		panic(err)
+	}
+
+	fmt.Println(target)
+	// Output:
+	// skipped [1] int
+	// [1 0 3]
+}
+
+// Below example demonstrates how to classify the errors returned by
+// Unmarshal.
+func ExampleUnsupportedTypeError() {
+	var target chan struct{}
+	err := Unmarshal("some value", &target)
+
+	var unsupportedErr *UnsupportedTypeError
+	if errors.As(err, &unsupportedErr) {
+		fmt.Println(unsupportedErr.Type)
+	}
+
+	var num int
+	err = Unmarshal("not a number", &num)
+	fmt.Println(errors.Is(err, ErrParseFailure))
+
+	// Output:
+	// *chan struct {}
+	// true
+}
+
+func ExampleUnmarshaler_Unmarshal_nested() {
+	var u Unmarshaler
+	u.NestedBrackets = true
+
+	var matrix [][]int
+	if err := u.Unmarshal("[1,2],[3,4]", reflect.ValueOf(&matrix)); err != nil {
+		panic(err)
+	}
+
+	fmt.Println(matrix)
+	// Output: [[1 2] [3 4]]
+}
+
+func ExampleUnmarshalStruct() {
+	type config struct {
+		Host    string        `raw:"host"`
+		Port    uint16        `raw:"port"`
+		Timeout time.Duration `raw:"timeout"`
+	}
+
+	var conf config
+	err := UnmarshalStruct(map[string]Value{
+		"host":    "localhost",
+		"port":    "8080",
+		"timeout": "5s",
+	}, &conf)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("%+v\n", conf)
+	// Output: {Host:localhost Port:8080 Timeout:5s}
+}
+
+func ExampleRegisterUnmarshalFuncFor() {
+	RegisterUnmarshalFuncFor[net.HardwareAddr](UnmarshalFuncOf(net.ParseMAC))
+	defer DeregisterUnmarshalFunc(reflect.TypeOf(net.HardwareAddr{}))
+
+	var mac net.HardwareAddr
+	if err := Unmarshal("00:00:5e:00:53:01", &mac); err != nil {
+		panic(err)
+	}
+
+	fmt.Println([]byte(mac))
+	// Output: [0 0 94 0 83 1]
+}
+
+func ExampleRegisterMarshalFuncFor() {
+	RegisterMarshalFuncFor[net.HardwareAddr](MarshalFuncOf(net.HardwareAddr.String))
+	defer DeregisterMarshalFunc(reflect.TypeOf(net.HardwareAddr{}))
+
+	val, err := Marshal(net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1})
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(val)
+	// Output: 00:00:5e:00:53:01
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"flag"
+	"reflect"
+)
+
+const panicFlagVarPointer = "rawconv: FlagVar requires a non-nil pointer"
+
+// FlagVar returns a flag.Value, which also implements flag.Getter, that sets
+// the value p points to using Unmarshal. Any type supported by Unmarshal,
+// including registered custom types, can be bound to a flag this way. It
+// panics when p is not a non-nil pointer.
+//
+//	var timeout time.Duration
+//	flag.Var(rawconv.FlagVar(&timeout), "timeout", "request timeout")
+//
+// Each call to Set unmarshals the complete raw value, which means a slice or
+// map is replaced instead of appended to. Multiple items are set using the
+// items separator, e.g. "-hosts=a,b".
+func FlagVar(p any) flag.Value {
+	rv := reflect.ValueOf(p)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		panic(panicFlagVarPointer)
+	}
+	return &flagValue{ptr: rv}
+}
+
+var _ flag.Getter = (*flagValue)(nil)
+
+type flagValue struct {
+	ptr reflect.Value
+}
+
+// Set unmarshals str to the value the flagValue points to.
+func (f *flagValue) Set(str string) error {
+	u := Unmarshaler{Options: GlobalOptions()}
+	return u.Unmarshal(Value(str), f.ptr)
+}
+
+// String returns the marshaled value the flagValue points to. It returns an
+// empty string when this value is its type's zero value, because the flag
+// package compares it with the String of a zero flagValue, which has no type,
+// to determine if a flag's default value should be printed.
+func (f *flagValue) String() string {
+	if f == nil || !f.ptr.IsValid() || f.ptr.Elem().IsZero() {
+		return ""
+	}
+
+	m := Marshaler{Options: GlobalOptions()}
+	val, err := m.Marshal(f.ptr.Elem())
+	if err != nil {
+		return ""
+	}
+	return val.String()
+}
+
+// Get returns the value the flagValue points to.
+func (f *flagValue) Get() any { return f.ptr.Elem().Interface() }
+
+// IsBoolFlag indicates the flag can be set without a value, e.g. "-debug"
+// instead of "-debug=true", when it points to a bool.
+func (f *flagValue) IsBoolFlag() bool { return f.ptr.Elem().Kind() == reflect.Bool }
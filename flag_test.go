@@ -0,0 +1,72 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlagVar(t *testing.T) {
+	t.Run("parse", func(t *testing.T) {
+		var (
+			timeout = time.Second
+			hosts   []string
+			limits  map[string]ByteSize
+			debug   bool
+		)
+
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(FlagVar(&timeout), "timeout", "")
+		fs.Var(FlagVar(&hosts), "hosts", "")
+		fs.Var(FlagVar(&limits), "limits", "")
+		fs.Var(FlagVar(&debug), "debug", "")
+
+		assert.NoError(t, fs.Parse([]string{
+			"-timeout=5s",
+			"-hosts", "a,b",
+			"-limits=mem=1GiB,disk=10GB",
+			"-debug",
+		}))
+		assert.Equal(t, 5*time.Second, timeout)
+		assert.Equal(t, []string{"a", "b"}, hosts)
+		assert.Equal(t, map[string]ByteSize{"mem": GiB, "disk": 10 * GB}, limits)
+		assert.True(t, debug)
+
+		assert.Equal(t, "5s", fs.Lookup("timeout").Value.String())
+		assert.Equal(t, hosts, fs.Lookup("hosts").Value.(flag.Getter).Get())
+	})
+	t.Run("invalid value", func(t *testing.T) {
+		var port uint16
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(&bytes.Buffer{})
+		fs.Var(FlagVar(&port), "port", "")
+
+		assert.Error(t, fs.Parse([]string{"-port=-1"}))
+		assert.Equal(t, uint16(0), port)
+	})
+	t.Run("defaults", func(t *testing.T) {
+		timeout := time.Minute
+		var zero int
+
+		var buf bytes.Buffer
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(&buf)
+		fs.Var(FlagVar(&timeout), "timeout", "request timeout")
+		fs.Var(FlagVar(&zero), "zero", "zero value")
+		fs.PrintDefaults()
+
+		assert.Contains(t, buf.String(), "(default 1m0s)")
+		assert.NotContains(t, buf.String(), "(default 0)")
+	})
+	t.Run("panic", func(t *testing.T) {
+		assert.PanicsWithValue(t, panicFlagVarPointer, func() { FlagVar(1) })
+		assert.PanicsWithValue(t, panicFlagVarPointer, func() { FlagVar((*int)(nil)) })
+	})
+}
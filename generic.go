@@ -0,0 +1,165 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/go-pogo/errors"
+)
+
+// As unmarshals Value val to a new value of type T, using the Options set
+// with SetGlobalOptions. See Unmarshal for additional details.
+//
+//	port, err := rawconv.As[uint16]("8080")
+func As[T any](val Value) (T, error) {
+	var v T
+	u := Unmarshaler{Options: GlobalOptions()}
+	err := u.unmarshal(context.Background(), val, reflect.ValueOf(&v), "", 0)
+	return v, err
+}
+
+// From marshals v of type T to a Value, using the Options set with
+// SetGlobalOptions. See Marshal for additional details.
+//
+//	val, err := rawconv.From(time.Minute)
+func From[T any](v T) (Value, error) {
+	m := Marshaler{Options: GlobalOptions()}
+	return m.Marshal(reflect.ValueOf(&v).Elem())
+}
+
+// FormatAs formats v of type T to its raw string representation, using the
+// Options set with SetGlobalOptions. When a MarshalFunc is registered for the
+// concrete type of v, it is called directly with v instead of with a
+// reflect.Value wrapping it. This makes FormatAs faster than Marshal for
+// registered types, e.g. in hot logging paths. Any other value, or a value of
+// a registered type when Options.Strict is set, is marshaled the same way as
+// with Marshal.
+//
+//	str, err := rawconv.FormatAs(time.Minute)
+func FormatAs[T any](v T) (string, error) {
+	x := any(v)
+	typ := reflect.TypeOf(x)
+
+	opts := GlobalOptions()
+	if typ != nil && typ.Kind() != reflect.Ptr && !opts.Strict {
+		if fn := formatFunc(typ, opts); fn != nil {
+			str, err := fn(x)
+			if err == nil {
+				return str, nil
+			}
+			if !errors.Is(err, ErrSkip) {
+				return str, funcErr(err)
+			}
+		}
+	}
+
+	m := Marshaler{Options: opts}
+	return m.marshal(context.Background(), reflect.ValueOf(&v).Elem(), 0)
+}
+
+// formatFunc returns the globally registered MarshalFunc, which can be called
+// directly with a value of typ, or nil if there is none.
+func formatFunc(typ reflect.Type, opts Options) MarshalFunc {
+	if fn, _, addr := marshaler.register.resolve(typ, opts); fn != nil {
+		if addr {
+			return nil
+		}
+		return fn
+	}
+	fn, _ := marshaler.register.resolveKind(typ)
+	return fn
+}
+
+const panicGenericInterface = "rawconv: RegisterUnmarshal requires a non-interface type, use RegisterUnmarshalFunc instead"
+
+// RegisterUnmarshal globally registers fn as UnmarshalFunc for type T. Unlike
+// an UnmarshalFunc, fn receives a typed pointer to its destination. T must
+// not be an interface type, because the destination of an interface is a
+// pointer to the concrete type which implements it. See
+// RegisterUnmarshalFunc for additional details.
+//
+//	rawconv.RegisterUnmarshal(func(val rawconv.Value, dest *net.HardwareAddr) (err error) {
+//		*dest, err = net.ParseMAC(val.String())
+//		return err
+//	})
+func RegisterUnmarshal[T any](fn func(val Value, dest *T) error) {
+	typ := typeFor[T]()
+	if typ.Kind() == reflect.Interface {
+		panic(panicGenericInterface)
+	}
+
+	RegisterUnmarshalFunc(typ, func(val Value, dest any) error {
+		return fn(val, dest.(*T))
+	})
+}
+
+// RegisterMarshal globally registers fn as MarshalFunc for type T. Unlike a
+// MarshalFunc, fn receives a typed value. See RegisterMarshalFunc for
+// additional details.
+//
+//	rawconv.RegisterMarshal(func(v net.HardwareAddr) (string, error) {
+//		return v.String(), nil
+//	})
+func RegisterMarshal[T any](fn func(v T) (string, error)) {
+	RegisterMarshalFunc(typeFor[T](), func(v any) (string, error) {
+		return fn(v.(T))
+	})
+}
+
+// RegisterUnmarshalFuncFor globally registers fn as UnmarshalFunc for type T,
+// without the need to get its reflect.Type first. It is equal to
+// RegisterUnmarshalFunc(reflect.TypeFor[T](), fn). Unlike RegisterUnmarshal,
+// T may be an interface type.
+//
+//	rawconv.RegisterUnmarshalFuncFor[net.HardwareAddr](rawconv.UnmarshalFuncOf(net.ParseMAC))
+func RegisterUnmarshalFuncFor[T any](fn UnmarshalFunc) {
+	RegisterUnmarshalFunc(typeFor[T](), fn)
+}
+
+// RegisterMarshalFuncFor globally registers fn as MarshalFunc for type T,
+// without the need to get its reflect.Type first. It is equal to
+// RegisterMarshalFunc(reflect.TypeFor[T](), fn).
+//
+//	rawconv.RegisterMarshalFuncFor[net.HardwareAddr](rawconv.MarshalFuncOf(net.HardwareAddr.String))
+func RegisterMarshalFuncFor[T any](fn MarshalFunc) {
+	RegisterMarshalFunc(typeFor[T](), fn)
+}
+
+// UnmarshalFuncOf adapts parse to an UnmarshalFunc, which sets the parsed
+// value to a destination of type *T. Like the builtin UnmarshalFuncs, it
+// leaves the destination untouched when Value is empty.
+//
+//	rawconv.RegisterUnmarshalFunc(
+//		reflect.TypeOf(net.HardwareAddr{}),
+//		rawconv.UnmarshalFuncOf(net.ParseMAC),
+//	)
+func UnmarshalFuncOf[T any](parse func(string) (T, error)) UnmarshalFunc {
+	return func(val Value, dest any) error {
+		if val.IsEmpty() {
+			return nil
+		}
+
+		x, err := parse(val.String())
+		if err != nil {
+			return err
+		}
+		*dest.(*T) = x
+		return nil
+	}
+}
+
+// MarshalFuncOf adapts format to a MarshalFunc for values of type T.
+//
+//	rawconv.RegisterMarshalFunc(
+//		reflect.TypeOf(net.HardwareAddr{}),
+//		rawconv.MarshalFuncOf(net.HardwareAddr.String),
+//	)
+func MarshalFuncOf[T any](format func(T) string) MarshalFunc {
+	return func(v any) (string, error) {
+		return format(v.(T)), nil
+	}
+}
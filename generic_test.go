@@ -0,0 +1,224 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAs(t *testing.T) {
+	port, err := As[uint16]("8080")
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(8080), port)
+
+	u, err := As[*url.URL]("http://localhost")
+	assert.NoError(t, err)
+	assert.Equal(t, &url.URL{Scheme: "http", Host: "localhost"}, u)
+
+	list, err := As[[]time.Duration]("1s,1m")
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{time.Second, time.Minute}, list)
+
+	_, err = As[int]("x")
+	assert.ErrorIs(t, err, ErrParseFailure)
+
+	_, err = As[chan int]("x")
+	assert.ErrorIs(t, err, &UnsupportedTypeError{Type: reflect.TypeOf((*chan int)(nil))})
+}
+
+func TestFrom(t *testing.T) {
+	val, err := From(time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, Value("1m0s"), val)
+
+	val, err = From([]bool{true, false})
+	assert.NoError(t, err)
+	assert.Equal(t, Value("true,false"), val)
+
+	val, err = From[*int](nil)
+	assert.NoError(t, err)
+	assert.Equal(t, Value(""), val)
+
+	_, err = From[any](nil)
+	assert.ErrorIs(t, err, &UnsupportedTypeError{Type: reflect.TypeOf((*any)(nil)).Elem()})
+}
+
+func TestFormatAs(t *testing.T) {
+	t.Run("registered", func(t *testing.T) {
+		have, err := FormatAs(time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, "1m0s", have)
+
+		have, err = FormatAs(url.URL{Scheme: "http", Host: "localhost"})
+		assert.NoError(t, err)
+		assert.Equal(t, "http://localhost", have)
+	})
+	t.Run("not registered", func(t *testing.T) {
+		have, err := FormatAs([]int{1, 2})
+		assert.NoError(t, err)
+		assert.Equal(t, "1,2", have)
+
+		have, err = FormatAs(time.UTC)
+		assert.NoError(t, err)
+		assert.Equal(t, "UTC", have)
+
+		have, err = FormatAs[*time.Duration](nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "", have)
+	})
+	t.Run("interface", func(t *testing.T) {
+		have, err := FormatAs[any](time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, "1s", have)
+
+		_, err = FormatAs[any](nil)
+		assert.ErrorIs(t, err, &UnsupportedTypeError{Type: reflect.TypeOf((*any)(nil)).Elem()})
+	})
+	t.Run("error", func(t *testing.T) {
+		type failing struct{}
+		typ := reflect.TypeOf(failing{})
+		RegisterMarshal(func(failing) (string, error) { return "", ErrUnableToSet })
+		defer DeregisterMarshalFunc(typ)
+
+		_, err := FormatAs(failing{})
+		assert.ErrorIs(t, err, ErrUnableToSet)
+	})
+}
+
+func BenchmarkFormatAs(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = FormatAs(time.Minute)
+	}
+}
+
+func BenchmarkMarshal_registered(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = Marshal(time.Minute)
+	}
+}
+
+func TestUnmarshalFuncOf(t *testing.T) {
+	typ := reflect.TypeOf(net.HardwareAddr{})
+
+	var u Unmarshaler
+	u.Register(typ, UnmarshalFuncOf(net.ParseMAC))
+
+	var have net.HardwareAddr
+	assert.NoError(t, u.Unmarshal("00:00:5e:00:53:01", reflect.ValueOf(&have)))
+	assert.Equal(t, net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1}, have)
+
+	assert.NoError(t, u.Unmarshal("", reflect.ValueOf(&have)))
+	assert.Equal(t, net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1}, have, "empty value should be skipped")
+
+	var list []net.HardwareAddr
+	assert.NoError(t, u.Unmarshal("00:00:5e:00:53:01, 00:00:5e:00:53:02", reflect.ValueOf(&list)))
+	assert.Len(t, list, 2)
+
+	var addrErr *net.AddrError
+	assert.ErrorAs(t, u.Unmarshal("invalid", reflect.ValueOf(&have)), &addrErr)
+}
+
+func TestMarshalFuncOf(t *testing.T) {
+	typ := reflect.TypeOf(net.HardwareAddr{})
+
+	var m Marshaler
+	m.Register(typ, MarshalFuncOf(net.HardwareAddr.String))
+
+	have, err := m.Marshal(reflect.ValueOf(net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1}))
+	assert.NoError(t, err)
+	assert.Equal(t, Value("00:00:5e:00:53:01"), have)
+
+	have, err = m.Marshal(reflect.ValueOf(&net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 2}))
+	assert.NoError(t, err)
+	assert.Equal(t, Value("00:00:5e:00:53:02"), have)
+}
+
+func TestRegisterUnmarshal(t *testing.T) {
+	typ := reflect.TypeOf(net.HardwareAddr{})
+	t.Cleanup(func() { DeregisterUnmarshalFunc(typ) })
+
+	RegisterUnmarshal(func(val Value, dest *net.HardwareAddr) (err error) {
+		*dest, err = net.ParseMAC(val.String())
+		return err
+	})
+
+	var have []net.HardwareAddr
+	assert.NoError(t, Unmarshal("00:00:5e:00:53:01,00:00:5e:00:53:02", &have))
+	assert.Equal(t, []net.HardwareAddr{
+		{0, 0, 0x5e, 0, 0x53, 1},
+		{0, 0, 0x5e, 0, 0x53, 2},
+	}, have)
+
+	t.Run("interface", func(t *testing.T) {
+		assert.PanicsWithValue(t, panicGenericInterface, func() {
+			RegisterUnmarshal(func(Value, *fmt.Stringer) error { return nil })
+		})
+	})
+}
+
+func TestRegisterMarshal(t *testing.T) {
+	typ := reflect.TypeOf(net.HardwareAddr{})
+	t.Cleanup(func() { DeregisterMarshalFunc(typ) })
+
+	RegisterMarshal(func(v net.HardwareAddr) (string, error) {
+		return v.String(), nil
+	})
+
+	have, err := Marshal(&net.HardwareAddr{0, 0, 0x5e, 0, 0x53, 1})
+	assert.NoError(t, err)
+	assert.Equal(t, Value("00:00:5e:00:53:01"), have)
+
+	t.Run("interface", func(t *testing.T) {
+		stringer := reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+		t.Cleanup(func() { DeregisterMarshalFunc(stringer) })
+
+		RegisterMarshal(func(v fmt.Stringer) (string, error) {
+			return "stringer:" + v.String(), nil
+		})
+
+		have, err := Marshal(time.Month(8))
+		assert.NoError(t, err)
+		assert.Equal(t, Value("stringer:August"), have)
+	})
+}
+
+func TestRegisterFuncFor(t *testing.T) {
+	typ := reflect.TypeOf(net.HardwareAddr{})
+	t.Cleanup(func() {
+		DeregisterUnmarshalFunc(typ)
+		DeregisterMarshalFunc(typ)
+	})
+
+	RegisterUnmarshalFuncFor[net.HardwareAddr](UnmarshalFuncOf(net.ParseMAC))
+	RegisterMarshalFuncFor[net.HardwareAddr](MarshalFuncOf(net.HardwareAddr.String))
+	assert.NotNil(t, GetUnmarshalFunc(typ))
+	assert.NotNil(t, GetMarshalFunc(typ))
+
+	t.Run("interface", func(t *testing.T) {
+		stringer := reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+		t.Cleanup(func() { DeregisterMarshalFunc(stringer) })
+
+		RegisterMarshalFuncFor[fmt.Stringer](func(v any) (string, error) {
+			return "stringer:" + v.(fmt.Stringer).String(), nil
+		})
+
+		have, err := Marshal(time.Month(8))
+		assert.NoError(t, err)
+		assert.Equal(t, Value("stringer:August"), have)
+	})
+	t.Run("typeFor", func(t *testing.T) {
+		assert.Equal(t, typ, typeFor[net.HardwareAddr]())
+		assert.Equal(t, reflect.TypeOf((*fmt.Stringer)(nil)).Elem(), typeFor[fmt.Stringer]())
+	})
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package interop cross-checks how rawconv interprets raw scalar values
+// against other decoders, such as encoding/json and YAML. It reports the
+// divergences, so teams which replace those decoders with rawconv, e.g. for
+// flat configurations, know exactly which values change meaning.
+//
+//	for _, d := range interop.Check(interop.DefaultInputs, reflect.TypeOf(false), reflect.TypeOf(0)) {
+//		fmt.Println(d)
+//	}
+package interop
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-pogo/rawconv"
+	"gopkg.in/yaml.v3"
+)
+
+// Decoder decodes data to the value pointed to by v, like json.Unmarshal.
+type Decoder struct {
+	Name      string
+	Unmarshal func(data []byte, v any) error
+}
+
+var (
+	// JSON decodes raw values with encoding/json. Because a raw string is not
+	// a json document, the input is decoded as a json string when the
+	// destination is of kind string, unless the input already is one.
+	JSON = Decoder{Name: "json", Unmarshal: unmarshalJSON}
+	// YAML decodes raw values with gopkg.in/yaml.v3.
+	YAML = Decoder{Name: "yaml", Unmarshal: yaml.Unmarshal}
+)
+
+func unmarshalJSON(data []byte, v any) error {
+	if reflect.TypeOf(v).Elem().Kind() == reflect.String &&
+		(len(data) == 0 || data[0] != '"') {
+		data = []byte(strconv.Quote(string(data)))
+	}
+	return json.Unmarshal(data, v)
+}
+
+// DefaultInputs are raw values which are commonly interpreted differently by
+// rawconv, encoding/json and YAML.
+var DefaultInputs = []rawconv.Value{
+	"", "~", "null",
+	"true", "True", "TRUE", "yes", "on", "t", "1", "0",
+	"010", "0o10", "0x10", "0b10", "1_000", "+1", "-0",
+	"1e3", "1.5", ".5", "NaN", ".nan", "Inf", ".inf",
+	"1h30m", "2024-01-02", "foo", `"foo"`,
+}
+
+// Divergence describes a raw input which is interpreted differently by
+// rawconv and another Decoder, when decoding it to a value of Type.
+type Divergence struct {
+	Input   rawconv.Value
+	Type    reflect.Type
+	Decoder string
+	// Rawconv is the result of rawconv, or nil when RawconvErr is set.
+	Rawconv    any
+	RawconvErr error
+	// Other is the result of Decoder, or nil when OtherErr is set.
+	Other    any
+	OtherErr error
+}
+
+func (d Divergence) String() string {
+	var buf strings.Builder
+	buf.WriteString(strconv.Quote(d.Input.String()))
+	buf.WriteString(" as ")
+	buf.WriteString(d.Type.String())
+	buf.WriteString(": rawconv=")
+	writeResult(&buf, d.Rawconv, d.RawconvErr)
+	buf.WriteString(", ")
+	buf.WriteString(d.Decoder)
+	buf.WriteString("=")
+	writeResult(&buf, d.Other, d.OtherErr)
+	return buf.String()
+}
+
+func writeResult(buf *strings.Builder, v any, err error) {
+	if err != nil {
+		buf.WriteString("error(")
+		buf.WriteString(err.Error())
+		buf.WriteString(")")
+		return
+	}
+	buf.WriteString(strconv.Quote(fmtValue(v)))
+}
+
+func fmtValue(v any) string {
+	val, err := rawconv.Marshal(v)
+	if err != nil {
+		return "?"
+	}
+	return val.String()
+}
+
+// Checker cross-checks the results of an Unmarshaler against Decoders.
+type Checker struct {
+	// Unmarshaler is used to unmarshal the raw inputs. When nil, a
+	// rawconv.Unmarshaler with the global Options is used.
+	Unmarshaler *rawconv.Unmarshaler
+	// Decoders are compared against Unmarshaler. When empty, JSON and YAML
+	// are used.
+	Decoders []Decoder
+}
+
+// Check checks each input for each typ with a Checker with default settings.
+// See Checker.Check for additional details.
+func Check(inputs []rawconv.Value, types ...reflect.Type) []Divergence {
+	var c Checker
+	return c.Check(inputs, types...)
+}
+
+// Check decodes each input to a new value of each typ, with both the
+// Unmarshaler and each Decoder, and returns the Divergences in their results.
+// Results diverge when one of them fails and the other does not, or when
+// both succeed but their values differ. Inputs which fail for both are not
+// reported, regardless of their errors.
+func (c *Checker) Check(inputs []rawconv.Value, types ...reflect.Type) []Divergence {
+	u := c.Unmarshaler
+	if u == nil {
+		u = &rawconv.Unmarshaler{Options: rawconv.GlobalOptions()}
+	}
+	decoders := c.Decoders
+	if len(decoders) == 0 {
+		decoders = []Decoder{JSON, YAML}
+	}
+
+	var res []Divergence
+	for _, typ := range types {
+		for _, input := range inputs {
+			have := reflect.New(typ)
+			haveErr := u.Unmarshal(input, have)
+
+			for _, dec := range decoders {
+				other := reflect.New(typ)
+				otherErr := dec.Unmarshal(input.Bytes(), other.Interface())
+
+				if (haveErr != nil) == (otherErr != nil) &&
+					(haveErr != nil || equal(have.Elem(), other.Elem())) {
+					continue
+				}
+
+				d := Divergence{
+					Input:      input,
+					Type:       typ,
+					Decoder:    dec.Name,
+					RawconvErr: haveErr,
+					OtherErr:   otherErr,
+				}
+				if haveErr == nil {
+					d.Rawconv = have.Elem().Interface()
+				}
+				if otherErr == nil {
+					d.Other = other.Elem().Interface()
+				}
+				res = append(res, d)
+			}
+		}
+	}
+	return res
+}
+
+// equal reports if x and y are deeply equal, where NaN floats are equal to
+// each other.
+func equal(x, y reflect.Value) bool {
+	switch x.Kind() {
+	case reflect.Float32, reflect.Float64:
+		if math.IsNaN(x.Float()) && math.IsNaN(y.Float()) {
+			return true
+		}
+	}
+	return reflect.DeepEqual(x.Interface(), y.Interface())
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package interop
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-pogo/rawconv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	boolType := reflect.TypeOf(false)
+	intType := reflect.TypeOf(0)
+
+	t.Run("equal", func(t *testing.T) {
+		assert.Empty(t, Check([]rawconv.Value{"true", "false"}, boolType))
+		assert.Empty(t, Check([]rawconv.Value{"0", "-12", "1337"}, intType))
+		assert.Empty(t, Check([]rawconv.Value{"foo", "true", "1.5"}, reflect.TypeOf("")))
+	})
+	t.Run("diverge", func(t *testing.T) {
+		have := Check([]rawconv.Value{"yes", "0x10"}, boolType, intType)
+		if assert.Len(t, have, 2, "yaml parses 0x10 as 16") {
+			assert.Equal(t, Divergence{
+				Input:      "yes",
+				Type:       boolType,
+				Decoder:    "yaml",
+				RawconvErr: have[0].RawconvErr,
+				Other:      true,
+			}, have[0])
+			assert.ErrorIs(t, have[0].RawconvErr, rawconv.ErrParseFailure)
+
+			assert.Equal(t, "json", have[1].Decoder)
+			assert.Equal(t, rawconv.Value("0x10"), have[1].Input)
+			assert.Equal(t, 16, have[1].Rawconv)
+			assert.Error(t, have[1].OtherErr)
+		}
+	})
+	t.Run("null", func(t *testing.T) {
+		have := Check([]rawconv.Value{"null"}, reflect.TypeOf(""))
+		if assert.Len(t, have, 1) {
+			assert.Equal(t, "yaml", have[0].Decoder)
+			assert.Equal(t, "null", have[0].Rawconv)
+			assert.Equal(t, "", have[0].Other)
+		}
+	})
+	t.Run("both fail", func(t *testing.T) {
+		assert.Empty(t, Check([]rawconv.Value{"foo"}, intType))
+	})
+	t.Run("nan", func(t *testing.T) {
+		assert.True(t, equal(reflect.ValueOf(math.NaN()), reflect.ValueOf(math.NaN())))
+		assert.False(t, equal(reflect.ValueOf(1.0), reflect.ValueOf(math.NaN())))
+	})
+}
+
+func TestChecker_Check(t *testing.T) {
+	c := Checker{
+		Unmarshaler: &rawconv.Unmarshaler{Options: rawconv.Options{DurationUnit: time.Second}},
+		Decoders: []Decoder{{
+			Name: "seconds",
+			Unmarshal: func(data []byte, v any) error {
+				var n int64
+				if err := json.Unmarshal(data, &n); err != nil {
+					return err
+				}
+				*v.(*time.Duration) = time.Duration(n) * time.Second
+				return nil
+			},
+		}},
+	}
+
+	have := c.Check([]rawconv.Value{"30", "1m"}, reflect.TypeOf(time.Second))
+	if assert.Len(t, have, 1) {
+		assert.Equal(t, rawconv.Value("1m"), have[0].Input)
+		assert.Equal(t, time.Minute, have[0].Rawconv)
+		assert.True(t, strings.HasPrefix(have[0].String(), `"1m" as time.Duration: rawconv="1m0s", seconds=error(`))
+	}
+}
+
+func TestDefaultInputs(t *testing.T) {
+	have := Check(DefaultInputs, reflect.TypeOf(false), reflect.TypeOf(0), reflect.TypeOf(0.0), reflect.TypeOf(""))
+	assert.NotEmpty(t, have)
+	for _, d := range have {
+		assert.NotEmpty(t, d.String())
+	}
+}
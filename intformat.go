@@ -0,0 +1,192 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-pogo/errors"
+)
+
+// RegisterIntFormat registers IntFormat f as both MarshalFunc and
+// UnmarshalFunc for typ, making it globally available. It panics when typ is
+// not of an integer kind.
+//
+//	type RGB uint32
+//	rawconv.RegisterIntFormat(reflect.TypeOf(RGB(0)), rawconv.IntFormat{
+//		Prefix:  "#",
+//		Base:    16,
+//		Width:   6,
+//		ZeroPad: true,
+//	})
+func RegisterIntFormat(typ reflect.Type, f IntFormat) {
+	if !isIntKind(typ.Kind()) && !isUintKind(typ.Kind()) {
+		panic(panicIntFormatKind)
+	}
+
+	RegisterUnmarshalFunc(typ, f.Unmarshal)
+	RegisterMarshalFunc(typ, f.Marshal)
+}
+
+const panicIntFormatKind = "rawconv: IntFormat requires a type of integer kind"
+
+// IntFormat describes the raw string representation of an integer kind type.
+// Its Marshal and Unmarshal methods can be registered as MarshalFunc and
+// UnmarshalFunc for any type with an underlying integer type.
+type IntFormat struct {
+	// Prefix is added when marshaling and is removed, when present, before
+	// unmarshaling.
+	Prefix string
+	// Base is used when formatting and parsing the integer, it must be between
+	// 2 and 36. Defaults to 10.
+	Base int
+	// Width is the minimum amount of characters, excluding Prefix, of a
+	// marshaled integer.
+	Width int
+	// ZeroPad pads the marshaled integer up to Width with leading zeros
+	// instead of spaces.
+	ZeroPad bool
+	// ByteOrder, when set, represents the integer as the hexadecimal encoding
+	// of its fixed-width bytes in this byte order, e.g. "0000ff01" for
+	// uint32(0x01ff0000) with binary.LittleEndian. The width is determined by
+	// the size of the type. Base, Width and ZeroPad are ignored.
+	ByteOrder binary.ByteOrder
+}
+
+func (f IntFormat) base() int {
+	if f.Base == 0 {
+		return 10
+	}
+	return f.Base
+}
+
+// Marshal formats v, which must be of an integer kind, according to IntFormat.
+func (f IntFormat) Marshal(v any) (string, error) {
+	rv := reflect.ValueOf(v)
+
+	var str string
+	switch k := rv.Kind(); {
+	case f.ByteOrder != nil && isIntKind(k):
+		str = f.formatBytes(uint64(rv.Int()), rv.Type().Bits())
+	case f.ByteOrder != nil && isUintKind(k):
+		str = f.formatBytes(rv.Uint(), rv.Type().Bits())
+	case isIntKind(k):
+		str = f.pad(strconv.FormatInt(rv.Int(), f.base()))
+	case isUintKind(k):
+		str = f.pad(strconv.FormatUint(rv.Uint(), f.base()))
+	default:
+		return "", errors.WithStack(&UnsupportedTypeError{Type: rv.Type()})
+	}
+
+	return f.Prefix + str, nil
+}
+
+// formatBytes returns the hexadecimal encoding of the bitSize wide bytes of
+// x, in ByteOrder.
+func (f IntFormat) formatBytes(x uint64, bitSize int) string {
+	var buf [8]byte
+	b := buf[:bitSize/8]
+	switch len(b) {
+	case 1:
+		b[0] = byte(x)
+	case 2:
+		f.ByteOrder.PutUint16(b, uint16(x))
+	case 4:
+		f.ByteOrder.PutUint32(b, uint32(x))
+	default:
+		f.ByteOrder.PutUint64(b, x)
+	}
+	return hex.EncodeToString(b)
+}
+
+// parseBytes decodes the hexadecimal encoded, bitSize wide, bytes of str in
+// ByteOrder.
+func (f IntFormat) parseBytes(str string, bitSize int) (uint64, error) {
+	b, err := hex.DecodeString(str)
+	if err != nil {
+		return 0, errors.Wrap(err, ErrParseFailure)
+	}
+	if len(b) != bitSize/8 {
+		return 0, errors.Wrap(strconv.ErrSyntax, ErrParseFailure)
+	}
+
+	switch len(b) {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(f.ByteOrder.Uint16(b)), nil
+	case 4:
+		return uint64(f.ByteOrder.Uint32(b)), nil
+	default:
+		return f.ByteOrder.Uint64(b), nil
+	}
+}
+
+func (f IntFormat) pad(str string) string {
+	n := f.Width - len(str)
+	if n <= 0 {
+		return str
+	}
+	if !f.ZeroPad {
+		return strings.Repeat(" ", n) + str
+	}
+	if str[0] == '-' {
+		return "-" + strings.Repeat("0", n) + str[1:]
+	}
+	return strings.Repeat("0", n) + str
+}
+
+// Unmarshal parses Value according to IntFormat and sets the result to dest,
+// which must be a pointer to a value of an integer kind.
+func (f IntFormat) Unmarshal(val Value, dest any) error {
+	if val.IsEmpty() {
+		return nil
+	}
+
+	rv := reflect.ValueOf(dest).Elem()
+	str := strings.TrimSpace(strings.TrimPrefix(val.String(), f.Prefix))
+
+	var err error
+	switch k := rv.Kind(); {
+	case f.ByteOrder != nil && (isIntKind(k) || isUintKind(k)):
+		bits := rv.Type().Bits()
+		var x uint64
+		if x, err = f.parseBytes(str, bits); err != nil {
+			return err
+		}
+		if isIntKind(k) {
+			// sign extend the bits of x to an int64
+			shift := 64 - bits
+			rv.SetInt(int64(x<<shift) >> shift)
+		} else {
+			rv.SetUint(x)
+		}
+		return nil
+	case isIntKind(k):
+		var x int64
+		x, err = strconv.ParseInt(str, f.base(), rv.Type().Bits())
+		rv.SetInt(x)
+	case isUintKind(k):
+		var x uint64
+		x, err = strconv.ParseUint(str, f.base(), rv.Type().Bits())
+		rv.SetUint(x)
+	default:
+		return errors.WithStack(&UnsupportedTypeError{Type: rv.Type()})
+	}
+
+	return parseErr(err)
+}
+
+func isIntKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUintKind(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uint64
+}
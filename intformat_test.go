@@ -0,0 +1,129 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type rgb uint32
+
+type offset int16
+
+func TestRegisterIntFormat(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		assert.PanicsWithValue(t, panicIntFormatKind, func() {
+			RegisterIntFormat(reflect.TypeOf(""), IntFormat{})
+		})
+	})
+
+	type color uint32
+	RegisterIntFormat(reflect.TypeOf(color(0)), IntFormat{Prefix: "#", Base: 16, Width: 6, ZeroPad: true})
+
+	val, err := Marshal(color(0x00ff00))
+	assert.NoError(t, err)
+	assert.Equal(t, Value("#00ff00"), val)
+
+	var have color
+	assert.NoError(t, Unmarshal("#ff0000", &have))
+	assert.Equal(t, color(0xff0000), have)
+}
+
+func TestIntFormat(t *testing.T) {
+	tests := map[string]struct {
+		format IntFormat
+		input  any
+		want   Value
+	}{
+		"default": {
+			input: rgb(255),
+			want:  "255",
+		},
+		"hex color": {
+			format: IntFormat{Prefix: "#", Base: 16, Width: 6, ZeroPad: true},
+			input:  rgb(0xff),
+			want:   "#0000ff",
+		},
+		"binary": {
+			format: IntFormat{Prefix: "0b", Base: 2, Width: 8, ZeroPad: true},
+			input:  rgb(5),
+			want:   "0b00000101",
+		},
+		"space padded": {
+			format: IntFormat{Width: 4},
+			input:  offset(-12),
+			want:   " -12",
+		},
+		"zero padded negative": {
+			format: IntFormat{Base: 16, Width: 4, ZeroPad: true},
+			input:  offset(-0xa),
+			want:   "-00a",
+		},
+		"big endian": {
+			format: IntFormat{ByteOrder: binary.BigEndian},
+			input:  rgb(0x01ff0000),
+			want:   "01ff0000",
+		},
+		"little endian": {
+			format: IntFormat{ByteOrder: binary.LittleEndian},
+			input:  rgb(0x01ff0000),
+			want:   "0000ff01",
+		},
+		"little endian negative": {
+			format: IntFormat{Prefix: "0x", ByteOrder: binary.LittleEndian},
+			input:  offset(-2),
+			want:   "0xfeff",
+		},
+		"big endian uint8": {
+			format: IntFormat{ByteOrder: binary.BigEndian},
+			input:  uint8(0xab),
+			want:   "ab",
+		},
+		"little endian int64": {
+			format: IntFormat{ByteOrder: binary.LittleEndian},
+			input:  int64(-0x0102030405060708),
+			want:   "f8f8f9fafbfcfdfe",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var u Unmarshaler
+			u.Register(reflect.TypeOf(tc.input), tc.format.Unmarshal)
+			var m Marshaler
+			m.Register(reflect.TypeOf(tc.input), tc.format.Marshal)
+
+			have, err := m.Marshal(reflect.ValueOf(tc.input))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, have)
+
+			rv := reflect.New(reflect.TypeOf(tc.input))
+			assert.NoError(t, u.Unmarshal(have, rv))
+			assert.Equal(t, tc.input, rv.Elem().Interface())
+		})
+	}
+
+	t.Run("overflow", func(t *testing.T) {
+		var have offset
+		err := IntFormat{Base: 16}.Unmarshal("ffffff", &have)
+		assert.ErrorIs(t, err, ErrValidationFailure)
+	})
+	t.Run("invalid width", func(t *testing.T) {
+		var have rgb
+		err := IntFormat{ByteOrder: binary.BigEndian}.Unmarshal("ff01", &have)
+		assert.ErrorIs(t, err, ErrParseFailure)
+		err = IntFormat{ByteOrder: binary.BigEndian}.Unmarshal("0xzz0000", &have)
+		assert.ErrorIs(t, err, ErrParseFailure)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		var have rgb
+		err := IntFormat{Prefix: "#", Base: 16}.Unmarshal("#xyz", &have)
+		assert.ErrorIs(t, err, ErrParseFailure)
+	})
+}
@@ -0,0 +1,185 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"reflect"
+)
+
+// Mechanism describes how a type is (un)marshaled.
+type Mechanism uint8
+
+const (
+	// Unsupported indicates the type cannot be (un)marshaled.
+	Unsupported Mechanism = iota
+	// BuiltinKind indicates the type is (un)marshaled by the builtin support
+	// for its kind, e.g. int, string or slice.
+	BuiltinKind
+	// RegisteredType indicates a func is registered for the (elem) type.
+	RegisteredType
+	// RegisteredInterface indicates a func is registered for an interface,
+	// which is implemented by the type.
+	RegisteredInterface
+	// RegisteredKind indicates a fallback func is registered for the kind of
+	// the type.
+	RegisteredKind
+)
+
+func (m Mechanism) String() string {
+	switch m {
+	case Unsupported:
+		return "unsupported"
+	case BuiltinKind:
+		return "builtin kind"
+	case RegisteredType:
+		return "registered type"
+	case RegisteredInterface:
+		return "registered interface"
+	case RegisteredKind:
+		return "registered kind"
+	default:
+		return "invalid"
+	}
+}
+
+// Conversion describes how a value of one type is converted to a value of
+// another type, by marshaling it to a Value and unmarshaling that Value.
+type Conversion struct {
+	From, To reflect.Type
+	// Marshal is the Mechanism which is used to marshal From.
+	Marshal Mechanism
+	// Unmarshal is the Mechanism which is used to unmarshal To.
+	Unmarshal Mechanism
+}
+
+// Possible indicates if the conversion is possible.
+func (c Conversion) Possible() bool {
+	return c.Marshal != Unsupported && c.Unmarshal != Unsupported
+}
+
+// Compatibility reports if, and how, a value of type from can be converted to
+// a value of type to, using Marshal and Unmarshal with the Options set with
+// SetGlobalOptions.
+func Compatibility(from, to reflect.Type) Conversion {
+	return Conversion{
+		From:      from,
+		To:        to,
+		Marshal:   MarshalMechanism(from),
+		Unmarshal: UnmarshalMechanism(to),
+	}
+}
+
+// UnmarshalMechanism returns the Mechanism which is used by Unmarshal to
+// unmarshal a Value to typ.
+func UnmarshalMechanism(typ reflect.Type) Mechanism {
+	u := Unmarshaler{Options: GlobalOptions()}
+	return u.Mechanism(typ)
+}
+
+// MarshalMechanism returns the Mechanism which is used by Marshal to marshal
+// a value of typ.
+func MarshalMechanism(typ reflect.Type) Mechanism {
+	m := Marshaler{Options: GlobalOptions()}
+	return m.Mechanism(typ)
+}
+
+// Mechanism returns the Mechanism which is used to unmarshal a Value to typ.
+// Arrays, slices and maps are only supported when their items are supported
+// as well, and Null types when their value is.
+func (u *Unmarshaler) Mechanism(typ reflect.Type) Mechanism {
+	return u.mechanism(typ, 0)
+}
+
+// mechanism returns the Mechanism which is used to unmarshal a Value to typ,
+// when it is nested at depth within a collection.
+func (u *Unmarshaler) mechanism(typ reflect.Type, depth int) Mechanism {
+	return u.Options.mechanism(typ, depth, func(typ reflect.Type) Mechanism {
+		if mech := u.register.mechanism(typ); mech != Unsupported {
+			return mech
+		}
+		if mech := unmarshaler.register.mechanism(typ); mech != Unsupported {
+			return mech
+		}
+		if mech := u.register.kindMechanism(typ); mech != Unsupported {
+			return mech
+		}
+		return unmarshaler.register.kindMechanism(typ)
+	})
+}
+
+// Mechanism returns the Mechanism which is used to marshal a value of typ.
+// Arrays, slices and maps are only supported when their items are supported
+// as well, and Null types when their value is.
+func (m *Marshaler) Mechanism(typ reflect.Type) Mechanism {
+	return m.Options.mechanism(typ, 0, func(typ reflect.Type) Mechanism {
+		if mech := m.register.mechanism(typ); mech != Unsupported {
+			return mech
+		}
+		if mech := marshaler.register.mechanism(typ); mech != Unsupported {
+			return mech
+		}
+		if mech := m.register.kindMechanism(typ); mech != Unsupported {
+			return mech
+		}
+		return marshaler.register.kindMechanism(typ)
+	})
+}
+
+func (o Options) mechanism(typ reflect.Type, depth int, registered func(typ reflect.Type) Mechanism) Mechanism {
+	if typ == nil {
+		return Unsupported
+	}
+	if mech := registered(typ); mech != Unsupported {
+		return mech
+	}
+
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	switch typ.Kind() {
+	case reflect.String,
+		reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64,
+		reflect.Complex64, reflect.Complex128:
+		return BuiltinKind
+
+	case reflect.Slice:
+		if typ == jsonRawMessageType {
+			return BuiltinKind
+		}
+		fallthrough
+
+	case reflect.Array:
+		if o.isBinary(typ) {
+			return BuiltinKind
+		}
+		if !o.nestable(depth) ||
+			o.mechanism(typ.Elem(), depth+1, registered) == Unsupported {
+			return Unsupported
+		}
+		return BuiltinKind
+
+	case reflect.Map:
+		if !o.nestable(depth) ||
+			o.mechanism(typ.Key(), depth+1, registered) == Unsupported ||
+			o.mechanism(typ.Elem(), depth+1, registered) == Unsupported {
+			return Unsupported
+		}
+		return BuiltinKind
+
+	case reflect.Struct:
+		if !isNullable(typ) ||
+			o.mechanism(typ.Field(0).Type, depth, registered) == Unsupported {
+			return Unsupported
+		}
+		return BuiltinKind
+
+	default:
+		return Unsupported
+	}
+}
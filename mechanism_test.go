@@ -0,0 +1,78 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalMechanism(t *testing.T) {
+	tests := map[string]struct {
+		typ  reflect.Type
+		want Mechanism
+	}{
+		"nil":              {typ: nil, want: Unsupported},
+		"chan":             {typ: reflect.TypeOf(make(chan int)), want: Unsupported},
+		"struct":           {typ: reflect.TypeOf(struct{}{}), want: Unsupported},
+		"string":           {typ: reflect.TypeOf(""), want: BuiltinKind},
+		"int ptr":          {typ: reflect.TypeOf(ptr(1)), want: BuiltinKind},
+		"raw json":         {typ: reflect.TypeOf(json.RawMessage{}), want: BuiltinKind},
+		"slice":            {typ: reflect.TypeOf([]int{}), want: BuiltinKind},
+		"slice of chans":   {typ: reflect.TypeOf([]chan int{}), want: Unsupported},
+		"nested slice":     {typ: reflect.TypeOf([][]int{}), want: Unsupported},
+		"map":              {typ: reflect.TypeOf(map[string]time.Duration{}), want: BuiltinKind},
+		"map of structs":   {typ: reflect.TypeOf(map[string]struct{}{}), want: Unsupported},
+		"duration":         {typ: reflect.TypeOf(time.Second), want: RegisteredType},
+		"url ptr":          {typ: reflect.TypeOf(&url.URL{}), want: RegisteredType},
+		"text unmarshaler": {typ: reflect.TypeOf(net.IP{}), want: RegisteredInterface},
+		"null":             {typ: reflect.TypeOf(Null[int]{}), want: BuiltinKind},
+		"null ptr":         {typ: reflect.TypeOf(&Null[int]{}), want: BuiltinKind},
+		"slice of nulls":   {typ: reflect.TypeOf([]Null[int]{}), want: BuiltinKind},
+		"null of chan":     {typ: reflect.TypeOf(Null[chan int]{}), want: Unsupported},
+		"sql null":         {typ: reflect.TypeOf(sql.NullInt64{}), want: BuiltinKind},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, UnmarshalMechanism(tc.typ))
+		})
+	}
+
+	t.Run("instance", func(t *testing.T) {
+		type myType struct{}
+
+		var u Unmarshaler
+		u.NestedBrackets = true
+		u.Register(reflect.TypeOf(myType{}), func(Value, any) error { return nil })
+
+		assert.Equal(t, RegisteredType, u.Mechanism(reflect.TypeOf(&myType{})))
+		assert.Equal(t, BuiltinKind, u.Mechanism(reflect.TypeOf([][]myType{})))
+		assert.Equal(t, Unsupported, UnmarshalMechanism(reflect.TypeOf(myType{})))
+	})
+}
+
+func TestCompatibility(t *testing.T) {
+	have := Compatibility(reflect.TypeOf(net.IP{}), reflect.TypeOf([]string{}))
+	assert.Equal(t, RegisteredInterface, have.Marshal)
+	assert.Equal(t, BuiltinKind, have.Unmarshal)
+	assert.True(t, have.Possible())
+
+	have = Compatibility(reflect.TypeOf(""), reflect.TypeOf(struct{}{}))
+	assert.Equal(t, Unsupported, have.Unmarshal)
+	assert.False(t, have.Possible())
+}
+
+func TestMechanism_String(t *testing.T) {
+	assert.Equal(t, "builtin kind", BuiltinKind.String())
+	assert.Equal(t, "invalid", Mechanism(99).String())
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"context"
+	"reflect"
+)
+
+// Use adds middleware mw to the Unmarshaler. Middleware wraps every
+// conversion of a top level Value, e.g. a Value passed to Unmarshal or the
+// Value of a struct field, and can be used for logging, metrics, tracing or
+// normalizing values. The items of arrays, slices and maps are part of the
+// conversion of their collection. Argument dest of the UnmarshalFunc passed
+// to mw is a pointer to the destination; next always unmarshals to this
+// destination, so mw should pass it on unchanged. Middleware which is added
+// first is called first.
+//
+//	u.Use(func(next rawconv.UnmarshalFunc) rawconv.UnmarshalFunc {
+//		return func(val rawconv.Value, dest any) error {
+//			err := next(val, dest)
+//			log.Printf("unmarshal %T: %v", dest, err)
+//			return err
+//		}
+//	})
+//
+// Use is not safe for concurrent use with any of the Unmarshaler's methods.
+func (u *Unmarshaler) Use(mw func(next UnmarshalFunc) UnmarshalFunc) *Unmarshaler {
+	u.middleware = append(u.middleware, mw)
+	return u
+}
+
+func (u *Unmarshaler) unmarshalMiddleware(ctx context.Context, v Value, dest reflect.Value, path string) error {
+	next := UnmarshalFunc(func(val Value, _ any) error {
+		return u.unmarshalValue(ctx, val, dest, path, 0)
+	})
+	for i := len(u.middleware) - 1; i >= 0; i-- {
+		next = u.middleware[i](next)
+	}
+
+	var ptr any
+	if dest.CanAddr() {
+		ptr = dest.Addr().Interface()
+	} else if dest.Kind() == reflect.Ptr {
+		ptr = dest.Interface()
+	}
+	return next(v, ptr)
+}
+
+// Use adds middleware mw to the Marshaler. Middleware wraps every conversion
+// of a top level value, e.g. a value passed to Marshal or the value of a
+// struct field, and can be used for logging, metrics, tracing or redacting
+// values. The items of arrays, slices and maps are part of the conversion of
+// their collection. Argument v of the MarshalFunc passed to mw is the value
+// which is marshaled; next always marshals this value. Middleware which is
+// added first is called first. Collections are no longer streamed by
+// MarshalTo when the Marshaler has middleware.
+//
+// Use is not safe for concurrent use with any of the Marshaler's methods.
+func (m *Marshaler) Use(mw func(next MarshalFunc) MarshalFunc) *Marshaler {
+	m.middleware = append(m.middleware, mw)
+	return m
+}
+
+func (m *Marshaler) marshalMiddleware(ctx context.Context, val reflect.Value) (string, error) {
+	next := MarshalFunc(func(any) (string, error) {
+		return m.marshalValue(ctx, val, 0)
+	})
+	for i := len(m.middleware) - 1; i >= 0; i-- {
+		next = m.middleware[i](next)
+	}
+
+	var v any
+	if val.IsValid() && val.CanInterface() {
+		v = val.Interface()
+	}
+	return next(v)
+}
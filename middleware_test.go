@@ -0,0 +1,126 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshaler_Use(t *testing.T) {
+	var calls []string
+	trace := func(name string) func(next UnmarshalFunc) UnmarshalFunc {
+		return func(next UnmarshalFunc) UnmarshalFunc {
+			return func(val Value, dest any) error {
+				calls = append(calls, name+":"+val.String()+":"+reflect.TypeOf(dest).String())
+				return next(val, dest)
+			}
+		}
+	}
+
+	var u Unmarshaler
+	u.Use(trace("first")).Use(trace("second"))
+
+	t.Run("value", func(t *testing.T) {
+		calls = calls[:0]
+		var have []int
+		assert.NoError(t, u.Unmarshal("1,2", reflect.ValueOf(&have)))
+		assert.Equal(t, []int{1, 2}, have)
+		assert.Equal(t, []string{"first:1,2:*[]int", "second:1,2:*[]int"}, calls)
+	})
+	t.Run("struct fields", func(t *testing.T) {
+		calls = calls[:0]
+		var have struct {
+			Port    int
+			Timeout time.Duration
+		}
+		assert.NoError(t, u.UnmarshalStruct(map[string]Value{
+			"Port":    "80",
+			"Timeout": "1s",
+		}, reflect.ValueOf(&have)))
+		assert.Equal(t, []string{
+			"first:80:*int", "second:80:*int",
+			"first:1s:*time.Duration", "second:1s:*time.Duration",
+		}, calls)
+	})
+	t.Run("reader", func(t *testing.T) {
+		calls = calls[:0]
+		var have []string
+		assert.NoError(t, u.UnmarshalReader(strings.NewReader("a,b"), reflect.ValueOf(&have)))
+		assert.Equal(t, []string{"a", "b"}, have)
+		assert.Len(t, calls, 2)
+	})
+	t.Run("modify value", func(t *testing.T) {
+		var u Unmarshaler
+		u.Use(func(next UnmarshalFunc) UnmarshalFunc {
+			return func(val Value, dest any) error {
+				return next(Value(strings.TrimSuffix(val.String(), "!")), dest)
+			}
+		})
+
+		var have bool
+		assert.NoError(t, u.Unmarshal("true!", reflect.ValueOf(&have)))
+		assert.True(t, have)
+	})
+}
+
+func TestMarshaler_Use(t *testing.T) {
+	var calls []any
+	redact := func(next MarshalFunc) MarshalFunc {
+		return func(v any) (string, error) {
+			calls = append(calls, v)
+			str, err := next(v)
+			if strings.Contains(str, "secret") {
+				return "***", err
+			}
+			return str, err
+		}
+	}
+
+	var m Marshaler
+	m.Use(redact)
+
+	t.Run("marshal", func(t *testing.T) {
+		calls = calls[:0]
+		have, err := m.Marshal(reflect.ValueOf("my secret"))
+		assert.NoError(t, err)
+		assert.Equal(t, Value("***"), have)
+
+		have, err = m.Marshal(reflect.ValueOf([]string{"a", "b"}))
+		assert.NoError(t, err)
+		assert.Equal(t, Value("a,b"), have)
+		assert.Equal(t, []any{"my secret", []string{"a", "b"}}, calls)
+	})
+	t.Run("append", func(t *testing.T) {
+		have, err := m.MarshalAppend([]byte("x="), reflect.ValueOf("secret"))
+		assert.NoError(t, err)
+		assert.Equal(t, "x=***", string(have))
+	})
+	t.Run("write", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, m.MarshalTo(&buf, reflect.ValueOf([]string{"secret", "b"})))
+		assert.Equal(t, "***", buf.String())
+	})
+	t.Run("struct fields", func(t *testing.T) {
+		have, err := m.MarshalStruct(reflect.ValueOf(struct {
+			User     string
+			Password string
+		}{User: "admin", Password: "secret"}))
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]Value{"User": "admin", "Password": "***"}, have)
+	})
+	t.Run("nullable", func(t *testing.T) {
+		calls = calls[:0]
+		have, err := m.Marshal(reflect.ValueOf(NullOf(1)))
+		assert.NoError(t, err)
+		assert.Equal(t, Value("1"), have)
+		assert.Len(t, calls, 1)
+	})
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import "reflect"
+
+// Null represents a value of type T which may be null. It mirrors the
+// nullable types of package database/sql, such as sql.NullString, which are
+// supported in the same way.
+//
+// An empty Value, or a Value which equals Options.NilToken, is unmarshaled to
+// a Null with Valid set to false. Any other Value is unmarshaled to V, after
+// which Valid is set to true. An invalid Null is marshaled to
+// Options.NilToken, which defaults to an empty Value.
+type Null[T any] struct {
+	V     T
+	Valid bool
+}
+
+// NullOf returns a valid Null containing v.
+func NullOf[T any](v T) Null[T] { return Null[T]{V: v, Valid: true} }
+
+func (Null[T]) rawconvNull() {}
+
+// nullType is implemented by Null only, it is used to detect any of its
+// instantiations.
+type nullType interface{ rawconvNull() }
+
+var nullTypeIface = reflect.TypeOf((*nullType)(nil)).Elem()
+
+// isNullable indicates if (the elem type of) typ is a Null, or a nullable
+// type of package database/sql. These are structs with a value as first field,
+// followed by a Valid bool field.
+func isNullable(typ reflect.Type) bool {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct || typ.NumField() != 2 {
+		return false
+	}
+	if valid := typ.Field(1); valid.Name != "Valid" || valid.Type.Kind() != reflect.Bool {
+		return false
+	}
+	return typ.PkgPath() == "database/sql" || typ.Implements(nullTypeIface)
+}
+
+// isNull indicates if Value v represents null.
+func (o Options) isNull(v Value) bool {
+	return v.IsEmpty() || (o.NilToken != "" && v.String() == o.NilToken)
+}
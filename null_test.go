@@ -0,0 +1,117 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNull(t *testing.T) {
+	date := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := map[string]struct {
+		input Value
+		null  any
+		valid any
+	}{
+		"Null[int]": {
+			input: "42",
+			null:  Null[int]{},
+			valid: NullOf(42),
+		},
+		"Null[[]string]": {
+			input: "a,b",
+			null:  Null[[]string]{},
+			valid: NullOf([]string{"a", "b"}),
+		},
+		"NullString": {
+			input: "foo",
+			null:  sql.NullString{},
+			valid: sql.NullString{String: "foo", Valid: true},
+		},
+		"NullInt64": {
+			input: "-64",
+			null:  sql.NullInt64{},
+			valid: sql.NullInt64{Int64: -64, Valid: true},
+		},
+		"NullInt16": {
+			input: "16",
+			null:  sql.NullInt16{},
+			valid: sql.NullInt16{Int16: 16, Valid: true},
+		},
+		"NullBool": {
+			input: "true",
+			null:  sql.NullBool{},
+			valid: sql.NullBool{Bool: true, Valid: true},
+		},
+		"NullFloat64": {
+			input: "1.5",
+			null:  sql.NullFloat64{},
+			valid: sql.NullFloat64{Float64: 1.5, Valid: true},
+		},
+		"NullTime": {
+			input: "2024-01-02T03:04:05Z",
+			null:  sql.NullTime{},
+			valid: sql.NullTime{Time: date, Valid: true},
+		},
+	}
+
+	opts := Options{NilToken: "NULL"}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			typ := reflect.TypeOf(tc.null)
+			u := Unmarshaler{Options: opts}
+			m := Marshaler{Options: opts}
+
+			have := reflect.New(typ)
+			assert.NoError(t, u.Unmarshal(tc.input, have))
+			assert.Equal(t, tc.valid, have.Elem().Interface())
+
+			val, err := m.Marshal(have.Elem())
+			assert.NoError(t, err)
+			assert.Equal(t, tc.input, val)
+
+			for _, null := range []Value{"NULL", ""} {
+				have.Elem().Set(reflect.ValueOf(tc.valid))
+				assert.NoError(t, u.Unmarshal(null, have))
+				assert.Equal(t, tc.null, have.Elem().Interface())
+			}
+
+			val, err = m.Marshal(have.Elem())
+			assert.NoError(t, err)
+			assert.Equal(t, Value("NULL"), val)
+		})
+	}
+
+	t.Run("struct", func(t *testing.T) {
+		type fixture struct {
+			Name sql.NullString
+			Age  Null[uint8]
+		}
+
+		have := fixture{Name: sql.NullString{String: "foo", Valid: true}}
+		_, err := UnmarshalStructAtomic(map[string]Value{"Name": "", "Age": "42"}, &have)
+		assert.NoError(t, err)
+		assert.Equal(t, fixture{Age: NullOf[uint8](42)}, have)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		var have Null[int]
+		assert.ErrorIs(t, Unmarshal("x", &have), ErrParseFailure)
+		assert.False(t, have.Valid)
+	})
+	t.Run("unsupported struct", func(t *testing.T) {
+		type notNull struct {
+			V     int
+			Valid bool
+		}
+		var have notNull
+		assert.ErrorIs(t, Unmarshal("1", &have), &UnsupportedTypeError{Type: reflect.TypeOf(&have)})
+	})
+}
@@ -4,6 +4,12 @@
 
 package rawconv
 
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
 const (
 	DefaultItemsSeparator    = ","
 	DefaultKeyValueSeparator = "="
@@ -12,6 +18,166 @@ const (
 type Options struct {
 	ItemsSeparator    string // ,
 	KeyValueSeparator string // =
+	// BytesEncoding is used to (un)marshal a []byte, and named types with an
+	// underlying byte slice, e.g. type Token []byte. Defaults to BytesRaw.
+	BytesEncoding BytesEncoding
+	// BinaryEncoding is used to (un)marshal types which implement
+	// encoding.BinaryMarshaler or encoding.BinaryUnmarshaler, but not their
+	// text counterparts. Only BytesBase64 and BytesHex are supported, any
+	// other value defaults to BytesBase64.
+	BinaryEncoding BytesEncoding
+	// ValidateRawJSON validates the raw value is valid json before it is
+	// unmarshaled to a json.RawMessage. A json.RawMessage is always passed
+	// through untouched, regardless of BytesEncoding.
+	ValidateRawJSON bool
+	// Strict returns an AmbiguousTypeError when a type is not registered
+	// itself, but matches multiple registered interfaces with different
+	// funcs. Otherwise, the func of the interface which was registered last is
+	// used. Funcs are compared by their code pointer, so closures created
+	// from the same func literal, and method values of the same method, are
+	// seen as the same func.
+	Strict bool
+	// OnSkipEmpty is called whenever an empty Value is skipped while
+	// unmarshaling with EmptySkip, leaving its destination untouched.
+	// Argument path describes the location of the value within its
+	// collection, e.g. "[2]" for the third item of a slice or "[key]" for a
+	// map item, and is empty for the top level value. Argument typ is the
+	// type of the destination.
+	OnSkipEmpty func(path string, typ reflect.Type)
+	// EmptyValues determines how empty values are handled when
+	// unmarshaling. Defaults to EmptySkip.
+	EmptyValues EmptyMode
+	// EmptyCollections determines how empty values are handled when
+	// unmarshaling them to a slice, map or array. Defaults to
+	// EmptyCollectionDefault, which follows EmptyValues.
+	EmptyCollections EmptyCollectionMode
+	// NilToken, when set, is the raw value of a nil slice or map, which makes
+	// it distinguishable from an empty slice or map. A value which equals
+	// NilToken is unmarshaled to a nil slice or map. It is also the raw value
+	// of an invalid Null, or database/sql nullable type, e.g. "NULL". Use it
+	// together with EmptyCollectionNonNil to preserve both nil and empty
+	// collections when round-tripping them.
+	NilToken string
+	// RedactURL determines which credentials of an url.URL are redacted when
+	// marshaling it, e.g. to prevent passwords from ending up in logs.
+	// Defaults to URLRedactNone.
+	RedactURL URLRedaction
+	// TimeLayout is used to marshal time.Time values and is the first layout
+	// that is tried when unmarshaling them. Defaults to time.RFC3339Nano.
+	TimeLayout string
+	// TimeLayouts are tried, in order, when unmarshaling a time.Time value
+	// with TimeLayout fails. Defaults to DefaultTimeLayouts.
+	TimeLayouts []string
+	// TimeEpoch, when set, unmarshals integer values to a time.Time as the
+	// seconds or milliseconds since the Unix epoch, other values are still
+	// parsed using TimeLayout and TimeLayouts. It also marshals time.Time
+	// values to their epoch form, instead of using TimeLayout. Defaults to
+	// EpochNone.
+	TimeEpoch TimeEpoch
+	// NestedBrackets enables (un)marshaling of nested arrays, slices and maps,
+	// where each nested collection is enclosed by square brackets, e.g.
+	// "[1,2],[3,4]" for a [][]int.
+	NestedBrackets bool
+	// NestedSeparators enables (un)marshaling of nested arrays, slices and
+	// maps, by using a different items separator for each nested depth level.
+	// The first separator is used for collections within the top level
+	// collection, e.g. "1|2,3|4" for a [][]int with NestedSeparators "|".
+	NestedSeparators []string
+	// Quote enables quoting of items of arrays, slices and maps with double
+	// quotes, so they may contain separators, e.g. `"a,b",c`. A double quote
+	// within a quoted item is escaped by another double quote, e.g.
+	// `"say ""hi"""`. Only items which need quoting are quoted when
+	// marshaling.
+	Quote bool
+	// Escape enables escaping of separators within items of arrays, slices
+	// and maps with a backslash, e.g. `a\,b,c`. A backslash itself is
+	// escaped by another backslash.
+	Escape bool
+	// MapKeyOnly allows items of a map without a key value separator, e.g.
+	// "a" and "c" in "a,b=2,c", like the keys of a query string. Their value
+	// is unmarshaled from MapKeyOnlyValue. Otherwise, such an item results
+	// in an ErrMapInvalidFormat error.
+	MapKeyOnly bool
+	// MapKeyOnlyValue is the raw value of a key-only map item when
+	// MapKeyOnly is set, e.g. "true" for a map[string]bool. It defaults to
+	// an empty value.
+	MapKeyOnlyValue string
+	// MaxSplit limits the number of items a raw value is split into when
+	// unmarshaling an array, slice or map, like strings.SplitN. The last
+	// item contains the remainder of the raw value, including any
+	// separators, e.g. "a:b:c" results in []string{"a", "b:c"} with
+	// ItemsSeparator ":" and a MaxSplit of 2. Zero or less means no limit.
+	MaxSplit int
+	// IntBase is used to parse and format integers, it must be between 2 and
+	// 36. Any other value parses integers with base 0, which detects the
+	// base from their prefix, e.g. "0x" for hexadecimal, and formats them
+	// with base 10. Types with a registered func, e.g. an IntFormat, are not
+	// affected.
+	IntBase int
+	// OnOverflow determines how integers and floats which do not fit their
+	// destination type are handled when unmarshaling. Defaults to
+	// OverflowError.
+	OnOverflow OverflowMode
+	// ExtendedDuration enables the units "d" for days and "w" for weeks when
+	// (un)marshaling a time.Duration, e.g. "1w2d" instead of "216h0m0s". See
+	// Value.ExtendedDuration for details.
+	ExtendedDuration bool
+	// DurationUnit, when set, is the unit of a plain integer which is
+	// unmarshaled to a time.Duration, e.g. "30" with a DurationUnit of
+	// time.Second results in 30 seconds. Otherwise, only "0" is accepted
+	// without a unit.
+	DurationUnit time.Duration
+	// BoolTrueTokens are accepted as true when unmarshaling a bool, in
+	// addition to the values accepted by Value.Bool, e.g. "yes" or "on".
+	// Tokens are matched case-insensitive. The first token is used when
+	// marshaling true.
+	BoolTrueTokens []string
+	// BoolFalseTokens are accepted as false when unmarshaling a bool, in
+	// addition to the values accepted by Value.Bool, e.g. "no" or "off".
+	// Tokens are matched case-insensitive. The first token is used when
+	// marshaling false.
+	BoolFalseTokens []string
+	// OnWarning is called whenever a non-fatal condition occurs while
+	// unmarshaling, e.g. a duplicate map key. Unlike errors, warnings do not
+	// stop the unmarshaling process.
+	OnWarning func(w Warning)
+	// Aliases map alternative, or deprecated, raw values to their canonical
+	// raw value before they are unmarshaled.
+	Aliases []Alias
+	// Checksum determines if the checksums of values are verified before
+	// they are unmarshaled. Checksums are only detected for top level values,
+	// not for the items of arrays, slices and maps. Defaults to
+	// ChecksumIgnore.
+	Checksum ChecksumMode
+	// Decrypt, when set, is called with the ciphertext of values that start
+	// with DecryptPrefix, before they are unmarshaled. Like checksums,
+	// encrypted values are only detected at the top level.
+	Decrypt DecryptFunc
+	// DecryptPrefix marks values as encrypted. Defaults to
+	// DefaultDecryptPrefix.
+	DecryptPrefix string
+}
+
+var globalOptions struct {
+	sync.RWMutex
+	opts Options
+}
+
+// SetGlobalOptions sets the Options which are used by the package-level
+// functions, such as Unmarshal and Marshal. It is safe to call concurrently,
+// but typically should be called once at startup of the application.
+func SetGlobalOptions(opts Options) {
+	globalOptions.Lock()
+	globalOptions.opts = opts
+	globalOptions.Unlock()
+}
+
+// GlobalOptions returns the Options which are used by the package-level
+// functions, such as Unmarshal and Marshal.
+func GlobalOptions() Options {
+	globalOptions.RLock()
+	defer globalOptions.RUnlock()
+	return globalOptions.opts
 }
 
 func (o Options) itemSeparator() string {
@@ -21,6 +187,20 @@ func (o Options) itemSeparator() string {
 	return o.ItemsSeparator
 }
 
+// nestable indicates if an array, slice or map at depth can be
+// (un)marshaled. The top level collection has a depth of 0.
+func (o Options) nestable(depth int) bool {
+	return depth == 0 || o.NestedBrackets || depth <= len(o.NestedSeparators)
+}
+
+// itemSeparatorAt returns the items separator of a collection at depth.
+func (o Options) itemSeparatorAt(depth int) string {
+	if depth > 0 && depth <= len(o.NestedSeparators) {
+		return o.NestedSeparators[depth-1]
+	}
+	return o.itemSeparator()
+}
+
 func (o Options) keyValueSeparator() string {
 	if o.KeyValueSeparator == "" {
 		return DefaultKeyValueSeparator
@@ -0,0 +1,46 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetGlobalOptions(t *testing.T) {
+	prev := GlobalOptions()
+	defer SetGlobalOptions(prev)
+
+	SetGlobalOptions(Options{ItemsSeparator: ";", KeyValueSeparator: ":"})
+	assert.Equal(t, Options{ItemsSeparator: ";", KeyValueSeparator: ":"}, GlobalOptions())
+
+	var list []string
+	assert.NoError(t, Unmarshal("foo;bar", &list))
+	assert.Equal(t, []string{"foo", "bar"}, list)
+
+	val, err := Marshal(map[string]int{"a": 1})
+	assert.NoError(t, err)
+	assert.Equal(t, Value("a:1"), val)
+
+	t.Run("concurrent", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				SetGlobalOptions(Options{ItemsSeparator: ";"})
+			}()
+			go func() {
+				defer wg.Done()
+				var list []string
+				_ = Unmarshal("foo;bar", &list)
+				_, _ = Marshal(list)
+			}()
+		}
+		wg.Wait()
+	})
+}
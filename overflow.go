@@ -0,0 +1,76 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/go-pogo/errors"
+)
+
+// OverflowMode determines how numbers which do not fit their destination
+// type are handled when unmarshaling.
+type OverflowMode uint8
+
+const (
+	// OverflowError returns an error wrapping ErrValidationFailure.
+	OverflowError OverflowMode = iota
+	// OverflowClamp saturates the number to the minimum or maximum value of
+	// its destination type, e.g. "300" results in 127 for an int8.
+	OverflowClamp
+	// OverflowTruncate silently truncates an integer to the size of its
+	// destination type, e.g. "300" results in 44 for an int8, like a Go
+	// conversion does. A float which exceeds a float32 results in an
+	// infinity. Numbers which do not fit 64 bits still result in an error.
+	OverflowTruncate
+)
+
+// parseInt parses v as an integer of bitSize according to Options.IntBase
+// and Options.OnOverflow.
+func (o Options) parseInt(v Value, bitSize int) (int64, error) {
+	if o.OnOverflow == OverflowTruncate {
+		bitSize = 64
+	}
+	x, err := intBase(v, o.parseBase(), bitSize)
+	return x, o.overflowErr(err)
+}
+
+// parseUint parses v as an unsigned integer of bitSize according to
+// Options.IntBase and Options.OnOverflow.
+func (o Options) parseUint(v Value, bitSize int) (uint64, error) {
+	if o.OnOverflow == OverflowTruncate {
+		bitSize = 64
+	}
+	x, err := uintBase(v, o.parseBase(), bitSize)
+	return x, o.overflowErr(err)
+}
+
+// parseFloat parses v as a float of bitSize according to Options.OnOverflow.
+func (o Options) parseFloat(v Value, bitSize int) (float64, error) {
+	if o.OnOverflow == OverflowTruncate {
+		bitSize = 64
+	}
+	x, err := floatSize(v, bitSize)
+	if err != nil && o.overflowErr(err) == nil {
+		// strconv.ParseFloat returns ±Inf on overflow
+		max := math.MaxFloat64
+		if bitSize == 32 {
+			max = math.MaxFloat32
+		}
+		return math.Copysign(max, x), nil
+	}
+	return x, err
+}
+
+// overflowErr returns nil when err is a range error which is clamped
+// according to Options.OnOverflow, otherwise it returns err. The strconv
+// parse funcs already return the saturated value on a range error.
+func (o Options) overflowErr(err error) error {
+	if err != nil && o.OnOverflow == OverflowClamp && errors.Is(err, strconv.ErrRange) {
+		return nil
+	}
+	return err
+}
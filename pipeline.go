@@ -0,0 +1,74 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"context"
+	"os"
+	"strings"
+)
+
+// Pipe is a chain of normalization steps which are applied to a Value, before
+// it is unmarshaled to its destination.
+//
+//	var level string
+//	err := rawconv.Pipeline(val).Trim().Lower().ExpandEnv().To(&level)
+type Pipe struct {
+	val Value
+	u   *Unmarshaler
+}
+
+// Pipeline starts a new Pipe for Value val.
+func Pipeline(val Value) *Pipe { return &Pipe{val: val} }
+
+// Trim removes all leading and trailing white space.
+func (p *Pipe) Trim() *Pipe {
+	p.val = Value(strings.TrimSpace(p.val.String()))
+	return p
+}
+
+// Lower maps all unicode letters to their lower case.
+func (p *Pipe) Lower() *Pipe {
+	p.val = Value(strings.ToLower(p.val.String()))
+	return p
+}
+
+// Upper maps all unicode letters to their upper case.
+func (p *Pipe) Upper() *Pipe {
+	p.val = Value(strings.ToUpper(p.val.String()))
+	return p
+}
+
+// ExpandEnv replaces ${var} or $var according to the values of the current
+// environment variables, see os.ExpandEnv.
+func (p *Pipe) ExpandEnv() *Pipe {
+	p.val = Value(os.ExpandEnv(p.val.String()))
+	return p
+}
+
+// Apply applies the custom normalization step fn.
+func (p *Pipe) Apply(fn func(val Value) Value) *Pipe {
+	p.val = fn(p.val)
+	return p
+}
+
+// Using sets the Unmarshaler which is used by To. When not set, To behaves
+// like Unmarshal.
+func (p *Pipe) Using(u *Unmarshaler) *Pipe {
+	p.u = u
+	return p
+}
+
+// Value returns the normalized Value.
+func (p *Pipe) Value() Value { return p.val }
+
+// To unmarshals the normalized Value to the value pointed to by v. See
+// Unmarshal for additional details.
+func (p *Pipe) To(v any) error {
+	if p.u == nil {
+		return Unmarshal(p.val, v)
+	}
+	return p.u.unmarshalPtr(context.Background(), p.val, v)
+}
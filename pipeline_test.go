@@ -0,0 +1,54 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipe(t *testing.T) {
+	t.Run("steps", func(t *testing.T) {
+		t.Setenv("RAWCONV_PIPE", "Bar")
+		assert.Equal(t, Value("foo-bar"), Pipeline("  FOO-BAR ").
+			Trim().
+			Lower().
+			Value(),
+		)
+		assert.Equal(t, Value("FOO-BAR"), Pipeline("foo-${RAWCONV_PIPE}").
+			ExpandEnv().
+			Upper().
+			Value(),
+		)
+		assert.Equal(t, Value("foo_bar"), Pipeline("foo bar").
+			Apply(func(val Value) Value {
+				return Value(strings.ReplaceAll(val.String(), " ", "_"))
+			}).
+			Value(),
+		)
+	})
+	t.Run("to", func(t *testing.T) {
+		var have bool
+		assert.NoError(t, Pipeline(" TRUE ").Trim().Lower().To(&have))
+		assert.True(t, have)
+		assert.ErrorIs(t, Pipeline("true").To(have), ErrPointerExpected)
+	})
+	t.Run("using", func(t *testing.T) {
+		var u Unmarshaler
+		u.ItemsSeparator = ";"
+
+		var have []string
+		assert.NoError(t, Pipeline(" a;b ").Trim().Using(&u).To(&have))
+		assert.Equal(t, []string{"a", "b"}, have)
+		assert.ErrorIs(t, Pipeline("a").Using(&u).To(nil), ErrNilDestination)
+	})
+	t.Run("unsupported", func(t *testing.T) {
+		var have chan int
+		assert.ErrorIs(t, Pipeline("x").To(&have), &UnsupportedTypeError{Type: reflect.TypeOf(&have)})
+	})
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"strings"
+)
+
+// splitN splits str by sep into at most n parts, like strings.SplitN. It
+// ignores separators which are enclosed by square brackets when
+// Options.NestedBrackets is set, enclosed by double quotes when Options.Quote
+// is set, or escaped when Options.Escape is set.
+func (o Options) splitN(str, sep string, n int) []string {
+	if !o.NestedBrackets && !o.Quote && !o.Escape {
+		return strings.SplitN(str, sep, n)
+	}
+	if n < 0 {
+		n = strings.Count(str, sep) + 1
+	}
+
+	res := make([]string, 0, n)
+
+	var level, start int
+	var quoted bool
+	for i := 0; i < len(str) && len(res) < n-1; i++ {
+		switch c := str[i]; {
+		case c == '\\' && o.Escape:
+			i++
+			continue
+		case c == '"' && o.Quote:
+			quoted = !quoted
+			continue
+		case quoted:
+			continue
+		case c == '[' && o.NestedBrackets:
+			level++
+			continue
+		case c == ']' && o.NestedBrackets:
+			if level > 0 {
+				level--
+			}
+			continue
+		}
+		if level == 0 && strings.HasPrefix(str[i:], sep) {
+			res = append(res, str[start:i])
+			i += len(sep) - 1
+			start = i + 1
+		}
+	}
+	return append(res, str[start:])
+}
+
+// unquote removes the enclosing double quotes and escape characters from str,
+// depending on Options.Quote and Options.Escape.
+func (o Options) unquote(str string) string {
+	if o.Quote && len(str) >= 2 && str[0] == '"' && str[len(str)-1] == '"' {
+		str = strings.ReplaceAll(str[1:len(str)-1], `""`, `"`)
+	}
+	if o.Escape && strings.IndexByte(str, '\\') >= 0 {
+		var buf strings.Builder
+		buf.Grow(len(str))
+		for i := 0; i < len(str); i++ {
+			if str[i] == '\\' && i+1 < len(str) {
+				i++
+			}
+			buf.WriteByte(str[i])
+		}
+		str = buf.String()
+	}
+	return str
+}
+
+// quote encloses str with double quotes when Options.Quote is set, or escapes
+// its special characters when Options.Escape is set, so str can be used as an
+// item of an array, slice or map.
+func (o Options) quote(str string) string {
+	if !o.Quote && !o.Escape {
+		return str
+	}
+
+	special := o.specialChars()
+	if o.Quote {
+		if !strings.ContainsAny(str, special) &&
+			strings.TrimSpace(str) == str {
+			return str
+		}
+		if o.Escape {
+			str = strings.ReplaceAll(str, `\`, `\\`)
+		}
+		return `"` + strings.ReplaceAll(str, `"`, `""`) + `"`
+	}
+
+	if !strings.ContainsAny(str, special) {
+		return str
+	}
+
+	var buf strings.Builder
+	buf.Grow(len(str) + 2)
+	for _, r := range str {
+		if strings.ContainsRune(special, r) {
+			buf.WriteByte('\\')
+		}
+		buf.WriteRune(r)
+	}
+	return buf.String()
+}
+
+// specialChars returns all characters which have a special meaning within
+// the raw representation of an array, slice or map.
+func (o Options) specialChars() string {
+	var buf strings.Builder
+	buf.WriteString(o.itemSeparator())
+	buf.WriteString(o.keyValueSeparator())
+	for _, sep := range o.NestedSeparators {
+		buf.WriteString(sep)
+	}
+	if o.NestedBrackets {
+		buf.WriteString("[]")
+	}
+	if o.Quote {
+		buf.WriteByte('"')
+	}
+	if o.Escape {
+		buf.WriteByte('\\')
+	}
+	return buf.String()
+}
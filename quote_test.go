@@ -0,0 +1,131 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptions_splitN(t *testing.T) {
+	tests := map[string]struct {
+		opts  Options
+		input string
+		sep   string
+		n     int
+		want  []string
+	}{
+		"plain": {
+			input: `a,"b,c"`,
+			n:     -1,
+			want:  []string{"a", `"b`, `c"`},
+		},
+		"quoted": {
+			opts:  Options{Quote: true},
+			input: `a,"b,c",d`,
+			n:     -1,
+			want:  []string{"a", `"b,c"`, "d"},
+		},
+		"escaped": {
+			opts:  Options{Escape: true},
+			input: `a\,b,c\\,d`,
+			n:     -1,
+			want:  []string{`a\,b`, `c\\`, "d"},
+		},
+		"brackets": {
+			opts:  Options{NestedBrackets: true, Quote: true},
+			input: `[a,"]"],b`,
+			n:     -1,
+			want:  []string{`[a,"]"]`, "b"},
+		},
+		"limit": {
+			opts:  Options{Quote: true},
+			input: `"a=b"=c=d`,
+			sep:   "=",
+			n:     2,
+			want:  []string{`"a=b"`, "c=d"},
+		},
+		"multi char separator": {
+			opts:  Options{Escape: true},
+			input: `a\::b::c`,
+			sep:   "::",
+			n:     -1,
+			want:  []string{`a\::b`, "c"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			sep := tc.sep
+			if sep == "" {
+				sep = ","
+			}
+			assert.Equal(t, tc.want, tc.opts.splitN(tc.input, sep, tc.n))
+		})
+	}
+}
+
+func TestQuote(t *testing.T) {
+	quote := Options{Quote: true}
+	escape := Options{Escape: true}
+
+	tests := map[string]struct {
+		opts  Options
+		input any
+		want  Value
+	}{
+		"quote slice": {
+			opts:  quote,
+			input: []string{"a,b", "c", ` d `, `say "hi"`},
+			want:  `"a,b",c," d ","say ""hi"""`,
+		},
+		"quote map": {
+			opts:  quote,
+			input: map[string]string{"a=b": "c,d"},
+			want:  `"a=b"="c,d"`,
+		},
+		"quote nested": {
+			opts:  Options{Quote: true, NestedBrackets: true},
+			input: [][]string{{"a]", "b"}, {"c,d"}},
+			want:  `["a]",b],["c,d"]`,
+		},
+		"escape slice": {
+			opts:  escape,
+			input: []string{"a,b", `c\`, "d"},
+			want:  `a\,b,c\\,d`,
+		},
+		"escape map": {
+			opts:  escape,
+			input: map[string]string{"a=b": "c,d"},
+			want:  `a\=b=c\,d`,
+		},
+		"quote and escape": {
+			opts:  Options{Quote: true, Escape: true},
+			input: []string{`a\,b`, "c"},
+			want:  `"a\\,b",c`,
+		},
+		"int slice": {
+			opts:  quote,
+			input: []int{1, 2},
+			want:  `1,2`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := Marshaler{Options: tc.opts}
+			have, err := m.Marshal(reflect.ValueOf(tc.input))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, have)
+
+			u := Unmarshaler{Options: tc.opts}
+			rv := reflect.New(reflect.TypeOf(tc.input))
+			assert.NoError(t, u.Unmarshal(have, rv))
+			assert.Equal(t, tc.input, rv.Elem().Interface())
+		})
+	}
+}
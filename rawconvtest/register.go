@@ -0,0 +1,78 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package rawconvtest provides helpers for testing code which relies on
+// (custom) types registered to the global registries of package rawconv.
+package rawconvtest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-pogo/rawconv"
+)
+
+var unmarshalFuncType = reflect.TypeOf(rawconv.UnmarshalFunc(nil))
+
+// Register registers fn for typ with either RegisterUnmarshalFunc or
+// RegisterMarshalFunc, depending on the type of fn.
+//
+// Tests which register a func for different types can safely run in
+// parallel. Parallel tests must not register a func for the same type:
+// registrations are global, so these tests use each other's func, and the
+// func which is restored on cleanup depends on the order in which they
+// complete.
+//
+//	rawconvtest.Register(t, reflect.TypeOf(myType{}), func(val rawconv.Value, dest any) error {
+//		// ...
+//	})
+func Register[F interface {
+	~func(rawconv.Value, any) error | ~func(any) (string, error)
+}](t testing.TB, typ reflect.Type, fn F) {
+	t.Helper()
+
+	rv := reflect.ValueOf(fn)
+	if rv.Type().ConvertibleTo(unmarshalFuncType) {
+		RegisterUnmarshalFunc(t, typ, rv.Convert(unmarshalFuncType).Interface().(rawconv.UnmarshalFunc))
+		return
+	}
+
+	var mfn rawconv.MarshalFunc
+	rv = rv.Convert(reflect.TypeOf(mfn))
+	RegisterMarshalFunc(t, typ, rv.Interface().(rawconv.MarshalFunc))
+}
+
+// RegisterUnmarshalFunc globally registers the rawconv.UnmarshalFunc for typ,
+// for the duration of test t. The previously registered rawconv.UnmarshalFunc
+// for typ, if any, is restored when t and all its subtests complete.
+func RegisterUnmarshalFunc(t testing.TB, typ reflect.Type, fn rawconv.UnmarshalFunc) {
+	t.Helper()
+
+	prev := rawconv.DeregisterUnmarshalFunc(typ)
+	rawconv.RegisterUnmarshalFunc(typ, fn)
+
+	t.Cleanup(func() {
+		rawconv.DeregisterUnmarshalFunc(typ)
+		if prev != nil {
+			rawconv.RegisterUnmarshalFunc(typ, prev)
+		}
+	})
+}
+
+// RegisterMarshalFunc globally registers the rawconv.MarshalFunc for typ, for
+// the duration of test t. The previously registered rawconv.MarshalFunc for
+// typ, if any, is restored when t and all its subtests complete.
+func RegisterMarshalFunc(t testing.TB, typ reflect.Type, fn rawconv.MarshalFunc) {
+	t.Helper()
+
+	prev := rawconv.DeregisterMarshalFunc(typ)
+	rawconv.RegisterMarshalFunc(typ, fn)
+
+	t.Cleanup(func() {
+		rawconv.DeregisterMarshalFunc(typ)
+		if prev != nil {
+			rawconv.RegisterMarshalFunc(typ, prev)
+		}
+	})
+}
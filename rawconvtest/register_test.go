@@ -0,0 +1,83 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconvtest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-pogo/rawconv"
+	"github.com/stretchr/testify/assert"
+)
+
+type myType struct{ val string }
+
+func TestRegister(t *testing.T) {
+	typ := reflect.TypeOf(myType{})
+
+	t.Run("unmarshal", func(t *testing.T) {
+		t.Run("registered", func(t *testing.T) {
+			Register(t, typ, func(val rawconv.Value, dest any) error {
+				dest.(*myType).val = val.String()
+				return nil
+			})
+
+			var have myType
+			assert.NoError(t, rawconv.Unmarshal("foo", &have))
+			assert.Equal(t, myType{val: "foo"}, have)
+		})
+
+		assert.Nil(t, rawconv.GetUnmarshalFunc(typ))
+	})
+	t.Run("marshal", func(t *testing.T) {
+		t.Run("registered", func(t *testing.T) {
+			Register(t, typ, func(v any) (string, error) {
+				return v.(myType).val, nil
+			})
+
+			have, err := rawconv.Marshal(myType{val: "bar"})
+			assert.NoError(t, err)
+			assert.Equal(t, rawconv.Value("bar"), have)
+		})
+
+		assert.Nil(t, rawconv.GetMarshalFunc(typ))
+	})
+}
+
+func TestRegisterUnmarshalFunc(t *testing.T) {
+	typ := reflect.TypeOf(time.Duration(0))
+	want := reflect.ValueOf(rawconv.GetUnmarshalFunc(typ)).Pointer()
+
+	t.Run("override", func(t *testing.T) {
+		RegisterUnmarshalFunc(t, typ, func(_ rawconv.Value, dest any) error {
+			*dest.(*time.Duration) = time.Minute
+			return nil
+		})
+
+		var have time.Duration
+		assert.NoError(t, rawconv.Unmarshal("1s", &have))
+		assert.Equal(t, time.Minute, have)
+	})
+
+	assert.Equal(t, want, reflect.ValueOf(rawconv.GetUnmarshalFunc(typ)).Pointer())
+}
+
+func TestRegisterMarshalFunc(t *testing.T) {
+	typ := reflect.TypeOf(time.Duration(0))
+	want := reflect.ValueOf(rawconv.GetMarshalFunc(typ)).Pointer()
+
+	t.Run("override", func(t *testing.T) {
+		RegisterMarshalFunc(t, typ, func(any) (string, error) {
+			return "forever", nil
+		})
+
+		have, err := rawconv.Marshal(time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, rawconv.Value("forever"), have)
+	})
+
+	assert.Equal(t, want, reflect.ValueOf(rawconv.GetMarshalFunc(typ)).Pointer())
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/go-pogo/errors"
+)
+
+// UnmarshalReader reads the raw value from r and stores the result in the
+// value pointed to by v, using the Options set with SetGlobalOptions. See
+// Unmarshal for additional details.
+func UnmarshalReader(r io.Reader, v any) error {
+	rv, err := ptrDest(v)
+	if err != nil {
+		return err
+	}
+
+	u := Unmarshaler{Options: GlobalOptions()}
+	return u.unmarshalReader(r, rv)
+}
+
+// UnmarshalReader reads the raw value from r and unmarshals it to v, like
+// Unmarshal. Slices are unmarshaled while reading their items from r, so the
+// raw value as a whole is never kept in memory. Any other type is unmarshaled
+// after reading all of r.
+func (u *Unmarshaler) UnmarshalReader(r io.Reader, v reflect.Value) error {
+	if err := validDest(v); err != nil {
+		return err
+	}
+	return u.unmarshalReader(r, v)
+}
+
+func (u *Unmarshaler) unmarshalReader(r io.Reader, dest reflect.Value) error {
+	if u.streamable(dest.Type()) {
+		return u.unmarshalStream(r, dest)
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return u.unmarshal(context.Background(), Value(b), dest, "", 0)
+}
+
+// streamable indicates if the items of typ can be unmarshaled while reading
+// them.
+func (u *Unmarshaler) streamable(typ reflect.Type) bool {
+	if u.Strict || u.NestedBrackets || u.Quote || u.Escape || u.MaxSplit > 0 || len(u.middleware) != 0 ||
+		u.Checksum != ChecksumIgnore || u.Decrypt != nil || u.NilToken != "" || len(u.Aliases) != 0 ||
+		u.Func(typ) != nil {
+		return false
+	}
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ.Kind() == reflect.Slice &&
+		typ != jsonRawMessageType &&
+		!u.isBinary(typ)
+}
+
+func (u *Unmarshaler) unmarshalStream(r io.Reader, dest reflect.Value) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, math.MaxInt)
+	scanner.Split(scanItems([]byte(u.itemSeparator())))
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return errors.WithStack(err)
+		}
+		// reader is empty
+		return u.unmarshal(context.Background(), "", dest, "", 0)
+	}
+
+	text := scanner.Text()
+	more := scanner.Scan()
+	if !more {
+		if err := scanner.Err(); err != nil {
+			return errors.WithStack(err)
+		}
+		// a single item, which may be empty or blank, is unmarshaled as a
+		// whole so it is handled the same as with Unmarshal
+		return u.unmarshal(context.Background(), Value(text), dest, "", 0)
+	}
+
+	for dest.Kind() == reflect.Ptr {
+		if dest.IsNil() {
+			if !dest.CanSet() {
+				return errors.New(ErrUnableToSet)
+			}
+			dest.Set(reflect.New(dest.Type().Elem()))
+		}
+		dest = dest.Elem()
+	}
+
+	slice := reflect.MakeSlice(dest.Type(), 0, 2)
+	typ := dest.Type().Elem()
+
+	for i := 0; ; i++ {
+		part := Value(strings.TrimSpace(text))
+		val := reflect.New(typ).Elem()
+		if err := u.unmarshal(context.Background(), part, val, u.indexPath("", i), 1); err != nil {
+			return &UnmarshalError{Index: i, Err: err}
+		}
+		slice = reflect.Append(slice, val)
+
+		if !more {
+			break
+		}
+		text = scanner.Text()
+		more = scanner.Scan()
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	dest.Set(slice)
+	return nil
+}
+
+// scanItems returns a bufio.SplitFunc which splits the read data by sep, the
+// same way strings.Split does.
+func scanItems(sep []byte) bufio.SplitFunc {
+	var trailing bool
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		if i := bytes.Index(data, sep); i >= 0 {
+			trailing = true
+			return i + len(sep), data[:i], nil
+		}
+		if !atEOF {
+			// request more data
+			return 0, nil, nil
+		}
+		if len(data) == 0 && !trailing {
+			return 0, nil, nil
+		}
+		return len(data), append([]byte{}, data...), bufio.ErrFinalToken
+	}
+}
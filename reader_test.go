@@ -0,0 +1,168 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"bufio"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalReader(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    any
+		wantErr error
+	}{
+		"string":      {input: "some value", want: "some value"},
+		"duration":    {input: "1h2m", want: time.Hour + 2*time.Minute},
+		"raw json":    {input: `{"foo":"bar"}`, want: json.RawMessage(`{"foo":"bar"}`)},
+		"map":         {input: "a=1", want: map[string]int{"a": 1}},
+		"slice":       {input: "1, 2,3", want: []int{1, 2, 3}},
+		"slice ptr":   {input: "1,2", want: ptr([]int{1, 2})},
+		"single item": {input: "1", want: []int{1}},
+		"empty items": {input: ",2,", want: []int{0, 2, 0}},
+		"empty":       {input: "", want: []int(nil)},
+		"parse error": {input: "1,x", want: []int(nil), wantErr: ErrParseFailure},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// one byte at a time to make sure the separators are found
+			// across multiple reads
+			r := iotest.OneByteReader(strings.NewReader(tc.input))
+			rv := reflect.New(reflect.TypeOf(tc.want))
+
+			haveErr := UnmarshalReader(r, rv.Interface())
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, haveErr, tc.wantErr)
+				return
+			}
+
+			assert.NoError(t, haveErr)
+			assert.Equal(t, tc.want, rv.Elem().Interface())
+		})
+	}
+
+	t.Run("not a pointer", func(t *testing.T) {
+		assert.ErrorIs(t, UnmarshalReader(strings.NewReader("x"), ""), ErrPointerExpected)
+	})
+	t.Run("read error", func(t *testing.T) {
+		r := iotest.ErrReader(bufio.ErrTooLong)
+
+		var str string
+		assert.ErrorIs(t, UnmarshalReader(r, &str), bufio.ErrTooLong)
+		var list []string
+		assert.ErrorIs(t, UnmarshalReader(r, &list), bufio.ErrTooLong)
+	})
+}
+
+func TestUnmarshaler_UnmarshalReader(t *testing.T) {
+	var u Unmarshaler
+	u.ItemsSeparator = "::"
+
+	var have []string
+	r := iotest.HalfReader(strings.NewReader("foo::bar::baz"))
+	assert.NoError(t, u.UnmarshalReader(r, reflect.ValueOf(&have)))
+	assert.Equal(t, []string{"foo", "bar", "baz"}, have)
+
+	assert.ErrorIs(t, u.UnmarshalReader(r, reflect.ValueOf(have)), ErrUnableToSet)
+}
+
+func TestUnmarshaler_UnmarshalReader_sameAsUnmarshal(t *testing.T) {
+	tests := map[string]struct {
+		opts  Options
+		input string
+		init  []string
+	}{
+		"nil token": {
+			opts:  Options{NilToken: "NULL"},
+			input: "NULL",
+			init:  []string{"keep"},
+		},
+		"alias": {
+			opts: Options{Aliases: []Alias{
+				{Type: reflect.TypeOf([]string{}), From: "all", To: "a,b,c"},
+				{Type: reflect.TypeOf([]string{}), From: "x,y", To: "z"},
+			}},
+			input: "x,y",
+		},
+		"blank": {
+			input: " ",
+			init:  []string{"keep"},
+		},
+		"blank set zero": {
+			opts:  Options{EmptyValues: EmptySetZero},
+			input: " ",
+			init:  []string{"keep"},
+		},
+		"empty set zero": {
+			opts: Options{EmptyValues: EmptySetZero},
+			init: []string{"keep"},
+		},
+		"empty collection": {
+			opts:  Options{EmptyCollections: EmptyCollectionNonNil},
+			input: " ",
+		},
+		"single item": {
+			input: " foo ",
+		},
+		"items": {
+			input: "foo, ,bar",
+			init:  []string{"keep"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			u := Unmarshaler{Options: tc.opts}
+
+			want := append([]string(nil), tc.init...)
+			assert.NoError(t, u.Unmarshal(Value(tc.input), reflect.ValueOf(&want)))
+
+			have := append([]string(nil), tc.init...)
+			r := iotest.OneByteReader(strings.NewReader(tc.input))
+			assert.NoError(t, u.UnmarshalReader(r, reflect.ValueOf(&have)))
+			assert.Equal(t, want, have)
+		})
+	}
+
+	t.Run("on skip empty", func(t *testing.T) {
+		var calls int
+		var u Unmarshaler
+		u.OnSkipEmpty = func(string, reflect.Type) { calls++ }
+
+		var have []string
+		assert.NoError(t, u.UnmarshalReader(strings.NewReader(" "), reflect.ValueOf(&have)))
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestScanItems(t *testing.T) {
+	for _, input := range []string{"", "a", "a,b", ",", "a,", ",b", "a,,b", "a,b,"} {
+		t.Run(input, func(t *testing.T) {
+			scanner := bufio.NewScanner(strings.NewReader(input))
+			scanner.Split(scanItems([]byte(",")))
+
+			var have []string
+			for scanner.Scan() {
+				have = append(have, scanner.Text())
+			}
+			assert.NoError(t, scanner.Err())
+
+			want := strings.Split(input, ",")
+			if input == "" {
+				want = nil
+			}
+			assert.Equal(t, want, have)
+		})
+	}
+}
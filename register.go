@@ -6,27 +6,122 @@ package rawconv
 
 import (
 	"encoding"
+	"io/fs"
+	"math/big"
+	"net/netip"
 	"net/url"
 	"reflect"
+	"regexp"
+	"sort"
+	"sync"
 	"time"
+
+	"github.com/go-pogo/errors"
 )
 
 // RegisterUnmarshalFunc registers the UnmarshalFunc for typ, making it globally
 // available for Unmarshal and any Unmarshaler.
+// It panics when typ is the empty interface, because it would match every
+// type; use Unmarshaler.Use to intercept all conversions instead.
+// It is safe to call concurrently, also while unmarshaling.
+//
+// When typ is a pointer type, e.g. *T, fn receives a **T so it can set the
+// pointer itself. This is required for types which must not be copied, such
+// as time.Location.
+//
+// The func registered for the exact type of a destination takes precedence.
+// When funcs are registered for both T and *T, the func for *T is used for
+// destinations of *T and **T, and the func for T is used for destinations
+// of T. The func for T is only used for a *T destination when no func is
+// registered for *T. This does not depend on the order of registration.
 func RegisterUnmarshalFunc(typ reflect.Type, fn UnmarshalFunc) {
 	unmarshaler.Register(typ, fn)
 }
 
 // RegisterMarshalFunc registers the MarshalFunc for typ, making it globally
 // available for Marshal, MarshalValue, MarshalReflect and any Marshaler.
+// It panics when typ is the empty interface, see RegisterUnmarshalFunc.
+// It is safe to call concurrently, also while marshaling. When typ is a
+// pointer type, fn receives the pointer instead of the value it points to.
+// Nil pointers are marshaled to an empty Value without calling fn. Funcs for
+// T and *T take precedence the same way as with RegisterUnmarshalFunc.
 func RegisterMarshalFunc(typ reflect.Type, fn MarshalFunc) {
 	marshaler.Register(typ, fn)
 }
 
-func init() {
-	// interfaces
+// DeregisterUnmarshalFunc removes the globally registered UnmarshalFunc for
+// typ and returns it. It returns nil when no UnmarshalFunc is registered for
+// the exact type typ.
+func DeregisterUnmarshalFunc(typ reflect.Type) UnmarshalFunc {
+	return unmarshaler.Deregister(typ)
+}
+
+// DeregisterMarshalFunc removes the globally registered MarshalFunc for typ
+// and returns it. It returns nil when no MarshalFunc is registered for the
+// exact type typ.
+func DeregisterMarshalFunc(typ reflect.Type) MarshalFunc {
+	return marshaler.Deregister(typ)
+}
+
+// RegisterUnmarshalKindFunc registers the UnmarshalFunc as fallback for all
+// types of reflect.Kind kind, making it globally available for Unmarshal and
+// any Unmarshaler. It is used for types which do not have an UnmarshalFunc
+// registered for their exact type or an interface they implement, including
+// the builtin types of kind, e.g. int for reflect.Int. Argument dest of fn is
+// a pointer to the (elem) type of kind.
+// It panics when kind is reflect.Ptr, reflect.Interface or an unsupported
+// kind.
+func RegisterUnmarshalKindFunc(kind reflect.Kind, fn UnmarshalFunc) {
+	unmarshaler.RegisterKind(kind, fn)
+}
+
+// RegisterMarshalKindFunc registers the MarshalFunc as fallback for all types
+// of reflect.Kind kind, making it globally available for Marshal,
+// MarshalValue, MarshalReflect and any Marshaler. See
+// RegisterUnmarshalKindFunc for details.
+func RegisterMarshalKindFunc(kind reflect.Kind, fn MarshalFunc) {
+	marshaler.RegisterKind(kind, fn)
+}
+
+// DeregisterUnmarshalKindFunc removes the globally registered fallback
+// UnmarshalFunc for kind and returns it. It returns nil when no UnmarshalFunc
+// is registered for kind.
+func DeregisterUnmarshalKindFunc(kind reflect.Kind) UnmarshalFunc {
+	return unmarshaler.register.removeKind(kind)
+}
+
+// DeregisterMarshalKindFunc removes the globally registered fallback
+// MarshalFunc for kind and returns it. It returns nil when no MarshalFunc is
+// registered for kind.
+func DeregisterMarshalKindFunc(kind reflect.Kind) MarshalFunc {
+	return marshaler.register.removeKind(kind)
+}
+
+// ResetRegisteredFuncs removes all globally registered UnmarshalFuncs and
+// MarshalFuncs, including those registered for kinds, and restores the
+// builtin funcs. Funcs which are registered by imported packages, like the
+// opt-in subpackages, are removed as well. It is intended to be used in tests.
+func ResetRegisteredFuncs() {
+	unmarshaler.register.reset()
+	marshaler.register.reset()
+	registerBuiltins()
+}
+
+func init() { registerBuiltins() }
+
+// registerBuiltins globally registers the builtin funcs.
+func registerBuiltins() {
+	// interfaces, binary first so text takes precedence
+	unmarshaler.register.addWithOptions(binaryUnmarshalerType, unmarshalBinary, func(opts Options) UnmarshalFunc {
+		return opts.unmarshalBinary
+	})
+	marshaler.register.addWithOptions(binaryMarshalerType, marshalBinary, func(opts Options) MarshalFunc {
+		return opts.marshalBinary
+	})
+
+	textUnmarshaler := reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	RegisterUnmarshalFunc(textUnmarshaler, unmarshalText)
 	textMarshaler := reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
-	RegisterUnmarshalFunc(textMarshaler, unmarshalText)
 	RegisterMarshalFunc(textMarshaler, marshalText)
 
 	// common types
@@ -34,17 +129,107 @@ func init() {
 	RegisterUnmarshalFunc(rune, unmarshalRune)
 	RegisterMarshalFunc(rune, marshalRune)
 
+	timeLocation := reflect.TypeOf((*time.Location)(nil))
+	RegisterUnmarshalFunc(timeLocation, unmarshalLocation)
+	RegisterMarshalFunc(timeLocation, marshalLocation)
+
+	byteSize := reflect.TypeOf(ByteSize(0))
+	RegisterUnmarshalFunc(byteSize, unmarshalByteSize)
+	RegisterMarshalFunc(byteSize, marshalByteSize)
+
+	fileMode := reflect.TypeOf(fs.FileMode(0))
+	RegisterUnmarshalFunc(fileMode, unmarshalFileMode)
+	RegisterMarshalFunc(fileMode, marshalFileMode)
+
+	// validated string types, which are marshaled as is
+	contentEncoding := reflect.TypeOf(EncodingIdentity)
+	RegisterUnmarshalFunc(contentEncoding, unmarshalContentEncoding)
+
+	charset := reflect.TypeOf(CharsetUTF8)
+	RegisterUnmarshalFunc(charset, unmarshalCharset)
+
+	regexpRegexp := reflect.TypeOf(regexp.Regexp{})
+	RegisterUnmarshalFunc(regexpRegexp, unmarshalRegexp)
+	RegisterMarshalFunc(regexpRegexp, marshalRegexp)
+
+	netipAddr := reflect.TypeOf(netip.Addr{})
+	RegisterUnmarshalFunc(netipAddr, unmarshalAddr)
+	RegisterMarshalFunc(netipAddr, marshalAddr)
+
+	netipAddrPort := reflect.TypeOf(netip.AddrPort{})
+	RegisterUnmarshalFunc(netipAddrPort, unmarshalAddrPort)
+	RegisterMarshalFunc(netipAddrPort, marshalAddrPort)
+
+	netipPrefix := reflect.TypeOf(netip.Prefix{})
+	RegisterUnmarshalFunc(netipPrefix, unmarshalPrefix)
+	RegisterMarshalFunc(netipPrefix, marshalPrefix)
+
+	bigInt := reflect.TypeOf(big.Int{})
+	RegisterUnmarshalFunc(bigInt, unmarshalBigInt)
+	RegisterMarshalFunc(bigInt, marshalBigInt)
+
+	bigFloat := reflect.TypeOf(big.Float{})
+	RegisterUnmarshalFunc(bigFloat, unmarshalBigFloat)
+	RegisterMarshalFunc(bigFloat, marshalBigFloat)
+
+	bigRat := reflect.TypeOf(big.Rat{})
+	RegisterUnmarshalFunc(bigRat, unmarshalBigRat)
+	RegisterMarshalFunc(bigRat, marshalBigRat)
+
+	// slog.Level, when available
+	registerSlog()
+
+	// types which depend on Options
+	timeTime := reflect.TypeOf(time.Time{})
+	unmarshaler.register.addWithOptions(timeTime, unmarshalTime, func(opts Options) UnmarshalFunc {
+		return opts.unmarshalTime
+	})
+	marshaler.register.addWithOptions(timeTime, marshalTime, func(opts Options) MarshalFunc {
+		return opts.marshalTime
+	})
+
 	timeDuration := reflect.TypeOf(time.Nanosecond)
-	RegisterUnmarshalFunc(timeDuration, unmarshalDuration)
-	RegisterMarshalFunc(timeDuration, marshalDuration)
+	unmarshaler.register.addWithOptions(timeDuration, unmarshalDuration, func(opts Options) UnmarshalFunc {
+		if !opts.ExtendedDuration && opts.DurationUnit <= 0 {
+			return unmarshalDuration
+		}
+		return opts.unmarshalDuration
+	})
+	marshaler.register.addWithOptions(timeDuration, marshalDuration, func(opts Options) MarshalFunc {
+		if !opts.ExtendedDuration {
+			return marshalDuration
+		}
+		return opts.marshalDuration
+	})
+
+	tristate := reflect.TypeOf(TristateUnset)
+	unmarshaler.register.addWithOptions(tristate, unmarshalTristate, func(opts Options) UnmarshalFunc {
+		if len(opts.BoolTrueTokens) == 0 && len(opts.BoolFalseTokens) == 0 {
+			return unmarshalTristate
+		}
+		return opts.unmarshalTristate
+	})
+	marshaler.register.addWithOptions(tristate, marshalTristate, func(opts Options) MarshalFunc {
+		if len(opts.BoolTrueTokens) == 0 && len(opts.BoolFalseTokens) == 0 {
+			return marshalTristate
+		}
+		return opts.marshalTristate
+	})
 
 	urlUrl := reflect.TypeOf(url.URL{})
 	RegisterUnmarshalFunc(urlUrl, unmarshalUrl)
-	RegisterMarshalFunc(urlUrl, marshalUrl)
+	marshaler.register.addWithOptions(urlUrl, marshalUrl, func(opts Options) MarshalFunc {
+		if opts.RedactURL == URLRedactNone {
+			return marshalUrl
+		}
+		return opts.marshalUrl
+	})
 }
 
+// unmarshalText relies on encoding.TextUnmarshaler implementations copying the
+// text if they wish to retain it, which allows passing it without copying.
 func unmarshalText(val Value, dest any) error {
-	return dest.(encoding.TextUnmarshaler).UnmarshalText(val.Bytes())
+	return dest.(encoding.TextUnmarshaler).UnmarshalText(val.UnsafeBytes())
 }
 
 func marshalText(v any) (string, error) {
@@ -52,26 +237,105 @@ func marshalText(v any) (string, error) {
 	return string(b), err
 }
 
+// ambiguousTypeErr returns an AmbiguousTypeError when typ is not resolved by
+// the first register which resolves it, because of multiple matching
+// interfaces.
+func ambiguousTypeErr[T interface{ MarshalFunc | UnmarshalFunc }](typ reflect.Type, registers ...*register[T]) error {
+	for _, r := range registers {
+		found, candidates := r.ambiguous(typ)
+		if len(candidates) != 0 {
+			return errors.WithStack(&AmbiguousTypeError{
+				Type:       typ,
+				Candidates: candidates,
+			})
+		}
+		if found {
+			break
+		}
+	}
+	return nil
+}
+
 type register[T interface{ MarshalFunc | UnmarshalFunc }] struct {
+	// mut guards all fields below, so funcs can be registered while others
+	// are being resolved. Only the methods which are used outside of register
+	// acquire a lock.
+	mut   sync.RWMutex
 	types map[reflect.Kind]map[reflect.Type]int
 	funcs []T
+	// optFuncs contains constructors for builtin funcs that depend on
+	// Options, by the pointer of their default func.
+	optFuncs map[uintptr]func(opts Options) T
+	// kinds contains the indexes of the fallback funcs, by kind.
+	kinds map[reflect.Kind]int
+	// ctxFuncs contains the context-aware funcs, by the index of their
+	// adapter in funcs.
+	ctxFuncs map[int]any
+	// cache contains the lookup results by reflect.Type. It is replaced by
+	// an empty cache whenever a func is added or removed.
+	cache *sync.Map
+}
+
+// lookupResult is the cached result of register.lookup.
+type lookupResult struct {
+	index int
+	addr  bool
 }
 
-func (r *register[T]) initialized() bool { return r.types != nil && r.funcs != nil }
+// reset removes all funcs from the register.
+func (r *register[T]) reset() {
+	r.mut.Lock()
+	defer r.mut.Unlock()
 
-const panicUnsupportedKind = "rawconv: unsupported kind"
+	r.types = nil
+	r.funcs = nil
+	r.optFuncs = nil
+	r.kinds = nil
+	r.ctxFuncs = nil
+	r.cache = nil
+}
+
+func (r *register[T]) initialized() bool {
+	r.mut.RLock()
+	defer r.mut.RUnlock()
+	return r.funcs != nil
+}
+
+const (
+	panicUnsupportedKind = "rawconv: unsupported kind"
+	panicEmptyInterface  = "rawconv: cannot register a func for the empty interface, it matches every type; use Use to intercept all conversions instead"
+)
 
 func (r *register[T]) add(typ reflect.Type, fn T) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	r.set(typ, fn, nil)
+}
+
+// addCtx adds adapter fn of the context-aware func ctxFn for typ.
+func (r *register[T]) addCtx(typ reflect.Type, fn T, ctxFn any) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	r.set(typ, fn, ctxFn)
+	if r.ctxFuncs == nil {
+		r.ctxFuncs = make(map[int]any, 2)
+	}
+	r.ctxFuncs[len(r.funcs)-1] = ctxFn
+}
+
+func (r *register[T]) set(typ reflect.Type, fn T, ctxFn any) {
 	k := typ.Kind()
 	if k == reflect.Invalid ||
 		k == reflect.Uintptr ||
 		k == reflect.Chan ||
 		k == reflect.Func ||
-		k == reflect.UnsafePointer ||
-		// not yet supported
-		k == reflect.Array || k == reflect.Map || k == reflect.Slice {
+		k == reflect.UnsafePointer {
 		panic(panicUnsupportedKind)
 	}
+	if k == reflect.Interface && typ.NumMethod() == 0 {
+		panic(panicEmptyInterface)
+	}
 
 	// lazy init
 	if r.types == nil {
@@ -89,46 +353,323 @@ func (r *register[T]) add(typ reflect.Type, fn T) {
 
 	// store func
 	r.funcs = append(r.funcs, fn)
+	r.cache = new(sync.Map)
+}
+
+// addKind adds fn as fallback func for all types of kind.
+func (r *register[T]) addKind(kind reflect.Kind, fn T) {
+	if kind == reflect.Invalid ||
+		kind == reflect.Uintptr ||
+		kind == reflect.Chan ||
+		kind == reflect.Func ||
+		kind == reflect.Interface ||
+		kind == reflect.Ptr ||
+		kind == reflect.UnsafePointer {
+		panic(panicUnsupportedKind)
+	}
+
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	if r.kinds == nil {
+		r.kinds = make(map[reflect.Kind]int, 2)
+	}
+	if r.funcs == nil {
+		r.funcs = make([]T, 0, 3)
+	}
+
+	r.kinds[kind] = len(r.funcs)
+	r.funcs = append(r.funcs, fn)
+}
+
+// addWithOptions adds the default func fn for typ. When resolved, the func
+// created by withOpts using the Options of the Unmarshaler or Marshaler is
+// used instead.
+func (r *register[T]) addWithOptions(typ reflect.Type, fn T, withOpts func(opts Options) T) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	r.set(typ, fn, nil)
+	if r.optFuncs == nil {
+		r.optFuncs = make(map[uintptr]func(opts Options) T, 2)
+	}
+	r.optFuncs[reflect.ValueOf(fn).Pointer()] = withOpts
+}
+
+// pointer returns the pointer of the func at index i, or of its
+// context-aware variant when it is an adapter.
+func (r *register[T]) pointer(i int) uintptr {
+	if ctxFn, ok := r.ctxFuncs[i]; ok {
+		return reflect.ValueOf(ctxFn).Pointer()
+	}
+	return reflect.ValueOf(r.getFromIndex(i)).Pointer()
+}
+
+// remove the func registered for the exact type typ and return it.
+func (r *register[T]) remove(typ reflect.Type) T {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	kind, ok := r.types[typ.Kind()]
+	if !ok {
+		return nil
+	}
+	i, ok := kind[typ]
+	if !ok {
+		return nil
+	}
+
+	delete(kind, typ)
+	delete(r.ctxFuncs, i)
+	r.cache = new(sync.Map)
+	return r.getFromIndex(i)
+}
+
+// removeKind removes the fallback func for kind and returns it.
+func (r *register[T]) removeKind(kind reflect.Kind) T {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	i, ok := r.kinds[kind]
+	if !ok {
+		return nil
+	}
+
+	delete(r.kinds, kind)
+	delete(r.ctxFuncs, i)
+	return r.getFromIndex(i)
 }
 
 func (r *register[T]) find(typ reflect.Type) T {
+	r.mut.RLock()
+	defer r.mut.RUnlock()
+
+	if i, _ := r.cachedLookup(typ); i >= 0 {
+		return r.getFromIndex(i)
+	}
+	return nil
+}
+
+// resolve returns the func registered for typ, according to Options opts,
+// and its context-aware variant when it has one. It also indicates if the
+// func is registered for an interface which is only implemented by a pointer
+// to (the elem type of) typ.
+func (r *register[T]) resolve(typ reflect.Type, opts Options) (T, any, bool) {
+	r.mut.RLock()
+	defer r.mut.RUnlock()
+
+	i, addr := r.cachedLookup(typ)
+	if i < 0 {
+		return nil, nil, false
+	}
+	fn := r.getFromIndex(i)
+	if r.optFuncs != nil {
+		if withOpts, ok := r.optFuncs[reflect.ValueOf(fn).Pointer()]; ok {
+			return withOpts(opts), nil, addr
+		}
+	}
+	return fn, r.ctxFuncs[i], addr
+}
+
+// cachedLookup returns the cached result of lookup for typ. A read lock must
+// be held, so the cache is not replaced while it is used.
+func (r *register[T]) cachedLookup(typ reflect.Type) (int, bool) {
+	cache := r.cache
+	if cache == nil {
+		return r.lookup(typ)
+	}
+	if res, ok := cache.Load(typ); ok {
+		return res.(lookupResult).index, res.(lookupResult).addr
+	}
+
+	i, addr := r.lookup(typ)
+	cache.Store(typ, lookupResult{index: i, addr: addr})
+	return i, addr
+}
+
+// lookup returns the index of the func registered for typ, or -1 when there
+// is none. It also indicates if the func is registered for an interface which
+// is only implemented by a pointer to (the elem type of) typ.
+func (r *register[T]) lookup(typ reflect.Type) (int, bool) {
 	// check if the exact type is registered
-	if fn := r.getFromType(typ); fn != nil {
-		return fn
+	if i := r.typeIndex(typ); i >= 0 {
+		return i, false
 	}
 
 	if typ.Kind() != reflect.Ptr {
 		// check if the type is registered as a pointer
-		return r.getFromImpl(reflect.New(typ).Type())
+		x, i := r.implIndex(reflect.PointerTo(typ))
+		if i < 0 {
+			return -1, false
+		}
+		return i, !typ.Implements(x)
 	}
 
 	// check if the elem type which is pointed to is registered
-	if fn := r.find(typ.Elem()); fn != nil {
-		return fn
-	}
-	if fn := r.getFromImpl(typ); fn != nil {
-		return fn
+	if i, addr := r.lookup(typ.Elem()); i >= 0 {
+		return i, addr
 	}
+	_, i := r.implIndex(typ)
+	return i, false
+}
+
+// ptrType returns the pointer type typ, or the pointer type which is pointed
+// to by typ, for which a func is registered. It returns nil when there is
+// none. When not nil, it is the type of the func returned by resolve.
+func (r *register[T]) ptrType(typ reflect.Type) reflect.Type {
+	r.mut.RLock()
+	defer r.mut.RUnlock()
 
+	for ; typ.Kind() == reflect.Ptr; typ = typ.Elem() {
+		if r.typeIndex(typ) >= 0 {
+			return typ
+		}
+	}
 	return nil
 }
 
+// resolveKind returns the fallback func registered for the kind of (the elem
+// type of) typ, and its context-aware variant when it has one.
+func (r *register[T]) resolveKind(typ reflect.Type) (T, any) {
+	r.mut.RLock()
+	defer r.mut.RUnlock()
+
+	if i := r.kindIndex(typ); i >= 0 {
+		return r.getFromIndex(i), r.ctxFuncs[i]
+	}
+	return nil, nil
+}
+
+// kindIndex returns the index of the fallback func registered for the kind of
+// (the elem type of) typ, or -1 when there is none.
+func (r *register[T]) kindIndex(typ reflect.Type) int {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if i, ok := r.kinds[typ.Kind()]; ok {
+		return i
+	}
+	return -1
+}
+
+// mechanism returns the Mechanism by which typ is resolved by the register.
+// It follows the same rules as lookup.
+func (r *register[T]) mechanism(typ reflect.Type) Mechanism {
+	r.mut.RLock()
+	defer r.mut.RUnlock()
+	return r.mechanismOf(typ)
+}
+
+// kindMechanism returns RegisteredKind when a fallback func is registered for
+// the kind of (the elem type of) typ.
+func (r *register[T]) kindMechanism(typ reflect.Type) Mechanism {
+	r.mut.RLock()
+	defer r.mut.RUnlock()
+
+	if r.kindIndex(typ) >= 0 {
+		return RegisteredKind
+	}
+	return Unsupported
+}
+
+func (r *register[T]) mechanismOf(typ reflect.Type) Mechanism {
+	if r.typeIndex(typ) >= 0 {
+		return RegisteredType
+	}
+	if typ.Kind() != reflect.Ptr {
+		if _, i := r.implIndex(reflect.PointerTo(typ)); i >= 0 {
+			return RegisteredInterface
+		}
+		return Unsupported
+	}
+	if mech := r.mechanismOf(typ.Elem()); mech != Unsupported {
+		return mech
+	}
+	if _, i := r.implIndex(typ); i >= 0 {
+		return RegisteredInterface
+	}
+	return Unsupported
+}
+
 func (r *register[T]) getFromType(typ reflect.Type) T {
+	if i := r.typeIndex(typ); i >= 0 {
+		return r.getFromIndex(i)
+	}
+	return nil
+}
+
+// typeIndex returns the index of the func registered for the exact type typ,
+// or -1 when there is none.
+func (r *register[T]) typeIndex(typ reflect.Type) int {
 	if kind, ok := r.types[typ.Kind()]; ok {
 		if i, ok := kind[typ]; ok {
-			return r.getFromIndex(i)
+			return i
 		}
 	}
-	return nil
+	return -1
 }
 
+// getFromImpl returns the func registered for an interface which typ
+// implements. When typ implements multiple registered interfaces, the func
+// which was registered last is returned.
 func (r *register[T]) getFromImpl(typ reflect.Type) T {
+	if _, i := r.implIndex(typ); i >= 0 {
+		return r.getFromIndex(i)
+	}
+	return nil
+}
+
+// implIndex returns the interface type and index of the func, which was
+// registered last for an interface which typ implements. It returns an index
+// of -1 when typ does not implement any of the registered interfaces.
+func (r *register[T]) implIndex(typ reflect.Type) (reflect.Type, int) {
+	var iface reflect.Type
+	index := -1
 	for x, i := range r.types[reflect.Interface] {
-		if typ.Implements(x) {
-			return r.getFromIndex(i)
+		if i > index && typ.Implements(x) {
+			iface, index = x, i
 		}
 	}
-	return nil
+	return iface, index
+}
+
+// ambiguous indicates if typ is resolved by the register, and returns all
+// interface types that typ matches with when there are multiple candidates
+// with different funcs.
+func (r *register[T]) ambiguous(typ reflect.Type) (found bool, candidates []reflect.Type) {
+	r.mut.RLock()
+	defer r.mut.RUnlock()
+
+	for {
+		if r.getFromType(typ) != nil {
+			return true, nil
+		}
+		if typ.Kind() != reflect.Ptr {
+			break
+		}
+		typ = typ.Elem()
+	}
+
+	typ = reflect.PointerTo(typ)
+	funcs := make(map[uintptr]struct{}, 2)
+	for x, i := range r.types[reflect.Interface] {
+		if !typ.Implements(x) {
+			continue
+		}
+		found = true
+		if !isBinaryInterface(x) {
+			candidates = append(candidates, x)
+			funcs[r.pointer(i)] = struct{}{}
+		}
+	}
+	if len(funcs) < 2 {
+		return found, nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].String() < candidates[j].String()
+	})
+	return true, candidates
 }
 
 const panicInvalidFuncIndex = "rawconv: invalid index, func must exist!"
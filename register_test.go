@@ -5,12 +5,16 @@
 package rawconv
 
 import (
+	"encoding"
 	"net"
 	"net/url"
 	"reflect"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/go-pogo/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -71,3 +75,522 @@ func testRegisterFind(t *testing.T, i int, getFn func(reflect.Type) any) {
 		}
 	}
 }
+
+func TestDeregisterUnmarshalFunc(t *testing.T) {
+	type myType struct{}
+	typ := reflect.TypeOf(myType{})
+
+	assert.Nil(t, DeregisterUnmarshalFunc(typ))
+
+	fn := func(Value, any) error { return nil }
+	RegisterUnmarshalFunc(typ, fn)
+	have := DeregisterUnmarshalFunc(typ)
+	assert.Equal(t, reflect.ValueOf(fn).Pointer(), reflect.ValueOf(have).Pointer())
+	assert.Nil(t, GetUnmarshalFunc(typ))
+}
+
+func TestDeregisterMarshalFunc(t *testing.T) {
+	type myType struct{}
+	typ := reflect.TypeOf(myType{})
+
+	assert.Nil(t, DeregisterMarshalFunc(typ))
+
+	fn := func(any) (string, error) { return "", nil }
+	RegisterMarshalFunc(typ, fn)
+	have := DeregisterMarshalFunc(typ)
+	assert.Equal(t, reflect.ValueOf(fn).Pointer(), reflect.ValueOf(have).Pointer())
+	assert.Nil(t, GetMarshalFunc(typ))
+}
+
+func TestUnmarshaler_Deregister(t *testing.T) {
+	typ := reflect.TypeOf(time.Nanosecond)
+	fn := func(Value, any) error { return nil }
+
+	var u Unmarshaler
+	assert.Nil(t, u.Deregister(typ))
+
+	u.Register(typ, fn)
+	have := u.Deregister(typ)
+	assert.Equal(t, reflect.ValueOf(fn).Pointer(), reflect.ValueOf(have).Pointer())
+	assert.Equal(t, reflect.ValueOf(unmarshalDuration).Pointer(), reflect.ValueOf(u.Func(typ)).Pointer())
+}
+
+func TestMarshaler_Deregister(t *testing.T) {
+	typ := reflect.TypeOf(time.Nanosecond)
+	fn := func(any) (string, error) { return "", nil }
+
+	var m Marshaler
+	assert.Nil(t, m.Deregister(typ))
+
+	m.Register(typ, fn)
+	have := m.Deregister(typ)
+	assert.Equal(t, reflect.ValueOf(fn).Pointer(), reflect.ValueOf(have).Pointer())
+	assert.Equal(t, reflect.ValueOf(marshalDuration).Pointer(), reflect.ValueOf(m.Func(typ)).Pointer())
+}
+
+func TestResetRegisteredFuncs(t *testing.T) {
+	type myType struct{}
+	typ := reflect.TypeOf(myType{})
+	durTyp := reflect.TypeOf(time.Nanosecond)
+	timeTyp := reflect.TypeOf(time.Time{})
+
+	DeregisterUnmarshalFunc(durTyp)
+	RegisterUnmarshalFunc(durTyp, func(Value, any) error { return nil })
+	RegisterMarshalFunc(typ, func(any) (string, error) { return "", nil })
+	RegisterUnmarshalKindFunc(reflect.Struct, func(Value, any) error { return nil })
+
+	ResetRegisteredFuncs()
+
+	assert.Equal(t, reflect.ValueOf(unmarshalDuration).Pointer(), reflect.ValueOf(GetUnmarshalFunc(durTyp)).Pointer())
+	assert.Nil(t, GetMarshalFunc(typ))
+	assert.Nil(t, GetUnmarshalFunc(typ))
+
+	var have time.Time
+	u := Unmarshaler{Options: Options{TimeLayout: time.DateOnly}}
+	assert.NoError(t, u.Unmarshal("1997-08-29", reflect.ValueOf(&have)))
+	assert.Equal(t, RegisteredType, UnmarshalMechanism(timeTyp))
+}
+
+func TestRegister_pointerPrecedence(t *testing.T) {
+	type myType int
+	valTyp := reflect.TypeOf(myType(0))
+	ptrTyp := reflect.TypeOf((*myType)(nil))
+
+	valFn := func(val Value, dest any) error {
+		*dest.(*myType) = 1
+		return nil
+	}
+	ptrFn := func(val Value, dest any) error {
+		x := myType(2)
+		*dest.(**myType) = &x
+		return nil
+	}
+
+	assertPrecedence := func(t *testing.T, u *Unmarshaler) {
+		var val myType
+		assert.NoError(t, u.Unmarshal("x", reflect.ValueOf(&val)))
+		assert.Equal(t, myType(1), val)
+
+		var ptr *myType
+		assert.NoError(t, u.Unmarshal("x", reflect.ValueOf(&ptr)))
+		assert.Equal(t, myType(2), *ptr)
+
+		var ptrPtr **myType
+		assert.NoError(t, u.Unmarshal("x", reflect.ValueOf(&ptrPtr)))
+		assert.Equal(t, myType(2), **ptrPtr)
+	}
+
+	t.Run("pointer after value", func(t *testing.T) {
+		var u Unmarshaler
+		u.Register(valTyp, valFn)
+		u.Register(ptrTyp, ptrFn)
+		assertPrecedence(t, &u)
+	})
+	t.Run("value after pointer", func(t *testing.T) {
+		var u Unmarshaler
+		u.Register(ptrTyp, ptrFn)
+		u.Register(valTyp, valFn)
+		assertPrecedence(t, &u)
+	})
+	t.Run("value only", func(t *testing.T) {
+		var u Unmarshaler
+		u.Register(valTyp, valFn)
+
+		var ptr *myType
+		assert.NoError(t, u.Unmarshal("x", reflect.ValueOf(&ptr)))
+		assert.Equal(t, myType(1), *ptr)
+	})
+	t.Run("same method", func(t *testing.T) {
+		// method values of the same method are registered as different funcs
+		var u Unmarshaler
+		u.Register(valTyp, IntFormat{Base: 16}.Unmarshal)
+		u.Register(reflect.TypeOf((**myType)(nil)), IntFormat{Base: 2}.Unmarshal)
+
+		var val myType
+		assert.NoError(t, u.Unmarshal("10", reflect.ValueOf(&val)))
+		assert.Equal(t, myType(16), val)
+	})
+	t.Run("override", func(t *testing.T) {
+		fn1 := func(Value, any) error { return nil }
+		fn2 := func(Value, any) error { return nil }
+
+		var u Unmarshaler
+		u.Register(valTyp, fn1)
+		u.Register(valTyp, fn2)
+		assert.Equal(t, reflect.ValueOf(fn2).Pointer(), reflect.ValueOf(u.Func(valTyp)).Pointer())
+		assert.Equal(t, reflect.ValueOf(fn2).Pointer(), reflect.ValueOf(u.Func(ptrTyp)).Pointer())
+	})
+	t.Run("marshal", func(t *testing.T) {
+		var m Marshaler
+		m.Register(valTyp, func(any) (string, error) { return "value", nil })
+		m.Register(ptrTyp, func(v any) (string, error) {
+			_ = v.(*myType)
+			return "pointer", nil
+		})
+
+		x := myType(0)
+		val, err := m.Marshal(reflect.ValueOf(x))
+		assert.NoError(t, err)
+		assert.Equal(t, Value("value"), val)
+
+		val, err = m.Marshal(reflect.ValueOf(&x))
+		assert.NoError(t, err)
+		assert.Equal(t, Value("pointer"), val)
+	})
+	t.Run("global", func(t *testing.T) {
+		var m Marshaler
+		assert.NotPanics(t, func() {
+			m.Register(reflect.TypeOf((*time.Duration)(nil)), func(any) (string, error) {
+				return "", nil
+			})
+		})
+	})
+}
+
+type scanner interface{ Scan(src any) error }
+
+type scannableText struct{ val string }
+
+func (s *scannableText) UnmarshalText(b []byte) error {
+	s.val = "text:" + string(b)
+	return nil
+}
+
+func (s *scannableText) Scan(src any) error {
+	s.val = "scan:" + src.(string)
+	return nil
+}
+
+func TestUnmarshaler_Strict(t *testing.T) {
+	scanTyp := reflect.TypeOf((*scanner)(nil)).Elem()
+	scanFn := func(val Value, dest any) error {
+		return dest.(scanner).Scan(val.String())
+	}
+
+	t.Run("last registered", func(t *testing.T) {
+		var u Unmarshaler
+		u.Register(scanTyp, scanFn)
+
+		var have scannableText
+		assert.NoError(t, u.Unmarshal("foo", reflect.ValueOf(&have)))
+		assert.Equal(t, "scan:foo", have.val)
+	})
+	t.Run("ambiguous", func(t *testing.T) {
+		var u Unmarshaler
+		u.Strict = true
+		u.Register(scanTyp, scanFn)
+		u.Register(reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem(), unmarshalText)
+
+		var have scannableText
+		err := u.Unmarshal("foo", reflect.ValueOf(&have))
+		assert.ErrorIs(t, err, &AmbiguousTypeError{Type: reflect.TypeOf(&have)})
+		assert.Equal(t, "", have.val)
+
+		var ambErr *AmbiguousTypeError
+		assert.ErrorAs(t, err, &ambErr)
+		assert.Equal(t, []reflect.Type{
+			reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem(),
+			scanTyp,
+		}, ambErr.Candidates)
+		assert.Equal(t, "type `*rawconv.scannableText` matches multiple interfaces: "+
+			"`encoding.TextUnmarshaler`, `rawconv.scanner`", ambErr.Error())
+	})
+	t.Run("registered type", func(t *testing.T) {
+		var u Unmarshaler
+		u.Strict = true
+		u.Register(scanTyp, scanFn)
+		u.Register(reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem(), unmarshalText)
+		u.Register(reflect.TypeOf(scannableText{}), scanFn)
+
+		var have scannableText
+		assert.NoError(t, u.Unmarshal("foo", reflect.ValueOf(&have)))
+		assert.Equal(t, "scan:foo", have.val)
+	})
+	t.Run("single candidate", func(t *testing.T) {
+		var u Unmarshaler
+		u.Strict = true
+
+		var have scannableText
+		assert.NoError(t, u.Unmarshal("foo", reflect.ValueOf(&have)))
+		assert.Equal(t, "text:foo", have.val)
+	})
+}
+
+func TestRegister_concurrent(t *testing.T) {
+	const n = 50
+
+	types := make([]reflect.Type, n)
+	for i := range types {
+		types[i] = reflect.StructOf([]reflect.StructField{{
+			Name: "F" + strconv.Itoa(i),
+			Type: reflect.TypeOf(0),
+		}})
+	}
+	t.Cleanup(func() {
+		for _, typ := range types {
+			DeregisterUnmarshalFunc(typ)
+			DeregisterMarshalFunc(typ)
+		}
+	})
+
+	var u Unmarshaler
+	var m Marshaler
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(2)
+		go func(typ reflect.Type) {
+			defer wg.Done()
+			RegisterUnmarshalFunc(typ, func(Value, any) error { return nil })
+			RegisterMarshalFunc(typ, func(any) (string, error) { return "", nil })
+			u.Register(typ, func(Value, any) error { return nil })
+			m.Register(typ, func(any) (string, error) { return "", nil })
+		}(types[i])
+		go func(typ reflect.Type) {
+			defer wg.Done()
+			var d time.Duration
+			assert.NoError(t, Unmarshal("1s", &d))
+			assert.NoError(t, u.Unmarshal("1s", reflect.ValueOf(&d)))
+			_, err := m.Marshal(reflect.ValueOf(d))
+			assert.NoError(t, err)
+
+			_ = GetUnmarshalFunc(typ)
+			_ = u.Func(typ)
+			_ = m.Func(typ)
+			_ = UnmarshalMechanism(typ)
+		}(types[i])
+	}
+	wg.Wait()
+
+	for _, typ := range types {
+		assert.NotNil(t, GetUnmarshalFunc(typ))
+		assert.NotNil(t, u.Func(typ))
+		assert.NotNil(t, m.Func(typ))
+	}
+}
+
+func TestRegister_collections(t *testing.T) {
+	bytesType := reflect.TypeOf([]byte{})
+	arrayType := reflect.TypeOf([2]int{})
+	mapType := reflect.TypeOf(map[string]bool{})
+
+	var u Unmarshaler
+	u.Register(bytesType, func(val Value, dest any) error {
+		b, err := BytesBase64.Decode(val)
+		*dest.(*[]byte) = b
+		return err
+	})
+	u.Register(arrayType, func(val Value, dest any) error {
+		x, err := val.Int()
+		*dest.(*[2]int) = [2]int{x, x}
+		return err
+	})
+	u.Register(mapType, func(val Value, dest any) error {
+		*dest.(*map[string]bool) = map[string]bool{val.String(): true}
+		return nil
+	})
+
+	var m Marshaler
+	m.Register(bytesType, func(v any) (string, error) {
+		return BytesBase64.Encode(v.([]byte)), nil
+	})
+
+	t.Run("unmarshal", func(t *testing.T) {
+		var b []byte
+		assert.NoError(t, u.Unmarshal("cmF3", reflect.ValueOf(&b)))
+		assert.Equal(t, []byte("raw"), b)
+
+		var list [][]byte
+		assert.NoError(t, u.Unmarshal("cmF3,Y29udg==", reflect.ValueOf(&list)))
+		assert.Equal(t, [][]byte{[]byte("raw"), []byte("conv")}, list)
+
+		var arr [2]int
+		assert.NoError(t, u.Unmarshal("3", reflect.ValueOf(&arr)))
+		assert.Equal(t, [2]int{3, 3}, arr)
+
+		var set map[string]bool
+		assert.NoError(t, u.Unmarshal("a,b", reflect.ValueOf(&set)))
+		assert.Equal(t, map[string]bool{"a,b": true}, set)
+	})
+	t.Run("marshal", func(t *testing.T) {
+		have, err := m.Marshal(reflect.ValueOf([][]byte{[]byte("raw"), []byte("conv")}))
+		assert.NoError(t, err)
+		assert.Equal(t, Value("cmF3,Y29udg=="), have)
+	})
+	t.Run("mechanism", func(t *testing.T) {
+		assert.Equal(t, RegisteredType, u.Mechanism(bytesType))
+		assert.Equal(t, BuiltinKind, u.Mechanism(reflect.TypeOf([][]byte{})))
+	})
+	t.Run("unsupported kind", func(t *testing.T) {
+		assert.PanicsWithValue(t, panicUnsupportedKind, func() {
+			u.Register(reflect.TypeOf(make(chan int)), func(Value, any) error { return nil })
+		})
+	})
+	t.Run("empty interface", func(t *testing.T) {
+		anyType := reflect.TypeOf((*any)(nil)).Elem()
+		assert.PanicsWithValue(t, panicEmptyInterface, func() {
+			u.Register(anyType, func(Value, any) error { return nil })
+		})
+		assert.PanicsWithValue(t, panicEmptyInterface, func() {
+			m.Register(reflect.TypeOf((*interface{})(nil)).Elem(), func(any) (string, error) { return "", nil })
+		})
+		assert.PanicsWithValue(t, panicEmptyInterface, func() {
+			RegisterUnmarshalFuncFor[any](func(Value, any) error { return nil })
+		})
+		assert.Nil(t, GetUnmarshalFunc(reflect.TypeOf(0)), "must not hijack other types")
+	})
+}
+
+type level int
+
+var levelNames = []string{"debug", "info", "error"}
+
+func TestRegister_kinds(t *testing.T) {
+	levelType := reflect.TypeOf(level(0))
+
+	var u Unmarshaler
+	u.RegisterKind(reflect.Int, func(val Value, dest any) error {
+		rv := reflect.ValueOf(dest).Elem()
+		if rv.Type() != levelType {
+			x, err := val.Int()
+			rv.SetInt(int64(x) * 10)
+			return err
+		}
+		for i, name := range levelNames {
+			if val.String() == name {
+				rv.SetInt(int64(i))
+				return nil
+			}
+		}
+		return errors.New("unknown level")
+	})
+
+	var m Marshaler
+	m.RegisterKind(reflect.Int, func(v any) (string, error) {
+		rv := reflect.ValueOf(v)
+		if rv.Type() == levelType {
+			return levelNames[rv.Int()], nil
+		}
+		return strconv.FormatInt(rv.Int(), 10), nil
+	})
+
+	t.Run("unmarshal", func(t *testing.T) {
+		var lvl level
+		assert.NoError(t, u.Unmarshal("error", reflect.ValueOf(&lvl)))
+		assert.Equal(t, level(2), lvl)
+
+		var lvls []*level
+		assert.NoError(t, u.Unmarshal("info,debug", reflect.ValueOf(&lvls)))
+		assert.Equal(t, level(1), *lvls[0])
+		assert.Equal(t, level(0), *lvls[1])
+
+		var i int
+		assert.NoError(t, u.Unmarshal("2", reflect.ValueOf(&i)))
+		assert.Equal(t, 20, i)
+
+		var d time.Duration
+		assert.NoError(t, u.Unmarshal("2s", reflect.ValueOf(&d)))
+		assert.Equal(t, 2*time.Second, d)
+	})
+	t.Run("marshal", func(t *testing.T) {
+		have, err := m.Marshal(reflect.ValueOf([]level{1, 2}))
+		assert.NoError(t, err)
+		assert.Equal(t, Value("info,error"), have)
+
+		have, err = m.Marshal(reflect.ValueOf(time.Second))
+		assert.NoError(t, err)
+		assert.Equal(t, Value("1s"), have)
+	})
+	t.Run("exact type precedes kind", func(t *testing.T) {
+		var u2 Unmarshaler
+		u2.RegisterKind(reflect.Int, func(Value, any) error { return errors.New("kind") })
+		u2.Register(levelType, func(_ Value, dest any) error {
+			*dest.(*level) = 1
+			return nil
+		})
+
+		var lvl level
+		assert.NoError(t, u2.Unmarshal("x", reflect.ValueOf(&lvl)))
+		assert.Equal(t, level(1), lvl)
+	})
+	t.Run("mechanism", func(t *testing.T) {
+		assert.Equal(t, RegisteredKind, u.Mechanism(levelType))
+		assert.Equal(t, RegisteredKind, m.Mechanism(reflect.PtrTo(levelType)))
+		assert.Equal(t, RegisteredType, u.Mechanism(reflect.TypeOf(time.Second)))
+	})
+	t.Run("global", func(t *testing.T) {
+		fn := func(Value, any) error { return nil }
+		RegisterUnmarshalKindFunc(reflect.Struct, fn)
+		assert.NotNil(t, GetUnmarshalFunc(reflect.TypeOf(struct{}{})))
+		assert.NotNil(t, DeregisterUnmarshalKindFunc(reflect.Struct))
+		assert.Nil(t, GetUnmarshalFunc(reflect.TypeOf(struct{}{})))
+		assert.Nil(t, DeregisterUnmarshalKindFunc(reflect.Struct))
+	})
+	t.Run("unsupported kind", func(t *testing.T) {
+		for _, kind := range []reflect.Kind{reflect.Ptr, reflect.Interface, reflect.Chan} {
+			assert.PanicsWithValue(t, panicUnsupportedKind, func() {
+				u.RegisterKind(kind, func(Value, any) error { return nil })
+			})
+		}
+	})
+}
+
+func TestRegister_cache(t *testing.T) {
+	typ := reflect.TypeOf(scannableText{})
+	fn := func(Value, any) error { return nil }
+
+	var u Unmarshaler
+	u.Register(reflect.TypeOf((*scanner)(nil)).Elem(), fn)
+	assert.Equal(t, RegisteredInterface, u.register.mechanism(typ))
+
+	have, _, addr := u.register.resolve(typ, u.Options)
+	assert.Equal(t, reflect.ValueOf(fn).Pointer(), reflect.ValueOf(have).Pointer())
+	assert.True(t, addr)
+
+	res, ok := u.register.cache.Load(typ)
+	assert.True(t, ok)
+	assert.Equal(t, lookupResult{index: 0, addr: true}, res)
+
+	fn2 := func(Value, any) error { return nil }
+	u.Register(typ, fn2)
+	have, _, addr = u.register.resolve(typ, u.Options)
+	assert.Equal(t, reflect.ValueOf(fn2).Pointer(), reflect.ValueOf(have).Pointer())
+	assert.False(t, addr)
+
+	u.Deregister(typ)
+	have, _, _ = u.register.resolve(typ, u.Options)
+	assert.Equal(t, reflect.ValueOf(fn).Pointer(), reflect.ValueOf(have).Pointer())
+}
+
+type marshalOnlyText string
+
+func (m marshalOnlyText) MarshalText() ([]byte, error) { return []byte("text:" + m), nil }
+
+type unmarshalOnlyText struct{ val string }
+
+func (u *unmarshalOnlyText) UnmarshalText(b []byte) error {
+	u.val = "text:" + string(b)
+	return nil
+}
+
+func TestRegister_textInterfaces(t *testing.T) {
+	t.Run("marshal only", func(t *testing.T) {
+		// the builtin UnmarshalFunc is registered for encoding.TextUnmarshaler,
+		// so types which only implement encoding.TextMarshaler are unmarshaled
+		// by their kind instead of panicking
+		var have marshalOnlyText
+		assert.NotPanics(t, func() {
+			assert.NoError(t, Unmarshal("foo", &have))
+		})
+		assert.Equal(t, marshalOnlyText("foo"), have)
+
+		val, err := Marshal(have)
+		assert.NoError(t, err)
+		assert.Equal(t, Value("text:foo"), val)
+	})
+	t.Run("unmarshal only", func(t *testing.T) {
+		var have unmarshalOnlyText
+		assert.NoError(t, Unmarshal("foo", &have))
+		assert.Equal(t, "text:foo", have.val)
+		assert.Equal(t, RegisteredInterface, UnmarshalMechanism(reflect.TypeOf(have)))
+	})
+}
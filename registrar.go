@@ -0,0 +1,74 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"reflect"
+)
+
+// Registrar registers UnmarshalFuncs and MarshalFuncs, either globally or
+// for a specific Unmarshaler and/or Marshaler.
+type Registrar interface {
+	RegisterUnmarshalFunc(typ reflect.Type, fn UnmarshalFunc)
+	RegisterMarshalFunc(typ reflect.Type, fn MarshalFunc)
+}
+
+// Registerer registers its funcs with a Registrar. Packages which provide
+// support for additional types conventionally expose a Register(Registrar)
+// func, and use it to register their types globally when imported for their
+// side effects:
+//
+//	func init() { Register(rawconv.GlobalRegistrar()) }
+//
+//	func Register(reg rawconv.Registrar) {
+//		reg.RegisterUnmarshalFunc(reflect.TypeOf(MyType{}), unmarshalMyType)
+//		reg.RegisterMarshalFunc(reflect.TypeOf(MyType{}), marshalMyType)
+//	}
+type Registerer interface {
+	Register(reg Registrar)
+}
+
+// RegistererFunc is a func which implements Registerer.
+type RegistererFunc func(reg Registrar)
+
+func (fn RegistererFunc) Register(reg Registrar) { fn(reg) }
+
+// GlobalRegistrar returns a Registrar which registers its funcs globally with
+// RegisterUnmarshalFunc and RegisterMarshalFunc.
+func GlobalRegistrar() Registrar { return globalRegistrar{} }
+
+type globalRegistrar struct{}
+
+func (globalRegistrar) RegisterUnmarshalFunc(typ reflect.Type, fn UnmarshalFunc) {
+	RegisterUnmarshalFunc(typ, fn)
+}
+
+func (globalRegistrar) RegisterMarshalFunc(typ reflect.Type, fn MarshalFunc) {
+	RegisterMarshalFunc(typ, fn)
+}
+
+// NewRegistrar returns a Registrar which registers its funcs with Unmarshaler
+// u and Marshaler m. Funcs are ignored when their respective Unmarshaler or
+// Marshaler is nil.
+func NewRegistrar(u *Unmarshaler, m *Marshaler) Registrar {
+	return &registrar{u: u, m: m}
+}
+
+type registrar struct {
+	u *Unmarshaler
+	m *Marshaler
+}
+
+func (r *registrar) RegisterUnmarshalFunc(typ reflect.Type, fn UnmarshalFunc) {
+	if r.u != nil {
+		r.u.Register(typ, fn)
+	}
+}
+
+func (r *registrar) RegisterMarshalFunc(typ reflect.Type, fn MarshalFunc) {
+	if r.m != nil {
+		r.m.Register(typ, fn)
+	}
+}
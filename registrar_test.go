@@ -0,0 +1,61 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type registrarFixture struct{ val string }
+
+var registrarFixtureFuncs = RegistererFunc(func(reg Registrar) {
+	typ := reflect.TypeOf(registrarFixture{})
+	reg.RegisterUnmarshalFunc(typ, func(val Value, dest any) error {
+		dest.(*registrarFixture).val = val.String()
+		return nil
+	})
+	reg.RegisterMarshalFunc(typ, func(v any) (string, error) {
+		return v.(registrarFixture).val, nil
+	})
+})
+
+func TestNewRegistrar(t *testing.T) {
+	t.Run("both", func(t *testing.T) {
+		var u Unmarshaler
+		var m Marshaler
+		registrarFixtureFuncs.Register(NewRegistrar(&u, &m))
+
+		var have registrarFixture
+		assert.NoError(t, u.Unmarshal("foo", reflect.ValueOf(&have)))
+		assert.Equal(t, registrarFixture{val: "foo"}, have)
+
+		val, err := m.Marshal(reflect.ValueOf(have))
+		assert.NoError(t, err)
+		assert.Equal(t, Value("foo"), val)
+
+		assert.Nil(t, GetUnmarshalFunc(reflect.TypeOf(have)))
+		assert.Nil(t, GetMarshalFunc(reflect.TypeOf(have)))
+	})
+	t.Run("nil", func(t *testing.T) {
+		var u Unmarshaler
+		registrarFixtureFuncs.Register(NewRegistrar(&u, nil))
+		assert.NotNil(t, u.Func(reflect.TypeOf(registrarFixture{})))
+	})
+}
+
+func TestGlobalRegistrar(t *testing.T) {
+	typ := reflect.TypeOf(registrarFixture{})
+	registrarFixtureFuncs.Register(GlobalRegistrar())
+	t.Cleanup(func() {
+		DeregisterUnmarshalFunc(typ)
+		DeregisterMarshalFunc(typ)
+	})
+
+	assert.NotNil(t, GetUnmarshalFunc(typ))
+	assert.NotNil(t, GetMarshalFunc(typ))
+}
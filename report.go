@@ -0,0 +1,155 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"reflect"
+	"sort"
+)
+
+// Redacted replaces the raw value of fields which are marked as secret with
+// the `secret` tag option, e.g. `raw:"password,secret"`, in a Report.
+const Redacted = "[redacted]"
+
+// Outcome describes what happened to a key during UnmarshalStructReport.
+type Outcome uint8
+
+const (
+	// OutcomeSet indicates the value is unmarshaled to the field.
+	OutcomeSet Outcome = iota
+	// OutcomeMissing indicates there is no value for the field, which is
+	// left untouched.
+	OutcomeMissing
+	// OutcomeFailed indicates the value failed to unmarshal to the field.
+	OutcomeFailed
+	// OutcomeUnused indicates there is no field for the value's key.
+	OutcomeUnused
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeSet:
+		return "set"
+	case OutcomeMissing:
+		return "missing"
+	case OutcomeFailed:
+		return "failed"
+	case OutcomeUnused:
+		return "unused"
+	default:
+		return "invalid"
+	}
+}
+
+// MarshalText returns the string representation of the Outcome, so it is
+// readable when the Report is encoded as JSON.
+func (o Outcome) MarshalText() ([]byte, error) {
+	return []byte(o.String()), nil
+}
+
+// ReportEntry describes the outcome of a single key.
+type ReportEntry struct {
+	Key string `json:"key"`
+	// Raw is the raw value of the key, or Redacted when the field is marked
+	// as secret.
+	Raw string `json:"raw"`
+	// Type is the type of the field, it is empty for unused keys.
+	Type string `json:"type,omitempty"`
+	// Source is the source which is passed to UnmarshalStructReport.
+	Source  string  `json:"source,omitempty"`
+	Outcome Outcome `json:"outcome"`
+	// Error is the error message when Outcome is OutcomeFailed.
+	Error string `json:"error,omitempty"`
+}
+
+// Report is a machine-readable audit trail of a UnmarshalStructReport run.
+// It contains an entry for each field of the struct, in field order, followed
+// by the unused keys, sorted by key. The Entries of the Reports of multiple
+// sources can be combined using append.
+type Report struct {
+	Source  string        `json:"source,omitempty"`
+	Entries []ReportEntry `json:"entries"`
+}
+
+// UnmarshalStructReport is like UnmarshalStruct but returns a Report of every
+// field and key. Unlike UnmarshalStruct, it does not stop at the first field
+// which fails to unmarshal, so the Report is complete. The first error is
+// returned. Argument source describes the origin of values, e.g. "env" or a
+// file name, and is included in each entry.
+func UnmarshalStructReport(source string, values map[string]Value, v any) (Report, error) {
+	rv, err := ptrDest(v)
+	if err != nil {
+		return Report{Source: source}, err
+	}
+
+	u := Unmarshaler{Options: GlobalOptions()}
+	rep := Report{Source: source}
+	err = u.unmarshalStruct(values, rv, &rep)
+	return rep, err
+}
+
+// UnmarshalStructReport unmarshals values to the fields of struct v and
+// returns a Report. See UnmarshalStructReport for additional details.
+func (u *Unmarshaler) UnmarshalStructReport(source string, values map[string]Value, v reflect.Value) (Report, error) {
+	rep := Report{Source: source}
+	if err := validDest(v); err != nil {
+		return rep, err
+	}
+
+	err := u.unmarshalStruct(values, v, &rep)
+	return rep, err
+}
+
+// add an entry for field to the Report.
+func (r *Report) add(field structField, val Value, outcome Outcome, err error) {
+	if r == nil {
+		return
+	}
+
+	e := ReportEntry{
+		Key:     field.name,
+		Raw:     val.String(),
+		Type:    field.typ.String(),
+		Source:  r.Source,
+		Outcome: outcome,
+	}
+	if field.secret && e.Raw != "" {
+		e.Raw = Redacted
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	r.Entries = append(r.Entries, e)
+}
+
+// unused adds an entry for each key of values which has no field entry in
+// the Report.
+func (r *Report) unused(values map[string]Value) {
+	if r == nil {
+		return
+	}
+
+	seen := make(map[string]struct{}, len(r.Entries))
+	for _, e := range r.Entries {
+		seen[e.Key] = struct{}{}
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if _, ok := seen[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		r.Entries = append(r.Entries, ReportEntry{
+			Key:     key,
+			Raw:     values[key].String(),
+			Source:  r.Source,
+			Outcome: OutcomeUnused,
+		})
+	}
+}
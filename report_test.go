@@ -0,0 +1,65 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalStructReport(t *testing.T) {
+	type config struct {
+		Host     string `raw:"host"`
+		Port     int    `raw:"port"`
+		Password string `raw:"password,secret"`
+		Debug    bool   `raw:"debug"`
+	}
+
+	var have config
+	rep, err := UnmarshalStructReport("env", map[string]Value{
+		"host":     "localhost",
+		"port":     "x",
+		"password": "hunter2",
+		"zzz":      "unused",
+		"aaa":      "unused",
+	}, &have)
+
+	assert.ErrorIs(t, err, ErrParseFailure)
+	assert.Equal(t, config{Host: "localhost", Password: "hunter2"}, have)
+	assert.Equal(t, "env", rep.Source)
+
+	errMsg := rep.Entries[1].Error
+	assert.NotEmpty(t, errMsg)
+	assert.Equal(t, []ReportEntry{
+		{Key: "host", Raw: "localhost", Type: "string", Source: "env", Outcome: OutcomeSet},
+		{Key: "port", Raw: "x", Type: "int", Source: "env", Outcome: OutcomeFailed, Error: errMsg},
+		{Key: "password", Raw: Redacted, Type: "string", Source: "env", Outcome: OutcomeSet},
+		{Key: "debug", Type: "bool", Source: "env", Outcome: OutcomeMissing},
+		{Key: "aaa", Raw: "unused", Source: "env", Outcome: OutcomeUnused},
+		{Key: "zzz", Raw: "unused", Source: "env", Outcome: OutcomeUnused},
+	}, rep.Entries)
+
+	t.Run("json", func(t *testing.T) {
+		b, err := json.Marshal(rep.Entries[2:4])
+		assert.NoError(t, err)
+		assert.JSONEq(t, `[
+			{"key":"password","raw":"[redacted]","type":"string","source":"env","outcome":"set"},
+			{"key":"debug","raw":"","type":"bool","source":"env","outcome":"missing"}
+		]`, string(b))
+	})
+	t.Run("invalid destination", func(t *testing.T) {
+		var u Unmarshaler
+		_, err := u.UnmarshalStructReport("", nil, reflect.Value{})
+		assert.ErrorIs(t, err, ErrNilDestination)
+	})
+}
+
+func TestOutcome_String(t *testing.T) {
+	assert.Equal(t, "unused", OutcomeUnused.String())
+	assert.Equal(t, "invalid", Outcome(99).String())
+}
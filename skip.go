@@ -0,0 +1,106 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/go-pogo/errors"
+)
+
+// ErrSkip can be returned by an UnmarshalFunc or MarshalFunc to indicate it
+// cannot handle the particular value. The next candidate func is tried
+// instead, in order of: the func registered for the type to the Unmarshaler
+// or Marshaler, the globally registered func, the func registered for the
+// kind to the Unmarshaler or Marshaler, and the globally registered func for
+// the kind. When none of them handles the value, the builtin logic for the
+// kind of the type is used. A func which returns ErrSkip must leave its
+// destination untouched.
+const ErrSkip errors.Msg = "skip func"
+
+// execFallbacks executes the candidate UnmarshalFuncs for the type of dest,
+// except for the first, until one of them does not return ErrSkip.
+func (u *Unmarshaler) execFallbacks(ctx context.Context, v Value, dest reflect.Value) error {
+	typ := dest.Type()
+	regs := []*register[UnmarshalFunc]{&u.register}
+	if &u.register != &unmarshaler.register {
+		regs = append(regs, &unmarshaler.register)
+	}
+
+	candidates := make([]UnmarshalFuncCtx, 0, 2*len(regs))
+	for _, r := range regs {
+		if fn, ctxFn, _ := r.resolve(typ, u.Options); fn != nil {
+			candidates = append(candidates, unmarshalFuncCtx(fn, ctxFn))
+		}
+	}
+	for _, r := range regs {
+		if fn, ctxFn := r.resolveKind(typ); fn != nil {
+			candidates = append(candidates, unmarshalFuncCtx(fn, ctxFn))
+		}
+	}
+
+	for _, fn := range candidates[1:] {
+		if err := fn.Exec(ctx, v, dest); !errors.Is(err, ErrSkip) {
+			return err
+		}
+	}
+	return errors.New(ErrSkip)
+}
+
+func unmarshalFuncCtx(fn UnmarshalFunc, ctxFn any) UnmarshalFuncCtx {
+	if ctxFn != nil {
+		return ctxFn.(UnmarshalFuncCtx)
+	}
+	return fn.ctx()
+}
+
+// execFallbacks executes the candidate MarshalFuncs for the type of val,
+// except for the first, until one of them does not return ErrSkip.
+func (m *Marshaler) execFallbacks(ctx context.Context, val reflect.Value) (string, error) {
+	typ := val.Type()
+	regs := []*register[MarshalFunc]{&m.register}
+	if &m.register != &marshaler.register {
+		regs = append(regs, &marshaler.register)
+	}
+
+	type candidate struct {
+		fn   MarshalFuncCtx
+		addr bool
+	}
+
+	candidates := make([]candidate, 0, 2*len(regs))
+	for _, r := range regs {
+		if fn, ctxFn, addr := r.resolve(typ, m.Options); fn != nil {
+			candidates = append(candidates, candidate{marshalFuncCtx(fn, ctxFn), addr})
+		}
+	}
+	for _, r := range regs {
+		if fn, ctxFn := r.resolveKind(typ); fn != nil {
+			candidates = append(candidates, candidate{marshalFuncCtx(fn, ctxFn), false})
+		}
+	}
+
+	for _, c := range candidates[1:] {
+		var str string
+		var err error
+		if c.addr {
+			str, err = c.fn.execAddr(ctx, val)
+		} else {
+			str, err = c.fn.exec(ctx, val)
+		}
+		if !errors.Is(err, ErrSkip) {
+			return str, err
+		}
+	}
+	return "", errors.New(ErrSkip)
+}
+
+func marshalFuncCtx(fn MarshalFunc, ctxFn any) MarshalFuncCtx {
+	if ctxFn != nil {
+		return ctxFn.(MarshalFuncCtx)
+	}
+	return fn.ctx()
+}
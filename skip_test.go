@@ -0,0 +1,94 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-pogo/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshaler_ErrSkip(t *testing.T) {
+	var u Unmarshaler
+	u.Register(reflect.TypeOf(0), func(val Value, dest any) error {
+		hex, ok := strings.CutPrefix(val.String(), "0x")
+		if !ok {
+			return ErrSkip
+		}
+		x, err := strconv.ParseInt(hex, 16, 0)
+		*dest.(*int) = int(x)
+		return err
+	})
+	u.Register(reflect.TypeOf(time.Duration(0)), func(val Value, dest any) error {
+		days, ok := strings.CutSuffix(val.String(), "d")
+		if !ok {
+			return errors.New(ErrSkip)
+		}
+		x, err := strconv.Atoi(days)
+		*dest.(*time.Duration) = time.Duration(x) * 24 * time.Hour
+		return err
+	})
+
+	t.Run("builtin", func(t *testing.T) {
+		var have []int
+		assert.NoError(t, u.Unmarshal("0x1f,31", reflect.ValueOf(&have)))
+		assert.Equal(t, []int{31, 31}, have)
+	})
+	t.Run("global", func(t *testing.T) {
+		var have []time.Duration
+		assert.NoError(t, u.Unmarshal("2d,1h", reflect.ValueOf(&have)))
+		assert.Equal(t, []time.Duration{48 * time.Hour, time.Hour}, have)
+	})
+	t.Run("kind", func(t *testing.T) {
+		u2 := Unmarshaler{}
+		u2.Register(reflect.TypeOf(level(0)), func(Value, any) error { return ErrSkip })
+		u2.RegisterKind(reflect.Int, func(_ Value, dest any) error {
+			*dest.(*level) = 2
+			return nil
+		})
+
+		var have level
+		assert.NoError(t, u2.Unmarshal("x", reflect.ValueOf(&have)))
+		assert.Equal(t, level(2), have)
+	})
+	t.Run("unsupported", func(t *testing.T) {
+		type myType struct{}
+		var u2 Unmarshaler
+		u2.Register(reflect.TypeOf(myType{}), func(Value, any) error { return ErrSkip })
+
+		var have myType
+		assert.ErrorIs(t, u2.Unmarshal("x", reflect.ValueOf(&have)), &UnsupportedTypeError{Type: reflect.TypeOf(&have)})
+	})
+}
+
+func TestMarshaler_ErrSkip(t *testing.T) {
+	var m Marshaler
+	m.Register(reflect.TypeOf(""), func(v any) (string, error) {
+		if str := v.(string); strings.HasPrefix(str, "!") {
+			return strings.ToUpper(str[1:]), nil
+		}
+		return "", ErrSkip
+	})
+	m.Register(reflect.TypeOf(time.Duration(0)), func(any) (string, error) {
+		return "", ErrSkip
+	})
+
+	have, err := m.Marshal(reflect.ValueOf([]string{"!foo", "bar"}))
+	assert.NoError(t, err)
+	assert.Equal(t, Value("FOO,bar"), have)
+
+	have, err = m.Marshal(reflect.ValueOf(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, Value("1m0s"), have)
+
+	b, err := m.MarshalAppend(nil, reflect.ValueOf(map[string]string{"a": "!b"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "a=B", string(b))
+}
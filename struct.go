@@ -0,0 +1,367 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/go-pogo/errors"
+)
+
+// StructTag is the name of the struct tag which is used by UnmarshalStruct and
+// MarshalStruct to determine the name of a struct field.
+const StructTag = "raw"
+
+const ErrStructExpected errors.Msg = "expected a struct"
+
+// UnmarshalStruct unmarshals the Value of each key in values to the struct
+// field with the same name, within the struct pointed to by v, using the
+// Options set with SetGlobalOptions.
+//
+// The name of a field is determined by its `raw:"name"` tag, or its field name
+// when there is no tag. Fields with tag `raw:"-"` and unexported fields are
+// ignored. The fields of embedded structs, or pointers to structs, are treated
+// as if they are fields of the outer struct. Fields without a matching key in
+// values are left untouched.
+func UnmarshalStruct(values map[string]Value, v any) error {
+	rv, err := ptrDest(v)
+	if err != nil {
+		return err
+	}
+
+	u := Unmarshaler{Options: GlobalOptions()}
+	return u.unmarshalStruct(values, rv, nil)
+}
+
+// UnmarshalStructAtomic is like UnmarshalStruct, but first unmarshals all
+// values to new values of the types of their fields. Only when every value
+// unmarshals successfully, the fields of the struct pointed to by v are set.
+// Because of this, existing pointers, maps and slices of fields are replaced
+// instead of updated. Fields without a matching key in values, or with an
+// empty Value, are left untouched.
+// The returned Revert restores the fields to the values they had before they
+// were set. It is never nil, and does nothing when an error is returned.
+func UnmarshalStructAtomic(values map[string]Value, v any) (Revert, error) {
+	rv, err := ptrDest(v)
+	if err != nil {
+		return noRevert, err
+	}
+
+	u := Unmarshaler{Options: GlobalOptions()}
+	return u.unmarshalStructAtomic(values, rv)
+}
+
+// MarshalStruct marshals the fields of struct v, or the struct v points to,
+// using the Options set with SetGlobalOptions. See UnmarshalStruct for details
+// on how the fields and their names are determined. The fields of a nil
+// embedded struct pointer are not included in the result.
+func MarshalStruct(v any) (map[string]Value, error) {
+	m := Marshaler{Options: GlobalOptions()}
+	return m.MarshalStruct(reflect.ValueOf(v))
+}
+
+// UnmarshalStruct unmarshals values to the fields of struct v. See
+// UnmarshalStruct for additional details.
+func (u *Unmarshaler) UnmarshalStruct(values map[string]Value, v reflect.Value) error {
+	if err := validDest(v); err != nil {
+		return err
+	}
+	return u.unmarshalStruct(values, v, nil)
+}
+
+// UnmarshalStructAtomic unmarshals values to the fields of struct v, but only
+// when all values unmarshal successfully. See UnmarshalStructAtomic for
+// additional details.
+func (u *Unmarshaler) UnmarshalStructAtomic(values map[string]Value, v reflect.Value) (Revert, error) {
+	if err := validDest(v); err != nil {
+		return noRevert, err
+	}
+	return u.unmarshalStructAtomic(values, v)
+}
+
+// unmarshalStruct unmarshals values to the fields of struct v. When rep is
+// not nil, an entry is added to it for each field and each unused key, and
+// fields are unmarshaled until all are handled instead of until the first
+// error.
+func (u *Unmarshaler) unmarshalStruct(values map[string]Value, v reflect.Value, rep *Report) error {
+	v, err := structDest(v)
+	if err != nil {
+		return err
+	}
+
+	var firstErr error
+	for _, field := range structFields(v.Type()) {
+		val, ok := values[field.name]
+		if !ok {
+			rep.add(field, val, OutcomeMissing, nil)
+			continue
+		}
+
+		fv, err := fieldByIndex(v, field.index, true)
+		if err == nil {
+			err = u.unmarshal(context.Background(), val, fv, field.name, 0)
+		}
+		if err != nil {
+			err = fieldErr(field.name, err)
+			if rep == nil {
+				return err
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+			rep.add(field, val, OutcomeFailed, err)
+			continue
+		}
+		rep.add(field, val, OutcomeSet, nil)
+	}
+
+	rep.unused(values)
+	return firstErr
+}
+
+// Revert restores the fields of a struct to the values they had before
+// UnmarshalStructAtomic set them.
+type Revert func()
+
+func noRevert() {}
+
+func (u *Unmarshaler) unmarshalStructAtomic(values map[string]Value, v reflect.Value) (Revert, error) {
+	v, err := structDest(v)
+	if err != nil {
+		return noRevert, err
+	}
+
+	fields := structFields(v.Type())
+	staged := make([]reflect.Value, len(fields))
+	for i, field := range fields {
+		val, ok := values[field.name]
+		if !ok || (val.IsEmpty() && u.skipsEmpty(field.typ)) {
+			continue
+		}
+
+		sv := reflect.New(field.typ).Elem()
+		if err = u.unmarshal(context.Background(), val, sv, field.name, 0); err != nil {
+			return noRevert, fieldErr(field.name, err)
+		}
+		staged[i] = sv
+	}
+
+	// prev contains the fields, or the nil embedded struct pointers they are
+	// part of, with a copy of their values before they are set
+	var prev [][2]reflect.Value
+	for i, field := range fields {
+		if !staged[i].IsValid() {
+			continue
+		}
+
+		if p, ok := nilEmbedded(v, field.index); ok {
+			prev = append(prev, [2]reflect.Value{p, reflect.Zero(p.Type())})
+		}
+
+		fv, err := fieldByIndex(v, field.index, true)
+		if err != nil {
+			return noRevert, fieldErr(field.name, err)
+		}
+
+		cp := reflect.New(fv.Type()).Elem()
+		cp.Set(fv)
+		prev = append(prev, [2]reflect.Value{fv, cp})
+		fv.Set(staged[i])
+	}
+
+	return func() {
+		for i := len(prev) - 1; i >= 0; i-- {
+			prev[i][0].Set(prev[i][1])
+		}
+	}, nil
+}
+
+// nilEmbedded returns the first nil embedded struct pointer within v, on the
+// path to the nested field at index.
+func nilEmbedded(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return v, true
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, false
+}
+
+// structDest returns the struct value v points to. Any nil pointer is
+// allocated.
+func structDest(v reflect.Value) (reflect.Value, error) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			if !v.CanSet() {
+				return v, errors.New(ErrUnableToSet)
+			}
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return v, errors.New(ErrStructExpected)
+	}
+	return v, nil
+}
+
+// MarshalStruct marshals the fields of struct v. See MarshalStruct for
+// additional details.
+func (m *Marshaler) MarshalStruct(v reflect.Value) (map[string]Value, error) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, errors.New(ErrStructExpected)
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, errors.New(ErrStructExpected)
+	}
+
+	fields := structFields(v.Type())
+	res := make(map[string]Value, len(fields))
+	for _, field := range fields {
+		fv, err := fieldByIndex(v, field.index, false)
+		if err != nil {
+			// embedded struct pointer is nil
+			continue
+		}
+
+		str, err := m.marshal(context.Background(), fv, 0)
+		if err != nil {
+			return nil, fieldErr(field.name, err)
+		}
+		res[field.name] = Value(str)
+	}
+	return res, nil
+}
+
+type structField struct {
+	name   string
+	index  []int
+	typ    reflect.Type
+	secret bool
+}
+
+// structFields returns the fields of struct type typ, including the fields of
+// embedded structs which are not registered. Fields of the outer struct take
+// precedence over fields of embedded structs with the same name.
+func structFields(typ reflect.Type) []structField {
+	return collectFields(typ, map[reflect.Type]struct{}{typ: {}})
+}
+
+// collectFields collects the fields of typ, see structFields. Embedded
+// structs of a type which is already in path, e.g. a struct which embeds a
+// pointer to itself, are skipped to prevent endless recursion.
+func collectFields(typ reflect.Type, path map[reflect.Type]struct{}) []structField {
+	var fields, embedded []structField
+	seen := make(map[string]struct{}, typ.NumField())
+
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		name, secret, ok := fieldName(f)
+		if !ok {
+			continue
+		}
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				if !f.IsExported() {
+					// unable to allocate an unexported struct pointer
+					continue
+				}
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && !registered(ft) {
+				if _, ok := path[ft]; ok {
+					continue
+				}
+
+				path[ft] = struct{}{}
+				for _, ef := range collectFields(ft, path) {
+					ef.index = append([]int{i}, ef.index...)
+					embedded = append(embedded, ef)
+				}
+				delete(path, ft)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		seen[name] = struct{}{}
+		fields = append(fields, structField{
+			name:   name,
+			index:  []int{i},
+			typ:    f.Type,
+			secret: secret,
+		})
+	}
+
+	for _, ef := range embedded {
+		if _, ok := seen[ef.name]; ok {
+			continue
+		}
+		seen[ef.name] = struct{}{}
+		fields = append(fields, ef)
+	}
+	return fields
+}
+
+// registered indicates if a func is registered globally for typ, in which
+// case an embedded struct of typ is not flattened.
+func registered(typ reflect.Type) bool {
+	return unmarshaler.register.find(typ) != nil || marshaler.register.find(typ) != nil
+}
+
+// fieldName returns the name from the StructTag of f, if the field is marked
+// as secret with the `secret` tag option, and false when f should be ignored.
+func fieldName(f reflect.StructField) (string, bool, bool) {
+	tag := f.Tag.Get(StructTag)
+	if tag == "-" {
+		return "", false, false
+	}
+
+	name, opts, _ := strings.Cut(tag, ",")
+	var secret bool
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		if opt == "secret" {
+			secret = true
+		}
+	}
+	return name, secret, true
+}
+
+// fieldByIndex returns the nested field of v at index. Any nil embedded
+// struct pointer is allocated when alloc is true, otherwise an ErrUnableToSet
+// error is returned.
+func fieldByIndex(v reflect.Value, index []int, alloc bool) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc || !v.CanSet() {
+					return v, errors.New(ErrUnableToSet)
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
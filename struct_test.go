@@ -0,0 +1,242 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type structEmbedded struct {
+	Port    int `raw:"port"`
+	Timeout time.Duration
+}
+
+type StructEmbeddedPtr struct {
+	Debug bool `raw:"debug"`
+	Name  string
+}
+
+type structFixture struct {
+	structEmbedded
+	*StructEmbeddedPtr
+	time.Time
+
+	Name     string    `raw:"name"`
+	Hosts    []string  `raw:"hosts,omitempty"`
+	URL      *url.URL  `raw:"url"`
+	Ignored  string    `raw:"-"`
+	Interval **float64 `raw:"interval"`
+	private  string
+}
+
+func TestUnmarshalStruct(t *testing.T) {
+	t.Run("fields", func(t *testing.T) {
+		var have structFixture
+		assert.NoError(t, UnmarshalStruct(map[string]Value{
+			"name":     "foo",
+			"Name":     "bar",
+			"hosts":    "a,b",
+			"url":      "http://localhost",
+			"Ignored":  "bar",
+			"-":        "bar",
+			"interval": "1.5",
+			"private":  "baz",
+			"port":     "8080",
+			"Timeout":  "5s",
+			"debug":    "true",
+			"Time":     "1997-08-29",
+		}, &have))
+
+		interval := ptr(1.5)
+		assert.Equal(t, structFixture{
+			structEmbedded:    structEmbedded{Port: 8080, Timeout: 5 * time.Second},
+			StructEmbeddedPtr: &StructEmbeddedPtr{Debug: true, Name: "bar"},
+			Time:              time.Date(1997, 8, 29, 0, 0, 0, 0, time.UTC),
+			Name:              "foo",
+			Hosts:             []string{"a", "b"},
+			URL:               &url.URL{Scheme: "http", Host: "localhost"},
+			Interval:          &interval,
+		}, have)
+	})
+	t.Run("untouched", func(t *testing.T) {
+		have := structFixture{Name: "foo"}
+		assert.NoError(t, UnmarshalStruct(map[string]Value{"port": "1"}, &have))
+		assert.Equal(t, structFixture{Name: "foo", structEmbedded: structEmbedded{Port: 1}}, have)
+	})
+	t.Run("error", func(t *testing.T) {
+		var have structFixture
+		err := UnmarshalStruct(map[string]Value{"port": "x"}, &have)
+		assert.ErrorIs(t, err, ErrParseFailure)
+		assert.Contains(t, err.Error(), "field `port`")
+	})
+	t.Run("invalid", func(t *testing.T) {
+		assert.ErrorIs(t, UnmarshalStruct(nil, structFixture{}), ErrPointerExpected)
+		assert.ErrorIs(t, UnmarshalStruct(nil, ptr("foo")), ErrStructExpected)
+
+		var u Unmarshaler
+		assert.ErrorIs(t, u.UnmarshalStruct(nil, reflect.ValueOf(structFixture{})), ErrUnableToSet)
+	})
+	t.Run("nil struct pointer", func(t *testing.T) {
+		var have *structEmbedded
+		assert.NoError(t, UnmarshalStruct(map[string]Value{"port": "1"}, &have))
+		assert.Equal(t, &structEmbedded{Port: 1}, have)
+	})
+}
+
+func TestUnmarshalStructAtomic(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		have := structFixture{Name: "keep", Hosts: []string{"x"}}
+		want := have
+
+		revert, err := UnmarshalStructAtomic(map[string]Value{
+			"name":  "",
+			"hosts": "a,b",
+			"port":  "8080",
+			"debug": "true",
+			"Name":  "bar",
+		}, &have)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "keep", have.Name)
+		assert.Equal(t, []string{"a", "b"}, have.Hosts)
+		assert.Equal(t, 8080, have.Port)
+		assert.Equal(t, &StructEmbeddedPtr{Debug: true, Name: "bar"}, have.StructEmbeddedPtr)
+
+		revert()
+		assert.Equal(t, want, have)
+	})
+	t.Run("revert embedded", func(t *testing.T) {
+		have := structFixture{StructEmbeddedPtr: &StructEmbeddedPtr{Name: "foo"}}
+		embedded := have.StructEmbeddedPtr
+
+		revert, err := UnmarshalStructAtomic(map[string]Value{
+			"debug": "true",
+			"Name":  "bar",
+		}, &have)
+		assert.NoError(t, err)
+		assert.Equal(t, &StructEmbeddedPtr{Debug: true, Name: "bar"}, have.StructEmbeddedPtr)
+
+		revert()
+		assert.Same(t, embedded, have.StructEmbeddedPtr)
+		assert.Equal(t, &StructEmbeddedPtr{Name: "foo"}, have.StructEmbeddedPtr)
+	})
+	t.Run("failure", func(t *testing.T) {
+		interval := ptr(1.5)
+		have := structFixture{Name: "keep", Interval: &interval}
+		want := have
+
+		revert, err := UnmarshalStructAtomic(map[string]Value{
+			"name":     "foo",
+			"interval": "2.5",
+			"port":     "x",
+			"debug":    "true",
+		}, &have)
+
+		assert.ErrorIs(t, err, ErrParseFailure)
+		assert.ErrorContains(t, err, "field `port`")
+		assert.Equal(t, want, have)
+		assert.Equal(t, 1.5, **have.Interval)
+
+		revert()
+		assert.Equal(t, want, have)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		var u Unmarshaler
+		var x int
+		revert, err := u.UnmarshalStructAtomic(nil, reflect.ValueOf(&x))
+		assert.ErrorIs(t, err, ErrStructExpected)
+		assert.NotNil(t, revert)
+	})
+}
+
+func TestMarshalStruct(t *testing.T) {
+	t.Run("fields", func(t *testing.T) {
+		interval := ptr(1.5)
+		have, err := MarshalStruct(&structFixture{
+			structEmbedded:    structEmbedded{Port: 8080},
+			StructEmbeddedPtr: &StructEmbeddedPtr{Debug: true, Name: "bar"},
+			Time:              time.Date(1997, 8, 29, 0, 0, 0, 0, time.UTC),
+			Name:              "foo",
+			Hosts:             []string{"a", "b"},
+			Ignored:           "ignored",
+			Interval:          &interval,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]Value{
+			"port":     "8080",
+			"Timeout":  "0s",
+			"debug":    "true",
+			"Name":     "bar",
+			"Time":     "1997-08-29T00:00:00Z",
+			"name":     "foo",
+			"hosts":    "a,b",
+			"url":      "",
+			"interval": "1.5",
+		}, have)
+	})
+	t.Run("nil embedded pointer", func(t *testing.T) {
+		have, err := MarshalStruct(structFixture{})
+		assert.NoError(t, err)
+		assert.NotContains(t, have, "debug")
+		assert.Contains(t, have, "port")
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := MarshalStruct("foo")
+		assert.ErrorIs(t, err, ErrStructExpected)
+		_, err = MarshalStruct((*structFixture)(nil))
+		assert.ErrorIs(t, err, ErrStructExpected)
+	})
+}
+
+func TestStructFields_shadowed(t *testing.T) {
+	type fixture struct {
+		structEmbedded
+		Port string `raw:"port"`
+	}
+
+	var have fixture
+	assert.NoError(t, UnmarshalStruct(map[string]Value{"port": "outer"}, &have))
+	assert.Equal(t, fixture{Port: "outer"}, have)
+}
+
+type RecursiveNode struct {
+	*RecursiveNode
+	X int
+}
+
+type RecursiveA struct {
+	*RecursiveB
+	A string
+}
+
+type RecursiveB struct {
+	*RecursiveA
+	B string
+}
+
+func TestStructFields_recursive(t *testing.T) {
+	t.Run("self", func(t *testing.T) {
+		var have RecursiveNode
+		assert.NoError(t, UnmarshalStruct(map[string]Value{"X": "1"}, &have))
+		assert.Equal(t, RecursiveNode{X: 1}, have)
+
+		m, err := MarshalStruct(have)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]Value{"X": "1"}, m)
+	})
+	t.Run("mutual", func(t *testing.T) {
+		var have RecursiveA
+		assert.NoError(t, UnmarshalStruct(map[string]Value{"A": "a", "B": "b"}, &have))
+		assert.Equal(t, "a", have.A)
+		if assert.NotNil(t, have.RecursiveB) {
+			assert.Equal(t, "b", have.B)
+		}
+	})
+}
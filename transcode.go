@@ -0,0 +1,38 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/go-pogo/errors"
+)
+
+// Transcode converts Value val from one raw representation to another. It
+// unmarshals val to a new value of type typ, using Options from, and marshals
+// the result using Options to.
+//
+//	// "a=1;b=2" -> "a:1,b:2"
+//	val, err := rawconv.Transcode("a=1;b=2", reflect.TypeOf(map[string]int{}),
+//		rawconv.Options{ItemsSeparator: ";"},
+//		rawconv.Options{KeyValueSeparator: ":"},
+//	)
+//
+// Registered (custom) types are resolved using the global registries.
+func Transcode(val Value, typ reflect.Type, from, to Options) (Value, error) {
+	if typ == nil {
+		return "", errors.New(ErrNilDestination)
+	}
+
+	rv := reflect.New(typ)
+	u := Unmarshaler{Options: from}
+	if err := u.unmarshal(context.Background(), val, rv, "", 0); err != nil {
+		return "", err
+	}
+
+	m := Marshaler{Options: to}
+	return m.Marshal(rv.Elem())
+}
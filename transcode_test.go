@@ -0,0 +1,84 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranscode(t *testing.T) {
+	type token []byte
+
+	tests := map[string]struct {
+		input    Value
+		typ      reflect.Type
+		from, to Options
+		want     Value
+		wantErr  error
+	}{
+		"slice": {
+			input: "1; 2; 3",
+			typ:   reflect.TypeOf([]int{}),
+			from:  Options{ItemsSeparator: ";"},
+			want:  "1,2,3",
+		},
+		"map": {
+			input: "a=1;a=2",
+			typ:   reflect.TypeOf(map[string]int{}),
+			from:  Options{ItemsSeparator: ";"},
+			to:    Options{KeyValueSeparator: ":"},
+			want:  "a:2",
+		},
+		"time": {
+			input: "29/08/1997",
+			typ:   reflect.TypeOf(time.Time{}),
+			from:  Options{TimeLayout: "02/01/2006"},
+			to:    Options{TimeLayout: time.DateOnly},
+			want:  "1997-08-29",
+		},
+		"bytes": {
+			input: "cmF3Y29udg==",
+			typ:   reflect.TypeOf(token{}),
+			from:  Options{BytesEncoding: BytesBase64},
+			to:    Options{BytesEncoding: BytesHex},
+			want:  "726177636f6e76",
+		},
+		"bool tokens": {
+			input: "yes,off",
+			typ:   reflect.TypeOf([]bool{}),
+			from: Options{
+				BoolTrueTokens:  []string{"yes"},
+				BoolFalseTokens: []string{"no", "off"},
+			},
+			to:   Options{BoolTrueTokens: []string{"on"}},
+			want: "on,false",
+		},
+		"parse failure": {
+			input:   "a,b",
+			typ:     reflect.TypeOf([]int{}),
+			wantErr: ErrParseFailure,
+		},
+		"nil type": {
+			input:   "a",
+			wantErr: ErrNilDestination,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			have, haveErr := Transcode(tc.input, tc.typ, tc.from, tc.to)
+			assert.Equal(t, tc.want, have)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, haveErr, tc.wantErr)
+			} else {
+				assert.NoError(t, haveErr)
+			}
+		})
+	}
+}
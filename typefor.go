@@ -0,0 +1,12 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.22
+
+package rawconv
+
+import "reflect"
+
+// typeFor returns the reflect.Type of T.
+func typeFor[T any]() reflect.Type { return reflect.TypeFor[T]() }
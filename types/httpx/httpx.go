@@ -0,0 +1,69 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package httpx adds support for additional types of package net/http to
+// rawconv. Import it for its side effects to register them globally:
+//
+//	import _ "github.com/go-pogo/rawconv/types/httpx"
+//
+// Supported types are:
+//   - http.SameSite, by its case-insensitive name: default, lax, strict or
+//     none
+package httpx
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-pogo/errors"
+	"github.com/go-pogo/rawconv"
+)
+
+func init() { Register(rawconv.GlobalRegistrar()) }
+
+// Register registers the supported types of this package with reg.
+func Register(reg rawconv.Registrar) {
+	sameSite := reflect.TypeOf(http.SameSiteDefaultMode)
+	reg.RegisterUnmarshalFunc(sameSite, unmarshalSameSite)
+	reg.RegisterMarshalFunc(sameSite, marshalSameSite)
+}
+
+const ErrInvalidName errors.Msg = "invalid name"
+
+var sameSiteNames = map[http.SameSite]string{
+	http.SameSiteDefaultMode: "default",
+	http.SameSiteLaxMode:     "lax",
+	http.SameSiteStrictMode:  "strict",
+	http.SameSiteNoneMode:    "none",
+}
+
+func unmarshalSameSite(val rawconv.Value, dest any) error {
+	if val.IsEmpty() {
+		return nil
+	}
+
+	str := strings.TrimSpace(val.String())
+	for mode, name := range sameSiteNames {
+		if strings.EqualFold(str, name) {
+			*dest.(*http.SameSite) = mode
+			return nil
+		}
+	}
+	return errors.Wrap(errors.New(ErrInvalidName), rawconv.ErrParseFailure)
+}
+
+// marshalSameSite marshals the zero value of http.SameSite, which means the
+// attribute is not set, to an empty string. It returns an ErrInvalidName
+// error for values which are unknown.
+func marshalSameSite(v any) (string, error) {
+	x := v.(http.SameSite)
+	if x == 0 {
+		return "", nil
+	}
+	if name, ok := sameSiteNames[x]; ok {
+		return name, nil
+	}
+	return "", errors.New(ErrInvalidName)
+}
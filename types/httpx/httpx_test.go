@@ -0,0 +1,54 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpx
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/go-pogo/rawconv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSameSite(t *testing.T) {
+	tests := map[rawconv.Value]http.SameSite{
+		"default": http.SameSiteDefaultMode,
+		"Lax":     http.SameSiteLaxMode,
+		"STRICT":  http.SameSiteStrictMode,
+		" none ":  http.SameSiteNoneMode,
+	}
+	for input, want := range tests {
+		t.Run(input.String(), func(t *testing.T) {
+			var have http.SameSite
+			assert.NoError(t, rawconv.Unmarshal(input, &have))
+			assert.Equal(t, want, have)
+		})
+	}
+
+	var have http.SameSite
+	assert.ErrorIs(t, rawconv.Unmarshal("relaxed", &have), ErrInvalidName)
+	assert.ErrorIs(t, rawconv.Unmarshal("2", &have), rawconv.ErrParseFailure)
+
+	val, err := rawconv.Marshal(http.Cookie{SameSite: http.SameSiteStrictMode}.SameSite)
+	assert.NoError(t, err)
+	assert.Equal(t, rawconv.Value("strict"), val)
+
+	val, err = rawconv.Marshal(http.SameSite(0))
+	assert.NoError(t, err)
+	assert.Equal(t, rawconv.Value(""), val)
+
+	_, err = rawconv.Marshal(http.SameSite(42))
+	assert.ErrorIs(t, err, ErrInvalidName)
+}
+
+func TestRegister(t *testing.T) {
+	var u rawconv.Unmarshaler
+	var m rawconv.Marshaler
+	Register(rawconv.NewRegistrar(&u, &m))
+
+	assert.NotNil(t, u.Func(reflect.TypeOf(http.SameSiteLaxMode)))
+	assert.NotNil(t, m.Func(reflect.TypeOf(http.SameSiteLaxMode)))
+}
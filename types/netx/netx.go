@@ -0,0 +1,107 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package netx adds support for additional types of package net to
+// rawconv. Import it for its side effects to register them globally:
+//
+//	import _ "github.com/go-pogo/rawconv/types/netx"
+//
+// Supported types are:
+//   - net.TCPAddr
+//   - net.UDPAddr
+//   - net.IPNet, in CIDR notation, e.g. "10.0.0.0/8"
+package netx
+
+import (
+	"net"
+	"net/netip"
+	"reflect"
+	"strings"
+
+	"github.com/go-pogo/errors"
+	"github.com/go-pogo/rawconv"
+)
+
+func init() { Register(rawconv.GlobalRegistrar()) }
+
+// Register registers the supported types of this package with reg.
+func Register(reg rawconv.Registrar) {
+	tcpAddr := reflect.TypeOf(net.TCPAddr{})
+	reg.RegisterUnmarshalFunc(tcpAddr, unmarshalTCPAddr)
+	reg.RegisterMarshalFunc(tcpAddr, marshalTCPAddr)
+
+	udpAddr := reflect.TypeOf(net.UDPAddr{})
+	reg.RegisterUnmarshalFunc(udpAddr, unmarshalUDPAddr)
+	reg.RegisterMarshalFunc(udpAddr, marshalUDPAddr)
+
+	ipNet := reflect.TypeOf(net.IPNet{})
+	reg.RegisterUnmarshalFunc(ipNet, unmarshalIPNet)
+	reg.RegisterMarshalFunc(ipNet, marshalIPNet)
+}
+
+// parseAddrPort parses val as a literal ip address and port, without
+// resolving any host names. Surrounding whitespace is ignored.
+func parseAddrPort(val rawconv.Value) (netip.AddrPort, error) {
+	x, err := netip.ParseAddrPort(strings.TrimSpace(val.String()))
+	return x, errors.Wrap(err, rawconv.ErrParseFailure)
+}
+
+func unmarshalTCPAddr(val rawconv.Value, dest any) error {
+	if val.IsEmpty() {
+		return nil
+	}
+
+	x, err := parseAddrPort(val)
+	if err != nil {
+		return err
+	}
+
+	*dest.(*net.TCPAddr) = *net.TCPAddrFromAddrPort(x)
+	return nil
+}
+
+func marshalTCPAddr(v any) (string, error) {
+	x := v.(net.TCPAddr)
+	return x.String(), nil
+}
+
+func unmarshalUDPAddr(val rawconv.Value, dest any) error {
+	if val.IsEmpty() {
+		return nil
+	}
+
+	x, err := parseAddrPort(val)
+	if err != nil {
+		return err
+	}
+
+	*dest.(*net.UDPAddr) = *net.UDPAddrFromAddrPort(x)
+	return nil
+}
+
+func marshalUDPAddr(v any) (string, error) {
+	x := v.(net.UDPAddr)
+	return x.String(), nil
+}
+
+// unmarshalIPNet parses val using net.ParseCIDR. The resulting net.IPNet is
+// the network of the address, e.g. "10.1.2.3/8" results in 10.0.0.0/8.
+func unmarshalIPNet(val rawconv.Value, dest any) error {
+	if val.IsEmpty() {
+		return nil
+	}
+
+	_, x, err := net.ParseCIDR(strings.TrimSpace(val.String()))
+	if err != nil {
+		return errors.Wrap(err, rawconv.ErrParseFailure)
+	}
+
+	*dest.(*net.IPNet) = *x
+	return nil
+}
+
+func marshalIPNet(v any) (string, error) {
+	x := v.(net.IPNet)
+	return x.String(), nil
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package netx
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/go-pogo/rawconv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTCPAddr(t *testing.T) {
+	var have *net.TCPAddr
+	assert.NoError(t, rawconv.Unmarshal("10.0.0.1:8080", &have))
+	assert.Equal(t, "10.0.0.1:8080", have.String())
+
+	val, err := rawconv.Marshal(have)
+	assert.NoError(t, err)
+	assert.Equal(t, rawconv.Value("10.0.0.1:8080"), val)
+
+	assert.NoError(t, rawconv.Unmarshal(" 10.0.0.2:80 ", &have))
+	assert.Equal(t, "10.0.0.2:80", have.String())
+
+	assert.ErrorIs(t, rawconv.Unmarshal("localhost:80", &have), rawconv.ErrParseFailure)
+}
+
+func TestUDPAddr(t *testing.T) {
+	var have net.UDPAddr
+	assert.NoError(t, rawconv.Unmarshal("[::1]:53", &have))
+	assert.Equal(t, "[::1]:53", have.String())
+
+	val, err := rawconv.Marshal(have)
+	assert.NoError(t, err)
+	assert.Equal(t, rawconv.Value("[::1]:53"), val)
+
+	var list []net.UDPAddr
+	assert.NoError(t, rawconv.Unmarshal("10.0.0.1:53, [::1]:53", &list))
+	if assert.Len(t, list, 2) {
+		assert.Equal(t, "[::1]:53", list[1].String())
+	}
+}
+
+func TestIPNet(t *testing.T) {
+	var have net.IPNet
+	assert.NoError(t, rawconv.Unmarshal("10.1.2.3/8", &have))
+	assert.Equal(t, "10.0.0.0/8", have.String())
+
+	var list []net.IPNet
+	assert.NoError(t, rawconv.Unmarshal("10.0.0.0/8, 192.168.0.0/16,fd00::/8", &list))
+	if assert.Len(t, list, 3) {
+		assert.True(t, list[1].Contains(net.ParseIP("192.168.1.1")))
+		assert.True(t, list[2].Contains(net.ParseIP("fd12::1")))
+	}
+
+	val, err := rawconv.Marshal(list)
+	assert.NoError(t, err)
+	assert.Equal(t, rawconv.Value("10.0.0.0/8,192.168.0.0/16,fd00::/8"), val)
+
+	assert.ErrorIs(t, rawconv.Unmarshal("10.0.0.1", &have), rawconv.ErrParseFailure)
+}
+
+func TestRegister(t *testing.T) {
+	var u rawconv.Unmarshaler
+	var m rawconv.Marshaler
+	Register(rawconv.NewRegistrar(&u, &m))
+
+	assert.NotNil(t, u.Func(reflect.TypeOf(net.TCPAddr{})))
+	assert.NotNil(t, m.Func(reflect.TypeOf(net.UDPAddr{})))
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package timex adds support for additional types of package time to
+// rawconv. Import it for its side effects to register them globally:
+//
+//	import _ "github.com/go-pogo/rawconv/types/timex"
+//
+// Supported types are:
+//   - time.Month, by its (abbreviated) english name or number
+//   - time.Weekday, by its (abbreviated) english name or number
+package timex
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-pogo/errors"
+	"github.com/go-pogo/rawconv"
+)
+
+func init() { Register(rawconv.GlobalRegistrar()) }
+
+// Register registers the supported types of this package with reg.
+func Register(reg rawconv.Registrar) {
+	month := reflect.TypeOf(time.January)
+	reg.RegisterUnmarshalFunc(month, unmarshalMonth)
+	reg.RegisterMarshalFunc(month, marshalStringer)
+
+	weekday := reflect.TypeOf(time.Sunday)
+	reg.RegisterUnmarshalFunc(weekday, unmarshalWeekday)
+	reg.RegisterMarshalFunc(weekday, marshalStringer)
+}
+
+const ErrInvalidName errors.Msg = "invalid name"
+
+// parseName returns the number of the name in val, which is either one of
+// names (or its first three characters), or a number within the range of
+// min and len(names)+min.
+func parseName(val rawconv.Value, names []string, min int) (int, error) {
+	str := strings.TrimSpace(val.String())
+	if i, err := strconv.Atoi(str); err == nil {
+		if i < min || i >= len(names)+min {
+			return 0, errors.Wrap(strconv.ErrRange, rawconv.ErrParseFailure)
+		}
+		return i, nil
+	}
+
+	for i, name := range names {
+		if strings.EqualFold(str, name) || strings.EqualFold(str, name[:3]) {
+			return i + min, nil
+		}
+	}
+	return 0, errors.Wrap(errors.New(ErrInvalidName), rawconv.ErrParseFailure)
+}
+
+var months = func() []string {
+	res := make([]string, 12)
+	for i := range res {
+		res[i] = time.Month(i + 1).String()
+	}
+	return res
+}()
+
+func unmarshalMonth(val rawconv.Value, dest any) error {
+	if val.IsEmpty() {
+		return nil
+	}
+
+	x, err := parseName(val, months, 1)
+	if err != nil {
+		return err
+	}
+
+	*dest.(*time.Month) = time.Month(x)
+	return nil
+}
+
+var weekdays = func() []string {
+	res := make([]string, 7)
+	for i := range res {
+		res[i] = time.Weekday(i).String()
+	}
+	return res
+}()
+
+func unmarshalWeekday(val rawconv.Value, dest any) error {
+	if val.IsEmpty() {
+		return nil
+	}
+
+	x, err := parseName(val, weekdays, 0)
+	if err != nil {
+		return err
+	}
+
+	*dest.(*time.Weekday) = time.Weekday(x)
+	return nil
+}
+
+func marshalStringer(v any) (string, error) {
+	return v.(interface{ String() string }).String(), nil
+}
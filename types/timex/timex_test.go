@@ -0,0 +1,60 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package timex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-pogo/rawconv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMonth(t *testing.T) {
+	tests := map[rawconv.Value]time.Month{
+		"January":  time.January,
+		"feb":      time.February,
+		"DECEMBER": time.December,
+		"3":        time.March,
+	}
+	for input, want := range tests {
+		t.Run(input.String(), func(t *testing.T) {
+			var have time.Month
+			assert.NoError(t, rawconv.Unmarshal(input, &have))
+			assert.Equal(t, want, have)
+		})
+	}
+
+	var have time.Month
+	assert.ErrorIs(t, rawconv.Unmarshal("13", &have), rawconv.ErrParseFailure)
+	assert.ErrorIs(t, rawconv.Unmarshal("0", &have), rawconv.ErrParseFailure)
+	assert.ErrorIs(t, rawconv.Unmarshal("jan.", &have), ErrInvalidName)
+
+	val, err := rawconv.Marshal(time.August)
+	assert.NoError(t, err)
+	assert.Equal(t, rawconv.Value("August"), val)
+}
+
+func TestWeekday(t *testing.T) {
+	tests := map[rawconv.Value]time.Weekday{
+		"Sunday": time.Sunday,
+		"sat":    time.Saturday,
+		"1":      time.Monday,
+	}
+	for input, want := range tests {
+		t.Run(input.String(), func(t *testing.T) {
+			var have time.Weekday
+			assert.NoError(t, rawconv.Unmarshal(input, &have))
+			assert.Equal(t, want, have)
+		})
+	}
+
+	var have time.Weekday
+	assert.ErrorIs(t, rawconv.Unmarshal("7", &have), rawconv.ErrParseFailure)
+
+	val, err := rawconv.Marshal([]time.Weekday{time.Monday, time.Friday})
+	assert.NoError(t, err)
+	assert.Equal(t, rawconv.Value("Monday,Friday"), val)
+}
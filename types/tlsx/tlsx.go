@@ -0,0 +1,134 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package tlsx adds support for additional types of package crypto/tls to
+// rawconv. Import it for its side effects to register them globally:
+//
+//	import _ "github.com/go-pogo/rawconv/types/tlsx"
+//
+// Supported types are:
+//   - tls.ClientAuthType, by its case-insensitive name, e.g.
+//     "RequireAndVerifyClientCert", or number
+//   - Version, a TLS version such as "1.2" or "TLS1.3"
+package tlsx
+
+import (
+	"crypto/tls"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-pogo/errors"
+	"github.com/go-pogo/rawconv"
+)
+
+func init() { Register(rawconv.GlobalRegistrar()) }
+
+// Register registers the supported types of this package with reg.
+func Register(reg rawconv.Registrar) {
+	clientAuth := reflect.TypeOf(tls.NoClientCert)
+	reg.RegisterUnmarshalFunc(clientAuth, unmarshalClientAuth)
+	reg.RegisterMarshalFunc(clientAuth, marshalStringer)
+
+	version := reflect.TypeOf(Version(0))
+	reg.RegisterUnmarshalFunc(version, unmarshalVersion)
+	reg.RegisterMarshalFunc(version, marshalStringer)
+}
+
+const (
+	ErrInvalidName    errors.Msg = "invalid name"
+	ErrInvalidVersion errors.Msg = "invalid tls version"
+)
+
+var clientAuthTypes = []tls.ClientAuthType{
+	tls.NoClientCert,
+	tls.RequestClientCert,
+	tls.RequireAnyClientCert,
+	tls.VerifyClientCertIfGiven,
+	tls.RequireAndVerifyClientCert,
+}
+
+func unmarshalClientAuth(val rawconv.Value, dest any) error {
+	if val.IsEmpty() {
+		return nil
+	}
+
+	str := strings.TrimSpace(val.String())
+	if i, err := strconv.Atoi(str); err == nil {
+		if i < 0 || i >= len(clientAuthTypes) {
+			return errors.Wrap(strconv.ErrRange, rawconv.ErrParseFailure)
+		}
+		*dest.(*tls.ClientAuthType) = clientAuthTypes[i]
+		return nil
+	}
+
+	for _, typ := range clientAuthTypes {
+		if strings.EqualFold(str, typ.String()) {
+			*dest.(*tls.ClientAuthType) = typ
+			return nil
+		}
+	}
+	return errors.Wrap(errors.New(ErrInvalidName), rawconv.ErrParseFailure)
+}
+
+// Version is a TLS version, e.g. tls.VersionTLS12, which is unmarshaled from
+// and marshaled to a human readable form, e.g. "1.2". Use it for the
+// MinVersion and MaxVersion fields of a tls.Config:
+//
+//	conf := &tls.Config{MinVersion: uint16(version)}
+type Version uint16
+
+var versions = []struct {
+	name    string
+	version Version
+}{
+	{"1.0", tls.VersionTLS10},
+	{"1.1", tls.VersionTLS11},
+	{"1.2", tls.VersionTLS12},
+	{"1.3", tls.VersionTLS13},
+}
+
+// ParseVersion parses a TLS version of the form "1.2", optionally prefixed
+// by "TLS", "TLS " or "TLSv", which is matched case-insensitive.
+func ParseVersion(str string) (Version, error) {
+	str = strings.TrimSpace(str)
+	if len(str) > 3 && strings.EqualFold(str[:3], "tls") {
+		str = strings.TrimLeft(str[3:], " vV")
+	}
+	for _, v := range versions {
+		if str == v.name {
+			return v.version, nil
+		}
+	}
+	return 0, errors.Wrap(errors.New(ErrInvalidVersion), rawconv.ErrParseFailure)
+}
+
+// String returns the version in its human readable form, e.g. "1.2", or its
+// hexadecimal value when it is unknown.
+func (v Version) String() string {
+	for _, x := range versions {
+		if v == x.version {
+			return x.name
+		}
+	}
+	return "0x" + strconv.FormatUint(uint64(v), 16)
+}
+
+func unmarshalVersion(val rawconv.Value, dest any) error {
+	if val.IsEmpty() {
+		return nil
+	}
+
+	x, err := ParseVersion(val.String())
+	if err != nil {
+		return err
+	}
+
+	*dest.(*Version) = x
+	return nil
+}
+
+func marshalStringer(v any) (string, error) {
+	return v.(interface{ String() string }).String(), nil
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tlsx
+
+import (
+	"crypto/tls"
+	"reflect"
+	"testing"
+
+	"github.com/go-pogo/rawconv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientAuthType(t *testing.T) {
+	tests := map[rawconv.Value]tls.ClientAuthType{
+		"NoClientCert":               tls.NoClientCert,
+		"requireandverifyclientcert": tls.RequireAndVerifyClientCert,
+		"3":                          tls.VerifyClientCertIfGiven,
+	}
+	for input, want := range tests {
+		t.Run(input.String(), func(t *testing.T) {
+			var have tls.ClientAuthType
+			assert.NoError(t, rawconv.Unmarshal(input, &have))
+			assert.Equal(t, want, have)
+		})
+	}
+
+	var have tls.ClientAuthType
+	assert.ErrorIs(t, rawconv.Unmarshal("5", &have), rawconv.ErrParseFailure)
+	assert.ErrorIs(t, rawconv.Unmarshal("always", &have), ErrInvalidName)
+
+	val, err := rawconv.Marshal(tls.RequireAnyClientCert)
+	assert.NoError(t, err)
+	assert.Equal(t, rawconv.Value("RequireAnyClientCert"), val)
+}
+
+func TestVersion(t *testing.T) {
+	tests := map[rawconv.Value]Version{
+		"1.0":     tls.VersionTLS10,
+		"1.2":     tls.VersionTLS12,
+		"TLS1.3":  tls.VersionTLS13,
+		"tls 1.1": tls.VersionTLS11,
+		"TLSv1.2": tls.VersionTLS12,
+	}
+	for input, want := range tests {
+		t.Run(input.String(), func(t *testing.T) {
+			var have Version
+			assert.NoError(t, rawconv.Unmarshal(input, &have))
+			assert.Equal(t, want, have)
+		})
+	}
+
+	var have Version
+	assert.ErrorIs(t, rawconv.Unmarshal("1.4", &have), ErrInvalidVersion)
+	assert.ErrorIs(t, rawconv.Unmarshal("771", &have), rawconv.ErrParseFailure)
+
+	val, err := rawconv.Marshal(Version(tls.VersionTLS13))
+	assert.NoError(t, err)
+	assert.Equal(t, rawconv.Value("1.3"), val)
+	assert.Equal(t, "0x305", Version(0x305).String())
+}
+
+func TestRegister(t *testing.T) {
+	var u rawconv.Unmarshaler
+	var m rawconv.Marshaler
+	Register(rawconv.NewRegistrar(&u, &m))
+
+	assert.NotNil(t, u.Func(reflect.TypeOf(tls.NoClientCert)))
+	assert.NotNil(t, m.Func(reflect.TypeOf(Version(0))))
+}
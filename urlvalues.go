@@ -0,0 +1,204 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"sort"
+
+	"github.com/go-pogo/errors"
+)
+
+const ErrStructOrMapExpected errors.Msg = "expected a struct or map"
+
+// UnmarshalURLValues unmarshals values, e.g. the query parameters of a
+// http.Request, to the struct or map v points to, using the Options set with
+// SetGlobalOptions.
+//
+// The fields of a struct are determined the same way as with UnmarshalStruct.
+// A field, or map value, of a slice type which has no registered
+// UnmarshalFunc receives an item for each value of its parameter, e.g.
+// "?id=1&id=2" unmarshals to []int{1, 2}. Any other field or map value is
+// unmarshaled from the first value of its parameter. Fields without a
+// matching parameter are left untouched.
+func UnmarshalURLValues(values url.Values, v any) error {
+	rv, err := ptrDest(v)
+	if err != nil {
+		return err
+	}
+
+	u := Unmarshaler{Options: GlobalOptions()}
+	return u.unmarshalURLValues(values, rv)
+}
+
+// MarshalURLValues marshals the fields of struct v, or the entries of map v,
+// to url.Values using the Options set with SetGlobalOptions. An array or
+// slice which has no registered MarshalFunc results in a value for each of
+// its items. See UnmarshalURLValues for additional details.
+func MarshalURLValues(v any) (url.Values, error) {
+	m := Marshaler{Options: GlobalOptions()}
+	return m.MarshalURLValues(reflect.ValueOf(v))
+}
+
+// UnmarshalURLValues unmarshals values to the fields of struct v, or the
+// entries of map v. See UnmarshalURLValues for additional details.
+func (u *Unmarshaler) UnmarshalURLValues(values url.Values, v reflect.Value) error {
+	if err := validDest(v); err != nil {
+		return err
+	}
+	return u.unmarshalURLValues(values, v)
+}
+
+func (u *Unmarshaler) unmarshalURLValues(values url.Values, v reflect.Value) error {
+	v, err := structDest(v)
+	switch {
+	case v.Kind() == reflect.Map:
+		return u.unmarshalURLValuesMap(values, v)
+	case err == nil:
+		break
+	case v.Kind() == reflect.Ptr:
+		return err
+	default:
+		return errors.New(ErrStructOrMapExpected)
+	}
+
+	for _, field := range structFields(v.Type()) {
+		params, ok := values[field.name]
+		if !ok || len(params) == 0 {
+			continue
+		}
+
+		fv, err := fieldByIndex(v, field.index, true)
+		if err == nil {
+			err = u.unmarshalParams(params, fv, field.name)
+		}
+		if err != nil {
+			return fieldErr(field.name, err)
+		}
+	}
+	return nil
+}
+
+func (u *Unmarshaler) unmarshalURLValuesMap(values url.Values, v reflect.Value) error {
+	if v.IsNil() {
+		v.Set(reflect.MakeMapWithSize(v.Type(), len(values)))
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	keyTyp, valTyp := v.Type().Key(), v.Type().Elem()
+	for _, k := range keys {
+		key := reflect.New(keyTyp).Elem()
+		if err := u.unmarshal(context.Background(), Value(k), key, k, 0); err != nil {
+			return &UnmarshalError{Key: k, Err: err}
+		}
+
+		val := reflect.New(valTyp).Elem()
+		if err := u.unmarshalParams(values[k], val, k); err != nil {
+			return &UnmarshalError{Key: k, Err: err}
+		}
+		v.SetMapIndex(key, val)
+	}
+	return nil
+}
+
+// unmarshalParams unmarshals the values of a single parameter to dest.
+func (u *Unmarshaler) unmarshalParams(params []string, dest reflect.Value, path string) error {
+	ctx := context.Background()
+	if dest.Kind() != reflect.Slice || !u.collection(dest.Type()) {
+		var val Value
+		if len(params) != 0 {
+			val = Value(params[0])
+		}
+		return u.unmarshal(ctx, val, dest, path, 0)
+	}
+
+	res := reflect.MakeSlice(dest.Type(), len(params), len(params))
+	for i, param := range params {
+		if err := u.unmarshal(ctx, Value(param), res.Index(i), u.indexPath(path, i), 0); err != nil {
+			return &UnmarshalError{Index: i, Err: err}
+		}
+	}
+	dest.Set(res)
+	return nil
+}
+
+// MarshalURLValues marshals the fields of struct v, or the entries of map v,
+// to url.Values. See MarshalURLValues for additional details.
+func (m *Marshaler) MarshalURLValues(v reflect.Value) (url.Values, error) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, errors.New(ErrStructOrMapExpected)
+		}
+		v = v.Elem()
+	}
+
+	ctx := context.Background()
+	switch v.Kind() {
+	case reflect.Struct:
+		fields := structFields(v.Type())
+		res := make(url.Values, len(fields))
+		for _, field := range fields {
+			fv, err := fieldByIndex(v, field.index, false)
+			if err != nil {
+				// embedded struct pointer is nil
+				continue
+			}
+			if err = m.marshalParams(ctx, res, field.name, fv); err != nil {
+				return nil, fieldErr(field.name, err)
+			}
+		}
+		return res, nil
+
+	case reflect.Map:
+		keys, err := m.mapKeys(ctx, v, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		res := make(url.Values, len(keys))
+		for _, key := range keys {
+			k, err := m.marshal(ctx, key, 0)
+			if err != nil {
+				return nil, err
+			}
+			if err = m.marshalParams(ctx, res, k, v.MapIndex(key)); err != nil {
+				return nil, errors.WithStack(&UnmarshalError{Key: k, Err: err})
+			}
+		}
+		return res, nil
+
+	default:
+		return nil, errors.New(ErrStructOrMapExpected)
+	}
+}
+
+// marshalParams adds the marshaled value(s) of val to res, as parameter name.
+func (m *Marshaler) marshalParams(ctx context.Context, res url.Values, name string, val reflect.Value) error {
+	coll, ok := m.collection(val)
+	if !ok || coll.Kind() == reflect.Map {
+		str, err := m.marshal(ctx, val, 0)
+		if err != nil {
+			return err
+		}
+		res.Set(name, str)
+		return nil
+	}
+
+	for i := 0; i < coll.Len(); i++ {
+		str, err := m.marshal(ctx, coll.Index(i), 0)
+		if err != nil {
+			return errors.WithStack(&UnmarshalError{Index: i, Err: err})
+		}
+		res.Add(name, str)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type urlValuesFixture struct {
+	Query   string        `raw:"q"`
+	IDs     []int         `raw:"id"`
+	Tags    [2]string     `raw:"tag"`
+	Page    *int          `raw:"page"`
+	Timeout time.Duration `raw:"timeout"`
+	Ignored string        `raw:"-"`
+}
+
+func TestUnmarshalURLValues(t *testing.T) {
+	t.Run("struct", func(t *testing.T) {
+		values, err := url.ParseQuery("q=foo&q=bar&id=1&id=2&id=3&tag=a,b&page=2&timeout=5s&Ignored=x&other=y")
+		assert.NoError(t, err)
+
+		var have urlValuesFixture
+		assert.NoError(t, UnmarshalURLValues(values, &have))
+		assert.Equal(t, urlValuesFixture{
+			Query:   "foo",
+			IDs:     []int{1, 2, 3},
+			Tags:    [2]string{"a", "b"},
+			Page:    ptr(2),
+			Timeout: 5 * time.Second,
+		}, have)
+	})
+	t.Run("map", func(t *testing.T) {
+		have := map[string][]uint{"c": {3}}
+		assert.NoError(t, UnmarshalURLValues(url.Values{
+			"a": {"1", "2"},
+			"b": {"3"},
+		}, &have))
+		assert.Equal(t, map[string][]uint{
+			"a": {1, 2},
+			"b": {3},
+			"c": {3},
+		}, have)
+	})
+	t.Run("nil map", func(t *testing.T) {
+		var have map[string]int
+		assert.NoError(t, UnmarshalURLValues(url.Values{"a": {"1", "2"}}, &have))
+		assert.Equal(t, map[string]int{"a": 1}, have)
+	})
+	t.Run("item error", func(t *testing.T) {
+		var have urlValuesFixture
+		err := UnmarshalURLValues(url.Values{"id": {"1", "x"}}, &have)
+		assert.ErrorIs(t, err, ErrParseFailure)
+
+		var unmarshalErr *UnmarshalError
+		assert.ErrorAs(t, err, &unmarshalErr)
+		assert.Equal(t, 1, unmarshalErr.Index)
+	})
+	t.Run("invalid dest", func(t *testing.T) {
+		var have []string
+		assert.ErrorIs(t, UnmarshalURLValues(url.Values{}, &have), ErrStructOrMapExpected)
+		assert.ErrorIs(t, UnmarshalURLValues(url.Values{}, nil), ErrNilDestination)
+	})
+}
+
+func TestMarshalURLValues(t *testing.T) {
+	t.Run("struct", func(t *testing.T) {
+		have, err := MarshalURLValues(urlValuesFixture{
+			Query:   "foo bar",
+			IDs:     []int{1, 2},
+			Tags:    [2]string{"a", "b"},
+			Timeout: time.Minute,
+			Ignored: "x",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "id=1&id=2&page=&q=foo+bar&tag=a&tag=b&timeout=1m0s", have.Encode())
+	})
+	t.Run("map", func(t *testing.T) {
+		have, err := MarshalURLValues(map[int][]bool{2: {true}, 1: {false, true}})
+		assert.NoError(t, err)
+		assert.Equal(t, url.Values{
+			"1": {"false", "true"},
+			"2": {"true"},
+		}, have)
+	})
+	t.Run("round trip", func(t *testing.T) {
+		want := urlValuesFixture{Query: "q", IDs: []int{3, 4}, Page: ptr(1)}
+		values, err := MarshalURLValues(&want)
+		assert.NoError(t, err)
+
+		var have urlValuesFixture
+		assert.NoError(t, UnmarshalURLValues(values, &have))
+		assert.Equal(t, want, have)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := MarshalURLValues("foo")
+		assert.ErrorIs(t, err, ErrStructOrMapExpected)
+	})
+	t.Run("error", func(t *testing.T) {
+		type failing int
+		var m Marshaler
+		m.Register(reflect.TypeOf(failing(0)), func(any) (string, error) {
+			return "", ErrValidationFailure
+		})
+
+		_, err := m.MarshalURLValues(reflect.ValueOf(map[string][]failing{"x": {1}}))
+		assert.ErrorIs(t, err, ErrValidationFailure)
+
+		var keyErr *UnmarshalError
+		if assert.ErrorAs(t, err, &keyErr) {
+			assert.Equal(t, "x", keyErr.Key)
+
+			var indexErr *UnmarshalError
+			if assert.ErrorAs(t, keyErr.Err, &indexErr) {
+				assert.Equal(t, 0, indexErr.Index)
+				assert.Empty(t, indexErr.Key)
+			}
+		}
+	})
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"math/big"
+	"strconv"
+
+	"github.com/go-pogo/errors"
+)
+
+// BigInt tries to parse Value as a *big.Int using big.Int.SetString. Base
+// prefixes like "0x", "0o" and "0b" are supported.
+func (v Value) BigInt() (*big.Int, error) {
+	x, ok := new(big.Int).SetString(v.String(), 0)
+	if !ok {
+		return nil, errors.Wrap(strconv.ErrSyntax, ErrParseFailure)
+	}
+	return x, nil
+}
+
+// MustBigInt is like BigInt but panics if Value cannot be parsed.
+func (v Value) MustBigInt() *big.Int { return must(v.BigInt()) }
+
+// BigIntVar sets the value p points to using BigInt.
+func (v Value) BigIntVar(p *big.Int) error {
+	x, err := v.BigInt()
+	if err != nil {
+		return err
+	}
+	p.Set(x)
+	return nil
+}
+
+// BigFloat tries to parse Value as a *big.Float using big.ParseFloat. The
+// precision of the result is large enough to hold all digits of Value, with a
+// minimum of 64 bits.
+func (v Value) BigFloat() (*big.Float, error) {
+	x, _, err := big.ParseFloat(v.String(), 0, bigFloatPrec(v), big.ToNearestEven)
+	if err != nil {
+		return nil, errors.Wrap(err, ErrParseFailure)
+	}
+	return x, nil
+}
+
+// bigFloatPrec returns the precision needed to represent all (decimal) digits
+// of Value, which is about 3.33 bits per digit.
+func bigFloatPrec(v Value) uint {
+	prec := uint(len(v)) * 10 / 3
+	if prec < 64 {
+		return 64
+	}
+	return prec
+}
+
+// MustBigFloat is like BigFloat but panics if Value cannot be parsed.
+func (v Value) MustBigFloat() *big.Float { return must(v.BigFloat()) }
+
+// BigFloatVar sets the value p points to using BigFloat.
+func (v Value) BigFloatVar(p *big.Float) error {
+	x, err := v.BigFloat()
+	if err != nil {
+		return err
+	}
+	p.SetPrec(x.Prec()).Set(x)
+	return nil
+}
+
+// BigRat tries to parse Value as a *big.Rat using big.Rat.SetString. Both
+// fractions, e.g. "3/4", and floating-point numbers, e.g. "0.75", are
+// supported.
+func (v Value) BigRat() (*big.Rat, error) {
+	x, ok := new(big.Rat).SetString(v.String())
+	if !ok {
+		return nil, errors.Wrap(strconv.ErrSyntax, ErrParseFailure)
+	}
+	return x, nil
+}
+
+// MustBigRat is like BigRat but panics if Value cannot be parsed.
+func (v Value) MustBigRat() *big.Rat { return must(v.BigRat()) }
+
+// BigRatVar sets the value p points to using BigRat.
+func (v Value) BigRatVar(p *big.Rat) error {
+	x, err := v.BigRat()
+	if err != nil {
+		return err
+	}
+	p.Set(x)
+	return nil
+}
+
+func unmarshalBigInt(val Value, dest any) error {
+	if val.IsEmpty() {
+		return nil
+	}
+
+	return val.BigIntVar(dest.(*big.Int))
+}
+
+func marshalBigInt(v any) (string, error) {
+	x := v.(big.Int)
+	return x.String(), nil
+}
+
+func unmarshalBigFloat(val Value, dest any) error {
+	if val.IsEmpty() {
+		return nil
+	}
+
+	return val.BigFloatVar(dest.(*big.Float))
+}
+
+func marshalBigFloat(v any) (string, error) {
+	x := v.(big.Float)
+	return x.Text('g', -1), nil
+}
+
+func unmarshalBigRat(val Value, dest any) error {
+	if val.IsEmpty() {
+		return nil
+	}
+
+	return val.BigRatVar(dest.(*big.Rat))
+}
+
+func marshalBigRat(v any) (string, error) {
+	x := v.(big.Rat)
+	return x.RatString(), nil
+}
@@ -6,8 +6,7 @@ package rawconv
 
 import (
 	"strconv"
-
-	"github.com/go-pogo/errors"
+	"strings"
 )
 
 // ValueFromBool encodes v to a Value using strconv.FormatBool.
@@ -20,10 +19,51 @@ func ValueFromBool(v bool) Value {
 // Any other value returns an error.
 func (v Value) Bool() (bool, error) {
 	x, err := strconv.ParseBool(string(v))
-	if kind := errKind(err); kind != nil {
-		return x, errors.Wrap(err, kind)
+	return x, parseErr(err)
+}
+
+// parseBool parses Value as a bool, accepting the case-insensitive
+// Options.BoolTrueTokens and Options.BoolFalseTokens in addition to the
+// values accepted by Value.Bool.
+func (o Options) parseBool(v Value) (bool, error) {
+	if len(o.BoolTrueTokens) != 0 || len(o.BoolFalseTokens) != 0 {
+		str := strings.TrimSpace(v.String())
+		for _, tok := range o.BoolTrueTokens {
+			if strings.EqualFold(str, tok) {
+				return true, nil
+			}
+		}
+		for _, tok := range o.BoolFalseTokens {
+			if strings.EqualFold(str, tok) {
+				return false, nil
+			}
+		}
+	}
+	return v.Bool()
+}
+
+// formatBool formats b using the first token of Options.BoolTrueTokens or
+// Options.BoolFalseTokens, or strconv.FormatBool when there is none.
+func (o Options) formatBool(b bool) string {
+	if b && len(o.BoolTrueTokens) != 0 {
+		return o.BoolTrueTokens[0]
+	}
+	if !b && len(o.BoolFalseTokens) != 0 {
+		return o.BoolFalseTokens[0]
+	}
+	return strconv.FormatBool(b)
+}
+
+// MustBool is like Bool but panics if Value cannot be parsed.
+func (v Value) MustBool() bool { return must(v.Bool()) }
+
+// BoolOr is like Bool but returns def if Value is empty or cannot be
+// parsed.
+func (v Value) BoolOr(def bool) bool {
+	if x, err := v.Bool(); err == nil {
+		return x
 	}
-	return x, errors.WithStack(err)
+	return def
 }
 
 // BoolVar sets the value p points to using Bool.
@@ -0,0 +1,155 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/go-pogo/errors"
+)
+
+// ByteSize is a size in bytes, which is parsed from and formatted to a human
+// readable form, e.g. "512", "10KB", "4MiB" or "1.5GB".
+type ByteSize uint64
+
+const (
+	Byte ByteSize = 1
+
+	KB ByteSize = 1000 * Byte
+	MB          = 1000 * KB
+	GB          = 1000 * MB
+	TB          = 1000 * GB
+	PB          = 1000 * TB
+	EB          = 1000 * PB
+)
+
+const (
+	KiB ByteSize = 1 << (10 * (iota + 1))
+	MiB
+	GiB
+	TiB
+	PiB
+	EiB
+)
+
+var byteUnits = []struct {
+	name string
+	size ByteSize
+}{
+	{"EiB", EiB}, {"EB", EB},
+	{"PiB", PiB}, {"PB", PB},
+	{"TiB", TiB}, {"TB", TB},
+	{"GiB", GiB}, {"GB", GB},
+	{"MiB", MiB}, {"MB", MB},
+	{"KiB", KiB}, {"KB", KB},
+	{"B", Byte},
+}
+
+// byteUnit returns the size of unit, which is matched case-insensitive. An
+// empty unit is a size in bytes, a unit without trailing "B" is an SI unit,
+// e.g. "k" equals "KB".
+func byteUnit(unit string) (ByteSize, bool) {
+	if unit == "" {
+		return Byte, true
+	}
+	if !strings.HasSuffix(unit, "b") && !strings.HasSuffix(unit, "B") {
+		unit += "B"
+	}
+	for _, u := range byteUnits {
+		if strings.EqualFold(u.name, unit) {
+			return u.size, true
+		}
+	}
+	return 0, false
+}
+
+// String returns the size in the largest unit in which it can be expressed
+// without loss of precision, e.g. "4MiB" or "1500MB".
+func (b ByteSize) String() string {
+	if b == 0 {
+		return "0B"
+	}
+
+	unit := byteUnits[len(byteUnits)-1]
+	for _, u := range byteUnits {
+		if b%u.size == 0 && b/u.size < b/unit.size {
+			unit = u
+		}
+	}
+	return strconv.FormatUint(uint64(b/unit.size), 10) + unit.name
+}
+
+// ByteSize tries to parse Value as a ByteSize. It accepts a (decimal) number
+// followed by an optional unit, e.g. "512", "10KB", "4MiB" or "1.5GB". Units
+// are case-insensitive, both SI (KB, MB, GB, TB, PB, EB) and binary (KiB,
+// MiB, GiB, TiB, PiB, EiB) units are supported.
+func (v Value) ByteSize() (ByteSize, error) {
+	str := strings.TrimSpace(v.String())
+	i := strings.IndexFunc(str, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		i = len(str)
+	}
+
+	num, unit := str[:i], strings.TrimSpace(str[i:])
+	size, ok := byteUnit(unit)
+	if num == "" || !ok {
+		return 0, errors.Wrap(strconv.ErrSyntax, ErrParseFailure)
+	}
+
+	if !strings.Contains(num, ".") {
+		x, err := strconv.ParseUint(num, 10, 64)
+		if err != nil {
+			return 0, parseErr(err)
+		}
+		if x > math.MaxUint64/uint64(size) {
+			return 0, errors.Wrap(strconv.ErrRange, ErrValidationFailure)
+		}
+		return ByteSize(x) * size, nil
+	}
+
+	x, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, parseErr(err)
+	}
+	x = math.Round(x * float64(size))
+	if x >= math.MaxUint64 {
+		return 0, errors.Wrap(strconv.ErrRange, ErrValidationFailure)
+	}
+	return ByteSize(x), nil
+}
+
+// MustByteSize is like ByteSize but panics if Value cannot be parsed.
+func (v Value) MustByteSize() ByteSize { return must(v.ByteSize()) }
+
+// ByteSizeOr is like ByteSize but returns def if Value is empty or cannot be
+// parsed.
+func (v Value) ByteSizeOr(def ByteSize) ByteSize {
+	if x, err := v.ByteSize(); err == nil {
+		return x
+	}
+	return def
+}
+
+// ByteSizeVar sets the value p points to using ByteSize.
+func (v Value) ByteSizeVar(p *ByteSize) (err error) {
+	*p, err = v.ByteSize()
+	return
+}
+
+func unmarshalByteSize(val Value, dest any) error {
+	if val.IsEmpty() {
+		return nil
+	}
+
+	return val.ByteSizeVar(dest.(*ByteSize))
+}
+
+func marshalByteSize(v any) (string, error) {
+	return v.(ByteSize).String(), nil
+}
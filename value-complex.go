@@ -4,11 +4,7 @@
 
 package rawconv
 
-import (
-	"strconv"
-
-	"github.com/go-pogo/errors"
-)
+import "strconv"
 
 // ValueFromComplex64 encodes v to a Value using strconv.FormatComplex.
 func ValueFromComplex64(v complex64) Value {
@@ -26,6 +22,18 @@ func (v Value) Complex64() (complex64, error) {
 	return complex64(x), err
 }
 
+// MustComplex64 is like Complex64 but panics if Value cannot be parsed.
+func (v Value) MustComplex64() complex64 { return must(v.Complex64()) }
+
+// Complex64Or is like Complex64 but returns def if Value is empty or cannot be
+// parsed.
+func (v Value) Complex64Or(def complex64) complex64 {
+	if x, err := v.Complex64(); err == nil {
+		return x
+	}
+	return def
+}
+
 // Complex64Var sets the value p points to using Complex64.
 func (v Value) Complex64Var(p *complex64) (err error) {
 	*p, err = v.Complex64()
@@ -37,6 +45,18 @@ func (v Value) Complex128() (complex128, error) {
 	return complexSize(v, 128)
 }
 
+// MustComplex128 is like Complex128 but panics if Value cannot be parsed.
+func (v Value) MustComplex128() complex128 { return must(v.Complex128()) }
+
+// Complex128Or is like Complex128 but returns def if Value is empty or cannot be
+// parsed.
+func (v Value) Complex128Or(def complex128) complex128 {
+	if x, err := v.Complex128(); err == nil {
+		return x
+	}
+	return def
+}
+
 // Complex128Var sets the value p points to using Complex128.
 func (v Value) Complex128Var(p *complex128) (err error) {
 	*p, err = v.Complex128()
@@ -45,8 +65,5 @@ func (v Value) Complex128Var(p *complex128) (err error) {
 
 func complexSize(v Value, bitSize int) (complex128, error) {
 	x, err := strconv.ParseComplex(v.String(), bitSize)
-	if kind := errKind(err); kind != nil {
-		return x, errors.Wrap(err, kind)
-	}
-	return x, errors.WithStack(err)
+	return x, parseErr(err)
 }
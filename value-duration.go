@@ -5,23 +5,175 @@
 package rawconv
 
 import (
+	"math"
+	"strconv"
 	"time"
 
 	"github.com/go-pogo/errors"
 )
 
+const (
+	day  = 24 * time.Hour
+	week = 7 * day
+)
+
 // Duration tries to parse Value as a time.Duration using time.ParseDuration.
 func (v Value) Duration() (time.Duration, error) {
 	x, err := time.ParseDuration(v.String())
 	return x, errors.Wrap(err, ErrParseFailure)
 }
 
+// MustDuration is like Duration but panics if Value cannot be parsed.
+func (v Value) MustDuration() time.Duration { return must(v.Duration()) }
+
+// DurationOr is like Duration but returns def if Value is empty or cannot be
+// parsed.
+func (v Value) DurationOr(def time.Duration) time.Duration {
+	if x, err := v.Duration(); err == nil {
+		return x
+	}
+	return def
+}
+
 // DurationVar sets the value p points to using Duration.
 func (v Value) DurationVar(p *time.Duration) (err error) {
 	*p, err = v.Duration()
 	return
 }
 
+// ExtendedDuration tries to parse Value as a time.Duration. In addition to the
+// units supported by time.ParseDuration, it accepts the units "d" for days of
+// 24 hours and "w" for weeks of 7 days, e.g. "2d", "1w" or "1w2d3h30m".
+func (v Value) ExtendedDuration() (time.Duration, error) {
+	str := v.String()
+	var neg bool
+	if str != "" && (str[0] == '-' || str[0] == '+') {
+		neg = str[0] == '-'
+		str = str[1:]
+	}
+	if str == "" {
+		return 0, errors.Wrap(strconv.ErrSyntax, ErrParseFailure)
+	}
+
+	// days and weeks are summed separately, all other units are passed on to
+	// time.ParseDuration
+	var days float64
+	rest := make([]byte, 0, len(str))
+	for str != "" {
+		i := 0
+		for i < len(str) && (str[i] == '.' || ('0' <= str[i] && str[i] <= '9')) {
+			i++
+		}
+		j := i
+		for j < len(str) && str[j] != '.' && (str[j] < '0' || str[j] > '9') {
+			j++
+		}
+
+		num, unit := str[:i], str[i:j]
+		str = str[j:]
+		if unit != "d" && unit != "w" {
+			rest = append(rest, num...)
+			rest = append(rest, unit...)
+			continue
+		}
+
+		x, err := strconv.ParseFloat(num, 64)
+		if err != nil {
+			return 0, parseErr(err)
+		}
+		if unit == "w" {
+			x *= 7
+		}
+		days += x
+	}
+
+	var x time.Duration
+	if len(rest) != 0 {
+		var err error
+		if x, err = time.ParseDuration(string(rest)); err != nil {
+			return 0, errors.Wrap(err, ErrParseFailure)
+		}
+	}
+	if days != 0 {
+		d := days * float64(day)
+		if d > float64(math.MaxInt64-x) {
+			return 0, errors.Wrap(strconv.ErrRange, ErrValidationFailure)
+		}
+		x += time.Duration(d)
+	}
+	if neg {
+		x = -x
+	}
+	return x, nil
+}
+
+// MustExtendedDuration is like ExtendedDuration but panics if Value cannot be
+// parsed.
+func (v Value) MustExtendedDuration() time.Duration { return must(v.ExtendedDuration()) }
+
+// ExtendedDurationVar sets the value p points to using ExtendedDuration.
+func (v Value) ExtendedDurationVar(p *time.Duration) (err error) {
+	*p, err = v.ExtendedDuration()
+	return
+}
+
+// formatExtendedDuration formats d like time.Duration.String, but with the
+// weeks and days it contains as a "w" and "d" prefix, e.g. "1w2d3h0m0s".
+func formatExtendedDuration(d time.Duration) string {
+	if d > -day && d < day {
+		return d.String()
+	}
+
+	b := make([]byte, 0, 32)
+	u := uint64(d)
+	if d < 0 {
+		b = append(b, '-')
+		u = -u
+	}
+	if w := u / uint64(week); w > 0 {
+		b = strconv.AppendUint(b, w, 10)
+		b = append(b, 'w')
+	}
+	if days := u % uint64(week) / uint64(day); days > 0 {
+		b = strconv.AppendUint(b, days, 10)
+		b = append(b, 'd')
+	}
+	if rem := u % uint64(day); rem > 0 {
+		b = append(b, time.Duration(rem).String()...)
+	}
+	return string(b)
+}
+
+// parseDuration parses val as a time.Duration according to the
+// ExtendedDuration and DurationUnit options.
+func (o Options) parseDuration(val Value) (time.Duration, error) {
+	if o.DurationUnit > 0 {
+		if n, err := strconv.ParseInt(val.String(), 10, 64); err == nil {
+			if n > math.MaxInt64/int64(o.DurationUnit) || n < math.MinInt64/int64(o.DurationUnit) {
+				return 0, errors.Wrap(strconv.ErrRange, ErrValidationFailure)
+			}
+			return time.Duration(n) * o.DurationUnit, nil
+		}
+	}
+	if o.ExtendedDuration {
+		return val.ExtendedDuration()
+	}
+	return val.Duration()
+}
+
+func (o Options) unmarshalDuration(val Value, dest any) (err error) {
+	if val.IsEmpty() {
+		return nil
+	}
+
+	*dest.(*time.Duration), err = o.parseDuration(val)
+	return
+}
+
+func (o Options) marshalDuration(v any) (string, error) {
+	return formatExtendedDuration(v.(time.Duration)), nil
+}
+
 func unmarshalDuration(val Value, dest any) error {
 	if val.IsEmpty() {
 		return nil
@@ -0,0 +1,133 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"strings"
+
+	"github.com/go-pogo/errors"
+)
+
+const (
+	ErrInvalidContentEncoding errors.Msg = "invalid content encoding"
+	ErrInvalidCharset         errors.Msg = "invalid charset"
+)
+
+// ContentEncoding is the name of a (compression) encoding, as used by the
+// Content-Encoding and Accept-Encoding http headers. Its Value is validated
+// and canonicalized to one of the ContentEncoding constants when
+// unmarshaling.
+type ContentEncoding string
+
+const (
+	EncodingIdentity ContentEncoding = "identity"
+	EncodingGzip     ContentEncoding = "gzip"
+	EncodingDeflate  ContentEncoding = "deflate"
+	EncodingBrotli   ContentEncoding = "br"
+	EncodingZstd     ContentEncoding = "zstd"
+	EncodingCompress ContentEncoding = "compress"
+)
+
+var contentEncodings = map[string]ContentEncoding{
+	"identity": EncodingIdentity,
+	"gzip":     EncodingGzip,
+	"x-gzip":   EncodingGzip,
+	"deflate":  EncodingDeflate,
+	"br":       EncodingBrotli,
+	"brotli":   EncodingBrotli,
+	"zstd":     EncodingZstd,
+	"compress": EncodingCompress,
+}
+
+// ContentEncoding tries to parse Value as a ContentEncoding. The name is
+// matched case-insensitive and aliases, such as "x-gzip" and "brotli", are
+// canonicalized.
+func (v Value) ContentEncoding() (ContentEncoding, error) {
+	x, ok := contentEncodings[strings.ToLower(strings.TrimSpace(v.String()))]
+	if !ok {
+		return "", errors.Wrap(errors.New(ErrInvalidContentEncoding), ErrParseFailure)
+	}
+	return x, nil
+}
+
+// MustContentEncoding is like ContentEncoding but panics if Value cannot be
+// parsed.
+func (v Value) MustContentEncoding() ContentEncoding { return must(v.ContentEncoding()) }
+
+// ContentEncodingVar sets the value p points to using ContentEncoding.
+func (v Value) ContentEncodingVar(p *ContentEncoding) (err error) {
+	*p, err = v.ContentEncoding()
+	return
+}
+
+func unmarshalContentEncoding(val Value, dest any) error {
+	if val.IsEmpty() {
+		return nil
+	}
+
+	return val.ContentEncodingVar(dest.(*ContentEncoding))
+}
+
+// Charset is the name of a character set, as used by the charset parameter
+// of the Content-Type http header. Its Value is validated and canonicalized
+// to one of the Charset constants when unmarshaling.
+type Charset string
+
+const (
+	CharsetUTF8        Charset = "utf-8"
+	CharsetUTF16       Charset = "utf-16"
+	CharsetUTF16BE     Charset = "utf-16be"
+	CharsetUTF16LE     Charset = "utf-16le"
+	CharsetASCII       Charset = "us-ascii"
+	CharsetISO88591    Charset = "iso-8859-1"
+	CharsetISO885915   Charset = "iso-8859-15"
+	CharsetWindows1252 Charset = "windows-1252"
+)
+
+var charsets = map[string]Charset{
+	"utf-8":        CharsetUTF8,
+	"utf8":         CharsetUTF8,
+	"utf-16":       CharsetUTF16,
+	"utf16":        CharsetUTF16,
+	"utf-16be":     CharsetUTF16BE,
+	"utf-16le":     CharsetUTF16LE,
+	"us-ascii":     CharsetASCII,
+	"ascii":        CharsetASCII,
+	"iso-8859-1":   CharsetISO88591,
+	"iso8859-1":    CharsetISO88591,
+	"latin1":       CharsetISO88591,
+	"iso-8859-15":  CharsetISO885915,
+	"latin9":       CharsetISO885915,
+	"windows-1252": CharsetWindows1252,
+	"cp1252":       CharsetWindows1252,
+}
+
+// Charset tries to parse Value as a Charset. The name is matched
+// case-insensitive and aliases, such as "utf8" and "latin1", are
+// canonicalized.
+func (v Value) Charset() (Charset, error) {
+	x, ok := charsets[strings.ToLower(strings.TrimSpace(v.String()))]
+	if !ok {
+		return "", errors.Wrap(errors.New(ErrInvalidCharset), ErrParseFailure)
+	}
+	return x, nil
+}
+
+// MustCharset is like Charset but panics if Value cannot be parsed.
+func (v Value) MustCharset() Charset { return must(v.Charset()) }
+
+// CharsetVar sets the value p points to using Charset.
+func (v Value) CharsetVar(p *Charset) (err error) {
+	*p, err = v.Charset()
+	return
+}
+
+func unmarshalCharset(val Value, dest any) error {
+	if val.IsEmpty() {
+		return nil
+	}
+
+	return val.CharsetVar(dest.(*Charset))
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// ValueFromFileMode encodes v to a Value as an octal number with a leading
+// zero, e.g. "0644".
+func ValueFromFileMode(v fs.FileMode) Value {
+	if v == 0 {
+		return "0"
+	}
+	return Value("0" + strconv.FormatUint(uint64(v), 8))
+}
+
+// FileMode tries to parse Value as an octal fs.FileMode, e.g. "0644", "755"
+// or "0o600".
+func (v Value) FileMode() (fs.FileMode, error) {
+	str := v.String()
+	if s, ok := strings.CutPrefix(str, "0o"); ok {
+		str = s
+	} else if s, ok = strings.CutPrefix(str, "0O"); ok {
+		str = s
+	}
+
+	x, err := strconv.ParseUint(str, 8, 32)
+	return fs.FileMode(x), parseErr(err)
+}
+
+// MustFileMode is like FileMode but panics if Value cannot be parsed.
+func (v Value) MustFileMode() fs.FileMode { return must(v.FileMode()) }
+
+// FileModeVar sets the value p points to using FileMode.
+func (v Value) FileModeVar(p *fs.FileMode) (err error) {
+	*p, err = v.FileMode()
+	return
+}
+
+func unmarshalFileMode(val Value, dest any) error {
+	if val.IsEmpty() {
+		return nil
+	}
+
+	return val.FileModeVar(dest.(*fs.FileMode))
+}
+
+func marshalFileMode(v any) (string, error) {
+	return ValueFromFileMode(v.(fs.FileMode)).String(), nil
+}
@@ -4,11 +4,7 @@
 
 package rawconv
 
-import (
-	"strconv"
-
-	"github.com/go-pogo/errors"
-)
+import "strconv"
 
 // ValueFromFloat32 encodes v to a Value using strconv.FormatFloat.
 func ValueFromFloat32(v float32) Value {
@@ -26,6 +22,18 @@ func (v Value) Float32() (float32, error) {
 	return float32(x), err
 }
 
+// MustFloat32 is like Float32 but panics if Value cannot be parsed.
+func (v Value) MustFloat32() float32 { return must(v.Float32()) }
+
+// Float32Or is like Float32 but returns def if Value is empty or cannot be
+// parsed.
+func (v Value) Float32Or(def float32) float32 {
+	if x, err := v.Float32(); err == nil {
+		return x
+	}
+	return def
+}
+
 // Float32Var sets the value p points to using Float32.
 func (v Value) Float32Var(p *float32) (err error) {
 	*p, err = v.Float32()
@@ -37,6 +45,18 @@ func (v Value) Float64() (float64, error) {
 	return floatSize(v, 64)
 }
 
+// MustFloat64 is like Float64 but panics if Value cannot be parsed.
+func (v Value) MustFloat64() float64 { return must(v.Float64()) }
+
+// Float64Or is like Float64 but returns def if Value is empty or cannot be
+// parsed.
+func (v Value) Float64Or(def float64) float64 {
+	if x, err := v.Float64(); err == nil {
+		return x
+	}
+	return def
+}
+
 // Float64Var sets the value p points to using Float64.
 func (v Value) Float64Var(p *float64) (err error) {
 	*p, err = v.Float64()
@@ -45,8 +65,5 @@ func (v Value) Float64Var(p *float64) (err error) {
 
 func floatSize(v Value, bitSize int) (float64, error) {
 	x, err := strconv.ParseFloat(v.String(), bitSize)
-	if kind := errKind(err); kind != nil {
-		return x, errors.Wrap(err, kind)
-	}
-	return x, errors.WithStack(err)
+	return x, parseErr(err)
 }
@@ -0,0 +1,38 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"net"
+	"strconv"
+
+	"github.com/go-pogo/errors"
+)
+
+// ValueFromHostPort joins host and port to a Value of the form "host:port"
+// using net.JoinHostPort. An IPv6 host is enclosed in square brackets, e.g.
+// "[::1]:8080".
+func ValueFromHostPort(host string, port uint16) Value {
+	if n := len(host); n > 1 && host[0] == '[' && host[n-1] == ']' {
+		host = host[1 : n-1]
+	}
+	return Value(net.JoinHostPort(host, strconv.FormatUint(uint64(port), 10)))
+}
+
+// HostPort tries to split Value of the form "host:port", "[host]:port" or
+// "[host%zone]:port" into a host and numeric port using net.SplitHostPort.
+// An IPv6 host must be enclosed in square brackets, which are removed from
+// the returned host.
+func (v Value) HostPort() (host string, port uint16, err error) {
+	host, p, err := net.SplitHostPort(v.String())
+	if err != nil {
+		return "", 0, errors.Wrap(err, ErrParseFailure)
+	}
+	x, err := strconv.ParseUint(p, 10, 16)
+	if err != nil {
+		return "", 0, parseErr(err)
+	}
+	return host, uint16(x), nil
+}
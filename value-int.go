@@ -4,11 +4,7 @@
 
 package rawconv
 
-import (
-	"strconv"
-
-	"github.com/go-pogo/errors"
-)
+import "strconv"
 
 // ValueFromInt encodes v to a Value using strconv.FormatInt.
 func ValueFromInt(v int) Value {
@@ -41,6 +37,18 @@ func (v Value) Int() (int, error) {
 	return int(x), err
 }
 
+// MustInt is like Int but panics if Value cannot be parsed.
+func (v Value) MustInt() int { return must(v.Int()) }
+
+// IntOr is like Int but returns def if Value is empty or cannot be
+// parsed.
+func (v Value) IntOr(def int) int {
+	if x, err := v.Int(); err == nil {
+		return x
+	}
+	return def
+}
+
 // IntVar sets the value p points to using Int.
 func (v Value) IntVar(p *int) (err error) {
 	*p, err = v.Int()
@@ -53,6 +61,18 @@ func (v Value) Int8() (int8, error) {
 	return int8(x), err
 }
 
+// MustInt8 is like Int8 but panics if Value cannot be parsed.
+func (v Value) MustInt8() int8 { return must(v.Int8()) }
+
+// Int8Or is like Int8 but returns def if Value is empty or cannot be
+// parsed.
+func (v Value) Int8Or(def int8) int8 {
+	if x, err := v.Int8(); err == nil {
+		return x
+	}
+	return def
+}
+
 // Int8Var sets the value p points to using Int8.
 func (v Value) Int8Var(p *int8) (err error) {
 	*p, err = v.Int8()
@@ -65,6 +85,18 @@ func (v Value) Int16() (int16, error) {
 	return int16(x), err
 }
 
+// MustInt16 is like Int16 but panics if Value cannot be parsed.
+func (v Value) MustInt16() int16 { return must(v.Int16()) }
+
+// Int16Or is like Int16 but returns def if Value is empty or cannot be
+// parsed.
+func (v Value) Int16Or(def int16) int16 {
+	if x, err := v.Int16(); err == nil {
+		return x
+	}
+	return def
+}
+
 // Int16Var sets the value p points to using Int16.
 func (v Value) Int16Var(p *int16) (err error) {
 	*p, err = v.Int16()
@@ -77,6 +109,18 @@ func (v Value) Int32() (int32, error) {
 	return int32(x), err
 }
 
+// MustInt32 is like Int32 but panics if Value cannot be parsed.
+func (v Value) MustInt32() int32 { return must(v.Int32()) }
+
+// Int32Or is like Int32 but returns def if Value is empty or cannot be
+// parsed.
+func (v Value) Int32Or(def int32) int32 {
+	if x, err := v.Int32(); err == nil {
+		return x
+	}
+	return def
+}
+
 // Int32Var sets the value p points to using Int32.
 func (v Value) Int32Var(p *int32) (err error) {
 	*p, err = v.Int32()
@@ -88,16 +132,54 @@ func (v Value) Int64() (int64, error) {
 	return intSize(v, 64)
 }
 
+// MustInt64 is like Int64 but panics if Value cannot be parsed.
+func (v Value) MustInt64() int64 { return must(v.Int64()) }
+
+// Int64Or is like Int64 but returns def if Value is empty or cannot be
+// parsed.
+func (v Value) Int64Or(def int64) int64 {
+	if x, err := v.Int64(); err == nil {
+		return x
+	}
+	return def
+}
+
 // Int64Var sets the value p points to using Int64.
 func (v Value) Int64Var(p *int64) (err error) {
 	*p, err = v.Int64()
 	return
 }
 
+// IntBase tries to parse Value as an int64 in the given base using
+// strconv.ParseInt. A base of 0 detects the base from the prefix of Value,
+// e.g. "0x" for hexadecimal.
+func (v Value) IntBase(base int) (int64, error) {
+	return intBase(v, base, 64)
+}
+
 func intSize(v Value, bitSize int) (int64, error) {
-	x, err := strconv.ParseInt(v.String(), 0, bitSize)
-	if kind := errKind(err); kind != nil {
-		return x, errors.Wrap(err, kind)
+	return intBase(v, 0, bitSize)
+}
+
+func intBase(v Value, base, bitSize int) (int64, error) {
+	x, err := strconv.ParseInt(v.String(), base, bitSize)
+	return x, parseErr(err)
+}
+
+// parseBase returns the base which is used to parse integers, see
+// Options.IntBase.
+func (o Options) parseBase() int {
+	if o.IntBase < 2 || o.IntBase > 36 {
+		return 0
+	}
+	return o.IntBase
+}
+
+// formatBase returns the base which is used to format integers, see
+// Options.IntBase.
+func (o Options) formatBase() int {
+	if o.IntBase < 2 || o.IntBase > 36 {
+		return 10
 	}
-	return x, errors.WithStack(err)
+	return o.IntBase
 }
@@ -0,0 +1,54 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/go-pogo/errors"
+)
+
+var mapValueType = reflect.TypeOf(map[string]Value(nil))
+
+// mapFormatErr returns a ParseError, wrapping ErrMapInvalidFormat, for item
+// of a map of typ which lacks a key value separator.
+func mapFormatErr(item string, typ reflect.Type) error {
+	return &ParseError{
+		Value: Value(item),
+		Type:  typ,
+		Err:   errors.New(ErrMapInvalidFormat),
+	}
+}
+
+// Map splits Value into a map of key value pairs, where each item is
+// separated by itemSep and each key from its value by kvSep. Empty separators
+// default to DefaultItemsSeparator and DefaultKeyValueSeparator. Keys and
+// values are trimmed of leading and trailing whitespace, and the last value of
+// a duplicate key wins. An item without kvSep results in an UnmarshalError
+// wrapping a ParseError, which contains the item and wraps
+// ErrMapInvalidFormat.
+//
+//	m, err := rawconv.Value("host=localhost,port=8080").Map("", "")
+//	port, err := m["port"].Uint16()
+func (v Value) Map(itemSep, kvSep string) (map[string]Value, error) {
+	if itemSep == "" {
+		itemSep = DefaultItemsSeparator
+	}
+	if kvSep == "" {
+		kvSep = DefaultKeyValueSeparator
+	}
+
+	items := v.ItemsSep(itemSep)
+	res := make(map[string]Value, len(items))
+	for i, item := range items {
+		key, val, ok := strings.Cut(item.String(), kvSep)
+		if !ok {
+			return nil, &UnmarshalError{Index: i, Err: mapFormatErr(item.String(), mapValueType)}
+		}
+		res[strings.TrimSpace(key)] = Value(strings.TrimSpace(val))
+	}
+	return res, nil
+}
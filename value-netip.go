@@ -0,0 +1,105 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"net/netip"
+
+	"github.com/go-pogo/errors"
+)
+
+// Addr tries to parse Value as a netip.Addr using netip.ParseAddr.
+func (v Value) Addr() (netip.Addr, error) {
+	x, err := netip.ParseAddr(v.String())
+	return x, errors.Wrap(err, ErrParseFailure)
+}
+
+// MustAddr is like Addr but panics if Value cannot be parsed.
+func (v Value) MustAddr() netip.Addr { return must(v.Addr()) }
+
+// AddrVar sets the value p points to using Addr.
+func (v Value) AddrVar(p *netip.Addr) (err error) {
+	*p, err = v.Addr()
+	return
+}
+
+// AddrPort tries to parse Value as a netip.AddrPort using
+// netip.ParseAddrPort.
+func (v Value) AddrPort() (netip.AddrPort, error) {
+	x, err := netip.ParseAddrPort(v.String())
+	return x, errors.Wrap(err, ErrParseFailure)
+}
+
+// MustAddrPort is like AddrPort but panics if Value cannot be parsed.
+func (v Value) MustAddrPort() netip.AddrPort { return must(v.AddrPort()) }
+
+// AddrPortVar sets the value p points to using AddrPort.
+func (v Value) AddrPortVar(p *netip.AddrPort) (err error) {
+	*p, err = v.AddrPort()
+	return
+}
+
+// Prefix tries to parse Value as a netip.Prefix using netip.ParsePrefix.
+func (v Value) Prefix() (netip.Prefix, error) {
+	x, err := netip.ParsePrefix(v.String())
+	return x, errors.Wrap(err, ErrParseFailure)
+}
+
+// MustPrefix is like Prefix but panics if Value cannot be parsed.
+func (v Value) MustPrefix() netip.Prefix { return must(v.Prefix()) }
+
+// PrefixVar sets the value p points to using Prefix.
+func (v Value) PrefixVar(p *netip.Prefix) (err error) {
+	*p, err = v.Prefix()
+	return
+}
+
+func unmarshalAddr(val Value, dest any) error {
+	if val.IsEmpty() {
+		return nil
+	}
+
+	return val.AddrVar(dest.(*netip.Addr))
+}
+
+func marshalAddr(v any) (string, error) {
+	x := v.(netip.Addr)
+	if !x.IsValid() {
+		return "", nil
+	}
+	return x.String(), nil
+}
+
+func unmarshalAddrPort(val Value, dest any) error {
+	if val.IsEmpty() {
+		return nil
+	}
+
+	return val.AddrPortVar(dest.(*netip.AddrPort))
+}
+
+func marshalAddrPort(v any) (string, error) {
+	x := v.(netip.AddrPort)
+	if !x.IsValid() {
+		return "", nil
+	}
+	return x.String(), nil
+}
+
+func unmarshalPrefix(val Value, dest any) error {
+	if val.IsEmpty() {
+		return nil
+	}
+
+	return val.PrefixVar(dest.(*netip.Prefix))
+}
+
+func marshalPrefix(v any) (string, error) {
+	x := v.(netip.Prefix)
+	if !x.IsValid() {
+		return "", nil
+	}
+	return x.String(), nil
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"regexp"
+
+	"github.com/go-pogo/errors"
+)
+
+// Regexp tries to compile Value as a regular expression using
+// regexp.Compile.
+func (v Value) Regexp() (*regexp.Regexp, error) {
+	x, err := regexp.Compile(v.String())
+	if err != nil {
+		return nil, errors.Wrap(err, ErrParseFailure)
+	}
+	return x, nil
+}
+
+// MustRegexp is like Regexp but panics if Value cannot be compiled.
+func (v Value) MustRegexp() *regexp.Regexp { return must(v.Regexp()) }
+
+// RegexpVar sets the value p points to using Regexp.
+func (v Value) RegexpVar(p *regexp.Regexp) error {
+	x, err := v.Regexp()
+	if err != nil {
+		return err
+	}
+	*p = *x
+	return nil
+}
+
+func unmarshalRegexp(val Value, dest any) error {
+	if val.IsEmpty() {
+		return nil
+	}
+
+	return val.RegexpVar(dest.(*regexp.Regexp))
+}
+
+func marshalRegexp(v any) (string, error) {
+	x := v.(regexp.Regexp)
+	return x.String(), nil
+}
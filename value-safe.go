@@ -0,0 +1,12 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !rawconv_unsafe
+
+package rawconv
+
+// UnsafeBytes returns Value as raw bytes without copying them, when built with
+// the rawconv_unsafe build tag. The returned bytes must be treated as
+// read-only. Without the build tag it is equal to Bytes.
+func (v Value) UnsafeBytes() []byte { return v.Bytes() }
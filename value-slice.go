@@ -0,0 +1,119 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"strings"
+	"time"
+)
+
+// Items splits Value into its items using DefaultItemsSeparator. Each item is
+// trimmed of leading and trailing whitespace. An empty Value has no items.
+func (v Value) Items() []Value { return v.ItemsSep(DefaultItemsSeparator) }
+
+// ItemsSep is like Items but splits Value using separator sep.
+func (v Value) ItemsSep(sep string) []Value {
+	if v.IsEmpty() {
+		return nil
+	}
+
+	parts := strings.Split(v.String(), sep)
+	items := make([]Value, len(parts))
+	for i, part := range parts {
+		items[i] = Value(strings.TrimSpace(part))
+	}
+	return items
+}
+
+// JoinValues joins items into a single Value, using the items separator of
+// opts. Items are quoted or escaped according to opts.Quote and opts.Escape,
+// exactly like the items of a slice are when it is marshaled by a Marshaler
+// with opts. This makes it the inverse of unmarshaling a []Value with an
+// Unmarshaler with the same Options.
+func JoinValues(items []Value, opts Options) Value {
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return Value(opts.quote(items[0].String()))
+	}
+
+	sep := opts.itemSeparator()
+	var buf strings.Builder
+	for i, item := range items {
+		if i > 0 {
+			buf.WriteString(sep)
+		}
+		buf.WriteString(opts.quote(item.String()))
+	}
+	return Value(buf.String())
+}
+
+// StringSlice returns the items of Value as a slice of strings, see Items.
+func (v Value) StringSlice() []string { return v.StringSliceSep(DefaultItemsSeparator) }
+
+// StringSliceSep is like StringSlice but splits Value using separator sep.
+func (v Value) StringSliceSep(sep string) []string {
+	items := v.ItemsSep(sep)
+	if items == nil {
+		return nil
+	}
+
+	res := make([]string, len(items))
+	for i, item := range items {
+		res[i] = item.String()
+	}
+	return res
+}
+
+// IntSlice tries to parse the items of Value as ints, see Items and Int.
+//
+//	ints, err := rawconv.Value("1,2,3").IntSlice()
+func (v Value) IntSlice() ([]int, error) { return v.IntSliceSep(DefaultItemsSeparator) }
+
+// IntSliceSep is like IntSlice but splits Value using separator sep.
+func (v Value) IntSliceSep(sep string) ([]int, error) {
+	return parseItems(v.ItemsSep(sep), Value.Int)
+}
+
+// Float64Slice tries to parse the items of Value as float64s, see Items and
+// Float64.
+func (v Value) Float64Slice() ([]float64, error) {
+	return v.Float64SliceSep(DefaultItemsSeparator)
+}
+
+// Float64SliceSep is like Float64Slice but splits Value using separator sep.
+func (v Value) Float64SliceSep(sep string) ([]float64, error) {
+	return parseItems(v.ItemsSep(sep), Value.Float64)
+}
+
+// DurationSlice tries to parse the items of Value as time.Durations, see
+// Items and Duration.
+func (v Value) DurationSlice() ([]time.Duration, error) {
+	return v.DurationSliceSep(DefaultItemsSeparator)
+}
+
+// DurationSliceSep is like DurationSlice but splits Value using separator sep.
+func (v Value) DurationSliceSep(sep string) ([]time.Duration, error) {
+	return parseItems(v.ItemsSep(sep), Value.Duration)
+}
+
+// parseItems parses each item using parse. The error of an item which fails
+// to parse is wrapped in an UnmarshalError.
+func parseItems[T any](items []Value, parse func(Value) (T, error)) ([]T, error) {
+	if items == nil {
+		return nil, nil
+	}
+
+	res := make([]T, len(items))
+	for i, item := range items {
+		x, err := parse(item)
+		if err != nil {
+			return nil, &UnmarshalError{Index: i, Err: err}
+		}
+		res[i] = x
+	}
+	return res, nil
+}
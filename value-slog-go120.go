@@ -0,0 +1,10 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !go1.21
+
+package rawconv
+
+// registerSlog is a no-op, log/slog is only available since go1.21.
+func registerSlog() {}
@@ -0,0 +1,66 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.21
+
+package rawconv
+
+import (
+	"log/slog"
+	"reflect"
+	"strconv"
+
+	"github.com/go-pogo/errors"
+)
+
+// SlogLevel tries to parse Value as a slog.Level. Besides the names accepted
+// by slog.Level.UnmarshalText, e.g. "debug", "INFO", "warn" or "ERROR+2",
+// a plain number is parsed as the numeric level, e.g. "-4" equals "debug".
+func (v Value) SlogLevel() (slog.Level, error) {
+	if n, err := strconv.Atoi(v.String()); err == nil {
+		return slog.Level(n), nil
+	}
+
+	var x slog.Level
+	if err := x.UnmarshalText(v.Bytes()); err != nil {
+		return 0, errors.Wrap(err, ErrParseFailure)
+	}
+	return x, nil
+}
+
+// MustSlogLevel is like SlogLevel but panics if Value cannot be parsed.
+func (v Value) MustSlogLevel() slog.Level { return must(v.SlogLevel()) }
+
+// SlogLevelOr is like SlogLevel but returns def if Value is empty or cannot
+// be parsed.
+func (v Value) SlogLevelOr(def slog.Level) slog.Level {
+	if x, err := v.SlogLevel(); err == nil {
+		return x
+	}
+	return def
+}
+
+// SlogLevelVar sets the value p points to using SlogLevel.
+func (v Value) SlogLevelVar(p *slog.Level) (err error) {
+	*p, err = v.SlogLevel()
+	return
+}
+
+func registerSlog() {
+	slogLevel := reflect.TypeOf(slog.LevelInfo)
+	RegisterUnmarshalFunc(slogLevel, unmarshalSlogLevel)
+	RegisterMarshalFunc(slogLevel, marshalSlogLevel)
+}
+
+func unmarshalSlogLevel(val Value, dest any) error {
+	if val.IsEmpty() {
+		return nil
+	}
+
+	return val.SlogLevelVar(dest.(*slog.Level))
+}
+
+func marshalSlogLevel(v any) (string, error) {
+	return v.(slog.Level).String(), nil
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.21
+
+package rawconv
+
+import (
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValue_SlogLevel(t *testing.T) {
+	tests := map[Value]slog.Level{
+		"debug":   slog.LevelDebug,
+		"INFO":    slog.LevelInfo,
+		"Warn":    slog.LevelWarn,
+		"error":   slog.LevelError,
+		"INFO+2":  slog.LevelInfo + 2,
+		"error-1": slog.LevelError - 1,
+		"-4":      slog.LevelDebug,
+		"12":      slog.Level(12),
+	}
+	for input, want := range tests {
+		t.Run(input.String(), func(t *testing.T) {
+			have, err := input.SlogLevel()
+			assert.NoError(t, err)
+			assert.Equal(t, want, have)
+		})
+	}
+
+	_, err := Value("verbose").SlogLevel()
+	assert.ErrorIs(t, err, ErrParseFailure)
+	assert.Panics(t, func() { Value("").MustSlogLevel() })
+	assert.Equal(t, slog.LevelWarn, Value("x").SlogLevelOr(slog.LevelWarn))
+
+	t.Run("unmarshal", func(t *testing.T) {
+		var have struct{ Level slog.Level }
+		assert.NoError(t, UnmarshalStruct(map[string]Value{"Level": "warn+1"}, &have))
+		assert.Equal(t, slog.LevelWarn+1, have.Level)
+	})
+	t.Run("marshal", func(t *testing.T) {
+		have, err := Marshal(slog.LevelError + 2)
+		assert.NoError(t, err)
+		assert.Equal(t, Value("ERROR+2"), have)
+	})
+	t.Run("registered", func(t *testing.T) {
+		typ := reflect.TypeOf(slog.LevelInfo)
+		assert.Equal(t, RegisteredType, unmarshaler.Mechanism(typ))
+		assert.Equal(t, RegisteredType, marshaler.Mechanism(typ))
+	})
+}
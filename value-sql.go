@@ -0,0 +1,37 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+
+	"github.com/go-pogo/errors"
+)
+
+var (
+	_ driver.Valuer = Value("")
+	_ sql.Scanner   = (*Value)(nil)
+)
+
+// Value implements driver.Valuer and returns Value as raw string.
+func (v Value) Value() (driver.Value, error) { return v.String(), nil }
+
+// Scan implements sql.Scanner and sets Value to src, which must be a string,
+// a []byte or nil. A nil src results in an empty Value.
+func (v *Value) Scan(src any) error {
+	switch x := src.(type) {
+	case string:
+		*v = Value(x)
+	case []byte:
+		*v = Value(x)
+	case nil:
+		*v = ""
+	default:
+		return errors.WithStack(&UnsupportedTypeError{Type: reflect.TypeOf(src)})
+	}
+	return nil
+}
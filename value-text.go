@@ -0,0 +1,116 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"strconv"
+
+	"github.com/go-pogo/errors"
+)
+
+var (
+	_ encoding.TextMarshaler   = Value("")
+	_ encoding.TextUnmarshaler = (*Value)(nil)
+	_ json.Marshaler           = Value("")
+	_ json.Unmarshaler         = (*Value)(nil)
+)
+
+// MarshalText implements encoding.TextMarshaler and returns Value as raw
+// bytes.
+func (v Value) MarshalText() ([]byte, error) { return v.Bytes(), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler and sets Value to a copy
+// of text.
+func (v *Value) UnmarshalText(text []byte) error {
+	*v = Value(text)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler and returns Value as json string.
+func (v Value) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. A json string is set as its
+// unquoted content, a json null results in an empty Value. Any other json
+// value, such as a number, boolean, object or array, is set as raw Value.
+func (v *Value) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case len(data) == 0:
+		return errors.New(ErrInvalidJSON)
+	case data[0] == '"':
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return errors.Wrap(err, ErrInvalidJSON)
+		}
+		*v = Value(str)
+	case bytes.Equal(data, []byte("null")):
+		*v = ""
+	default:
+		if !json.Valid(data) {
+			return errors.New(ErrInvalidJSON)
+		}
+		*v = Value(data)
+	}
+	return nil
+}
+
+// JSONNumber tries to parse Value as a json.Number. Value must be a valid
+// json number, e.g. "42", "-1.5" or "1e3", unlike Float64 it does not accept
+// values such as "0x10", "+1" or "Inf".
+func (v Value) JSONNumber() (json.Number, error) {
+	str := v.String()
+	if !isJSONNumber(str) {
+		return "", errors.Wrap(strconv.ErrSyntax, ErrParseFailure)
+	}
+	return json.Number(str), nil
+}
+
+// MustJSONNumber is like JSONNumber but panics if Value cannot be parsed.
+func (v Value) MustJSONNumber() json.Number { return must(v.JSONNumber()) }
+
+// JSONValue returns Value in the shape encoding/json produces when it decodes
+// the equivalent json scalar into an any. That is a bool for "true" and
+// "false", nil for "null", a float64 for a valid json number, or a json.Number
+// when useNumber is set (like json.Decoder.UseNumber), and a string for any
+// other Value. An error is returned when a number does not fit a float64.
+func (v Value) JSONValue(useNumber bool) (any, error) {
+	str := v.String()
+	switch str {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	if !isJSONNumber(str) {
+		return str, nil
+	}
+	if useNumber {
+		return json.Number(str), nil
+	}
+
+	x, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return nil, parseErr(err)
+	}
+	return x, nil
+}
+
+// isJSONNumber indicates if str is a valid json number.
+func isJSONNumber(str string) bool {
+	if str == "" {
+		return false
+	}
+	if c := str[0]; c != '-' && (c < '0' || c > '9') {
+		return false
+	}
+	return json.Valid([]byte(str))
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/go-pogo/errors"
+)
+
+// DefaultTimeLayouts are the layouts that are tried when parsing a Value as
+// time.Time, when no other layouts are provided.
+var DefaultTimeLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC850,
+	time.ANSIC,
+	time.DateTime,
+	time.DateOnly,
+	"02 Jan 2006",
+}
+
+// Time tries to parse Value as a time.Time using time.Parse with each of the
+// provided layouts, in order, until one succeeds. When no layouts are
+// provided, DefaultTimeLayouts are used.
+func (v Value) Time(layouts ...string) (time.Time, error) {
+	if len(layouts) == 0 {
+		layouts = DefaultTimeLayouts
+	}
+
+	var firstErr error
+	for _, layout := range layouts {
+		x, err := time.Parse(layout, v.String())
+		if err == nil {
+			return x, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return time.Time{}, errors.Wrap(firstErr, ErrParseFailure)
+}
+
+// MustTime is like Time but panics if Value cannot be parsed.
+func (v Value) MustTime(layouts ...string) time.Time { return must(v.Time(layouts...)) }
+
+// TimeOr is like Time but returns def if Value is empty or cannot be parsed.
+func (v Value) TimeOr(def time.Time, layouts ...string) time.Time {
+	if x, err := v.Time(layouts...); err == nil {
+		return x
+	}
+	return def
+}
+
+// TimeVar sets the value p points to using Time.
+func (v Value) TimeVar(p *time.Time, layouts ...string) (err error) {
+	*p, err = v.Time(layouts...)
+	return
+}
+
+// Unix tries to parse Value as an integer number of seconds since the Unix
+// epoch, and returns the corresponding time.Time in UTC.
+func (v Value) Unix() (time.Time, error) {
+	x, err := strconv.ParseInt(v.String(), 10, 64)
+	if err != nil {
+		return time.Time{}, parseErr(err)
+	}
+	return time.Unix(x, 0).UTC(), nil
+}
+
+// UnixMilli tries to parse Value as an integer number of milliseconds since
+// the Unix epoch, and returns the corresponding time.Time in UTC.
+func (v Value) UnixMilli() (time.Time, error) {
+	x, err := strconv.ParseInt(v.String(), 10, 64)
+	if err != nil {
+		return time.Time{}, parseErr(err)
+	}
+	return time.UnixMilli(x).UTC(), nil
+}
+
+// TimeEpoch determines if, and how, time.Time values are (un)marshaled as
+// the time elapsed since the Unix epoch.
+type TimeEpoch uint8
+
+const (
+	// EpochNone (un)marshals time.Time values using time layouts only.
+	EpochNone TimeEpoch = iota
+	// EpochSeconds (un)marshals time.Time values as the number of seconds
+	// since the Unix epoch, see Value.Unix.
+	EpochSeconds
+	// EpochMillis (un)marshals time.Time values as the number of
+	// milliseconds since the Unix epoch, see Value.UnixMilli.
+	EpochMillis
+)
+
+// Location parses Value as a time zone name using time.LoadLocation.
+func (v Value) Location() (*time.Location, error) {
+	x, err := time.LoadLocation(v.String())
+	if err != nil {
+		return nil, errors.Wrap(err, ErrParseFailure)
+	}
+	return x, nil
+}
+
+// MustLocation is like Location but panics if Value cannot be parsed.
+func (v Value) MustLocation() *time.Location { return must(v.Location()) }
+
+// LocationVar sets the value p points to using Location. The
+// *time.Location is set as is, because a time.Location must not be copied.
+func (v Value) LocationVar(p **time.Location) error {
+	x, err := v.Location()
+	if err != nil {
+		return err
+	}
+	*p = x
+	return nil
+}
+
+func (o Options) timeLayout() string {
+	if o.TimeLayout == "" {
+		return time.RFC3339Nano
+	}
+	return o.TimeLayout
+}
+
+func (o Options) timeLayouts() []string {
+	layouts := o.TimeLayouts
+	if layouts == nil {
+		layouts = DefaultTimeLayouts
+	}
+	if o.TimeLayout == "" {
+		return layouts
+	}
+	return append([]string{o.TimeLayout}, layouts...)
+}
+
+// parseTime parses val as an epoch timestamp when it is an integer and
+// TimeEpoch is set, otherwise it uses the time layouts.
+func (o Options) parseTime(val Value) (time.Time, error) {
+	switch o.TimeEpoch {
+	case EpochSeconds:
+		if x, err := val.Unix(); err == nil {
+			return x, nil
+		}
+	case EpochMillis:
+		if x, err := val.UnixMilli(); err == nil {
+			return x, nil
+		}
+	}
+	return val.Time(o.timeLayouts()...)
+}
+
+func (o Options) unmarshalTime(val Value, dest any) (err error) {
+	if val.IsEmpty() {
+		return nil
+	}
+
+	*dest.(*time.Time), err = o.parseTime(val)
+	return
+}
+
+func (o Options) marshalTime(v any) (string, error) {
+	t := v.(time.Time)
+	switch o.TimeEpoch {
+	case EpochSeconds:
+		return strconv.FormatInt(t.Unix(), 10), nil
+	case EpochMillis:
+		return strconv.FormatInt(t.UnixMilli(), 10), nil
+	}
+	return t.Format(o.timeLayout()), nil
+}
+
+func unmarshalTime(val Value, dest any) error { return Options{}.unmarshalTime(val, dest) }
+
+func marshalTime(v any) (string, error) { return Options{}.marshalTime(v) }
+
+func unmarshalLocation(val Value, dest any) error {
+	if val.IsEmpty() {
+		return nil
+	}
+
+	return val.LocationVar(dest.(**time.Location))
+}
+
+func marshalLocation(v any) (string, error) {
+	return v.(*time.Location).String(), nil
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+// Tristate is a bool which can also be unset. Unlike a *bool, an empty Value
+// is unmarshaled to TristateUnset instead of leaving the destination
+// untouched, and TristateUnset is marshaled to an empty Value.
+type Tristate uint8
+
+const (
+	TristateUnset Tristate = iota
+	TristateFalse
+	TristateTrue
+)
+
+// TristateOf returns TristateTrue or TristateFalse depending on b.
+func TristateOf(b bool) Tristate {
+	if b {
+		return TristateTrue
+	}
+	return TristateFalse
+}
+
+// IsSet indicates if t is either TristateTrue or TristateFalse.
+func (t Tristate) IsSet() bool { return t == TristateTrue || t == TristateFalse }
+
+// Bool returns true when t is TristateTrue, and false otherwise.
+func (t Tristate) Bool() bool { return t == TristateTrue }
+
+// BoolOr returns def when t is unset, otherwise it returns Bool.
+func (t Tristate) BoolOr(def bool) bool {
+	if !t.IsSet() {
+		return def
+	}
+	return t.Bool()
+}
+
+// String returns "true", "false" or an empty string when t is unset.
+func (t Tristate) String() string { return Options{}.formatTristate(t) }
+
+// Tristate tries to parse Value as a Tristate. An empty Value results in
+// TristateUnset, any other Value is parsed using Bool.
+func (v Value) Tristate() (Tristate, error) { return Options{}.parseTristate(v) }
+
+// MustTristate is like Tristate but panics if Value cannot be parsed.
+func (v Value) MustTristate() Tristate { return must(v.Tristate()) }
+
+// TristateVar sets the value p points to using Tristate.
+func (v Value) TristateVar(p *Tristate) (err error) {
+	*p, err = v.Tristate()
+	return
+}
+
+func (o Options) parseTristate(v Value) (Tristate, error) {
+	if v.IsEmpty() {
+		return TristateUnset, nil
+	}
+
+	b, err := o.parseBool(v)
+	if err != nil {
+		return TristateUnset, err
+	}
+	return TristateOf(b), nil
+}
+
+func (o Options) formatTristate(t Tristate) string {
+	if !t.IsSet() {
+		return ""
+	}
+	return o.formatBool(t.Bool())
+}
+
+func (o Options) unmarshalTristate(val Value, dest any) (err error) {
+	*dest.(*Tristate), err = o.parseTristate(val)
+	return
+}
+
+func (o Options) marshalTristate(v any) (string, error) {
+	return o.formatTristate(v.(Tristate)), nil
+}
+
+func unmarshalTristate(val Value, dest any) error {
+	return Options{}.unmarshalTristate(val, dest)
+}
+
+func marshalTristate(v any) (string, error) { return Options{}.marshalTristate(v) }
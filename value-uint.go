@@ -4,11 +4,7 @@
 
 package rawconv
 
-import (
-	"strconv"
-
-	"github.com/go-pogo/errors"
-)
+import "strconv"
 
 // ValueFromUint encodes v to a Value using strconv.FormatUint.
 func ValueFromUint(v uint) Value {
@@ -41,6 +37,18 @@ func (v Value) Uint() (uint, error) {
 	return uint(x), err
 }
 
+// MustUint is like Uint but panics if Value cannot be parsed.
+func (v Value) MustUint() uint { return must(v.Uint()) }
+
+// UintOr is like Uint but returns def if Value is empty or cannot be
+// parsed.
+func (v Value) UintOr(def uint) uint {
+	if x, err := v.Uint(); err == nil {
+		return x
+	}
+	return def
+}
+
 // UintVar sets the value p points to using Uint.
 func (v Value) UintVar(p *uint) (err error) {
 	*p, err = v.Uint()
@@ -53,6 +61,18 @@ func (v Value) Uint8() (uint8, error) {
 	return uint8(x), err
 }
 
+// MustUint8 is like Uint8 but panics if Value cannot be parsed.
+func (v Value) MustUint8() uint8 { return must(v.Uint8()) }
+
+// Uint8Or is like Uint8 but returns def if Value is empty or cannot be
+// parsed.
+func (v Value) Uint8Or(def uint8) uint8 {
+	if x, err := v.Uint8(); err == nil {
+		return x
+	}
+	return def
+}
+
 // Uint8Var sets the value p points to using Uint8.
 func (v Value) Uint8Var(p *uint8) (err error) {
 	*p, err = v.Uint8()
@@ -65,6 +85,18 @@ func (v Value) Uint16() (uint16, error) {
 	return uint16(x), err
 }
 
+// MustUint16 is like Uint16 but panics if Value cannot be parsed.
+func (v Value) MustUint16() uint16 { return must(v.Uint16()) }
+
+// Uint16Or is like Uint16 but returns def if Value is empty or cannot be
+// parsed.
+func (v Value) Uint16Or(def uint16) uint16 {
+	if x, err := v.Uint16(); err == nil {
+		return x
+	}
+	return def
+}
+
 // Uint16Var sets the value p points to using Uint16.
 func (v Value) Uint16Var(p *uint16) (err error) {
 	*p, err = v.Uint16()
@@ -77,6 +109,18 @@ func (v Value) Uint32() (uint32, error) {
 	return uint32(x), err
 }
 
+// MustUint32 is like Uint32 but panics if Value cannot be parsed.
+func (v Value) MustUint32() uint32 { return must(v.Uint32()) }
+
+// Uint32Or is like Uint32 but returns def if Value is empty or cannot be
+// parsed.
+func (v Value) Uint32Or(def uint32) uint32 {
+	if x, err := v.Uint32(); err == nil {
+		return x
+	}
+	return def
+}
+
 // Uint32Var sets the value p points to using Uint32.
 func (v Value) Uint32Var(p *uint32) (err error) {
 	*p, err = v.Uint32()
@@ -88,16 +132,36 @@ func (v Value) Uint64() (uint64, error) {
 	return uintSize(v, 64)
 }
 
+// MustUint64 is like Uint64 but panics if Value cannot be parsed.
+func (v Value) MustUint64() uint64 { return must(v.Uint64()) }
+
+// Uint64Or is like Uint64 but returns def if Value is empty or cannot be
+// parsed.
+func (v Value) Uint64Or(def uint64) uint64 {
+	if x, err := v.Uint64(); err == nil {
+		return x
+	}
+	return def
+}
+
 // Uint64Var sets the value p points to using Uint64.
 func (v Value) Uint64Var(p *uint64) (err error) {
 	*p, err = v.Uint64()
 	return
 }
 
+// UintBase tries to parse Value as an uint64 in the given base using
+// strconv.ParseUint. A base of 0 detects the base from the prefix of Value,
+// e.g. "0x" for hexadecimal.
+func (v Value) UintBase(base int) (uint64, error) {
+	return uintBase(v, base, 64)
+}
+
 func uintSize(v Value, bitSize int) (uint64, error) {
-	x, err := strconv.ParseUint(v.String(), 0, bitSize)
-	if kind := errKind(err); kind != nil {
-		return x, errors.Wrap(err, kind)
-	}
-	return x, errors.WithStack(err)
+	return uintBase(v, 0, bitSize)
+}
+
+func uintBase(v Value, base, bitSize int) (uint64, error) {
+	x, err := strconv.ParseUint(v.String(), base, bitSize)
+	return x, parseErr(err)
 }
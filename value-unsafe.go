@@ -0,0 +1,22 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build rawconv_unsafe
+
+package rawconv
+
+import "unsafe"
+
+// UnsafeBytes returns Value as raw bytes without copying them. The returned
+// bytes share their memory with Value and must be treated as read-only;
+// modifying them results in undefined behavior.
+//
+// UnsafeBytes only avoids the copy when built with the rawconv_unsafe build
+// tag, otherwise it is equal to Bytes.
+func (v Value) UnsafeBytes() []byte {
+	if v == "" {
+		return nil
+	}
+	return unsafe.Slice(unsafe.StringData(string(v)), len(v))
+}
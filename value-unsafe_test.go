@@ -0,0 +1,23 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build rawconv_unsafe
+
+package rawconv
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValue_UnsafeBytes_zeroCopy(t *testing.T) {
+	str := "foo bar"
+	have := Value(str).UnsafeBytes()
+	assert.Equal(t, unsafe.StringData(str), &have[0])
+	assert.Zero(t, testing.AllocsPerRun(100, func() {
+		_ = Value(str).UnsafeBytes()
+	}))
+}
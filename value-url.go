@@ -19,6 +19,9 @@ func (v Value) Url() (*url.URL, error) {
 	return x, nil
 }
 
+// MustUrl is like Url but panics if Value cannot be parsed.
+func (v Value) MustUrl() *url.URL { return must(v.Url()) }
+
 // UrlVar sets the value p points to using Url.
 func (v Value) UrlVar(p *url.URL) error {
 	x, err := v.Url()
@@ -37,7 +40,30 @@ func unmarshalUrl(val Value, dest any) error {
 	return val.UrlVar(dest.(*url.URL))
 }
 
-func marshalUrl(v any) (string, error) {
+func marshalUrl(v any) (string, error) { return Options{}.marshalUrl(v) }
+
+// URLRedaction determines which credentials of an url.URL are redacted when
+// marshaling it.
+type URLRedaction uint8
+
+const (
+	// URLRedactNone marshals an url.URL including its credentials.
+	URLRedactNone URLRedaction = iota
+	// URLRedactPassword replaces the password of an url.URL with "xxxxx",
+	// see url.URL.Redacted.
+	URLRedactPassword
+	// URLRedactUserinfo removes the complete userinfo, both username and
+	// password, of an url.URL.
+	URLRedactUserinfo
+)
+
+func (o Options) marshalUrl(v any) (string, error) {
 	u := v.(url.URL)
+	switch o.RedactURL {
+	case URLRedactPassword:
+		return u.Redacted(), nil
+	case URLRedactUserinfo:
+		u.User = nil
+	}
 	return u.String(), nil
 }
@@ -4,10 +4,15 @@
 
 package rawconv
 
+import "strings"
+
 // Value is a textual representation of a raw value which is able to cast itself
 // to any of the supported types using its corresponding method.
 //
 //	boolVal, err := rawconv.Value("true").Bool()
+//
+// The bool, int, uint, float and complex methods do not allocate when Value
+// is successfully parsed.
 type Value string
 
 // IsEmpty indicates if Value is an empty string.
@@ -21,8 +26,83 @@ func (v Value) String() string { return string(v) }
 // StringVar sets the value p points to, to Value as raw string.
 func (v Value) StringVar(p *string) { *p = v.String() }
 
-// Bytes returns Value as raw bytes.
+// StringOr returns Value as raw string, or def if Value is empty.
+func (v Value) StringOr(def string) string {
+	if v.IsEmpty() {
+		return def
+	}
+	return v.String()
+}
+
+// Bytes returns a copy of Value as raw bytes. See UnsafeBytes for a
+// zero-copy alternative.
 func (v Value) Bytes() []byte { return []byte(v) }
 
 // BytesVar sets the value p points to, to Value as raw bytes.
 func (v Value) BytesVar(p *[]byte) { *p = v.Bytes() }
+
+// CompareNumericAware compares Value with other using a natural sort order,
+// where sequences of digits are compared by their numeric value instead of
+// character by character, e.g. "v2" sorts before "v10". It returns -1 when
+// Value sorts before other, +1 when it sorts after and 0 when both are equal.
+func (v Value) CompareNumericAware(other Value) int {
+	a, b := string(v), string(other)
+	for a != "" && b != "" {
+		if isDigit(a[0]) && isDigit(b[0]) {
+			var x, y string
+			x, a = cutDigits(a)
+			y, b = cutDigits(b)
+			if c := compareDigits(x, y); c != 0 {
+				return c
+			}
+			continue
+		}
+		if a[0] != b[0] {
+			if a[0] < b[0] {
+				return -1
+			}
+			return 1
+		}
+		a, b = a[1:], b[1:]
+	}
+	if a == "" && b == "" {
+		// numerically equal, e.g. "v01" and "v1", fallback to a stable order
+		return strings.Compare(string(v), string(other))
+	}
+	if a == "" {
+		return -1
+	}
+	return 1
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+// cutDigits returns the leading digits of str and the remainder.
+func cutDigits(str string) (digits, rest string) {
+	i := 0
+	for i < len(str) && isDigit(str[i]) {
+		i++
+	}
+	return str[:i], str[i:]
+}
+
+// compareDigits compares two sequences of digits by their numeric value,
+// without parsing them so their size is not limited.
+func compareDigits(x, y string) int {
+	x = strings.TrimLeft(x, "0")
+	y = strings.TrimLeft(y, "0")
+	if len(x) != len(y) {
+		if len(x) < len(y) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(x, y)
+}
+
+func must[T any](x T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return x
+}
@@ -5,8 +5,14 @@
 package rawconv
 
 import (
+	"encoding/json"
+	"io/fs"
 	"math"
+	"math/big"
+	"net/netip"
 	"net/url"
+	"reflect"
+	"regexp"
 	"strconv"
 	"testing"
 	"time"
@@ -291,6 +297,66 @@ func TestValue_GoString(t *testing.T) {
 	assert.Equal(t, `rawconv.Value("just some value")`, Value("just some value").GoString())
 }
 
+func TestValue_UnsafeBytes(t *testing.T) {
+	assert.Equal(t, []byte("foo bar"), Value("foo bar").UnsafeBytes())
+	assert.Empty(t, Value("").UnsafeBytes())
+}
+
+func TestValue_Value(t *testing.T) {
+	have, err := Value("foo").Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", have)
+}
+
+func TestValue_Scan(t *testing.T) {
+	tests := map[string]struct {
+		src  any
+		want Value
+	}{
+		"string": {src: "foo", want: "foo"},
+		"bytes":  {src: []byte("bar"), want: "bar"},
+		"nil":    {src: nil, want: ""},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			have := Value("initial")
+			assert.NoError(t, have.Scan(tc.src))
+			assert.Equal(t, tc.want, have)
+		})
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		have := Value("initial")
+		assert.ErrorIs(t, have.Scan(int64(1)), &UnsupportedTypeError{Type: reflect.TypeOf(int64(0))})
+		assert.Equal(t, Value("initial"), have)
+	})
+}
+
+func TestValue_CompareNumericAware(t *testing.T) {
+	tests := []struct {
+		a, b Value
+		want int
+	}{
+		{"", "", 0},
+		{"", "a", -1},
+		{"v1", "v1", 0},
+		{"v2", "v10", -1},
+		{"v10", "v2", 1},
+		{"1.2.10", "1.10.2", -1},
+		{"file9.txt", "file10.txt", -1},
+		{"v1", "v1.0", -1},
+		{"v01", "v1", -1},
+		{"abc", "abd", -1},
+		{"99999999999999999999", "100000000000000000000", -1},
+	}
+	for _, tc := range tests {
+		t.Run(string(tc.a)+"|"+string(tc.b), func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.a.CompareNumericAware(tc.b))
+			assert.Equal(t, -tc.want, tc.b.CompareNumericAware(tc.a))
+		})
+	}
+}
+
 func TestValueFromComplex64(t *testing.T) {
 	var want complex64 = 1 + 2i
 	have, haveErr := ValueFromComplex64(want).Complex64()
@@ -360,3 +426,839 @@ func TestValueFromUint32(t *testing.T) {
 	assert.Equal(t, want, have)
 	assert.Nil(t, haveErr)
 }
+
+func TestValue_Must(t *testing.T) {
+	tests := map[string]struct {
+		input Value
+		want  any
+		fn    func(v Value) any
+	}{
+		"Bool":       {"true", true, func(v Value) any { return v.MustBool() }},
+		"Int":        {"-1", -1, func(v Value) any { return v.MustInt() }},
+		"Int8":       {"8", int8(8), func(v Value) any { return v.MustInt8() }},
+		"Int16":      {"16", int16(16), func(v Value) any { return v.MustInt16() }},
+		"Int32":      {"32", int32(32), func(v Value) any { return v.MustInt32() }},
+		"Int64":      {"64", int64(64), func(v Value) any { return v.MustInt64() }},
+		"Uint":       {"1", uint(1), func(v Value) any { return v.MustUint() }},
+		"Uint8":      {"8", uint8(8), func(v Value) any { return v.MustUint8() }},
+		"Uint16":     {"16", uint16(16), func(v Value) any { return v.MustUint16() }},
+		"Uint32":     {"32", uint32(32), func(v Value) any { return v.MustUint32() }},
+		"Uint64":     {"64", uint64(64), func(v Value) any { return v.MustUint64() }},
+		"Float32":    {"1.5", float32(1.5), func(v Value) any { return v.MustFloat32() }},
+		"Float64":    {"1.5", 1.5, func(v Value) any { return v.MustFloat64() }},
+		"Complex64":  {"1+2i", complex64(1 + 2i), func(v Value) any { return v.MustComplex64() }},
+		"Complex128": {"1+2i", 1 + 2i, func(v Value) any { return v.MustComplex128() }},
+		"Duration":   {"5s", 5 * time.Second, func(v Value) any { return v.MustDuration() }},
+		"Time": {"1997-08-29", time.Date(1997, 8, 29, 0, 0, 0, 0, time.UTC),
+			func(v Value) any { return v.MustTime() }},
+		"Location": {"UTC", time.UTC, func(v Value) any { return v.MustLocation() }},
+		"Addr":     {"10.0.0.1", netip.MustParseAddr("10.0.0.1"), func(v Value) any { return v.MustAddr() }},
+		"AddrPort": {"10.0.0.1:80", netip.MustParseAddrPort("10.0.0.1:80"),
+			func(v Value) any { return v.MustAddrPort() }},
+		"Prefix": {"10.0.0.0/8", netip.MustParsePrefix("10.0.0.0/8"),
+			func(v Value) any { return v.MustPrefix() }},
+		"BigInt": {"1", big.NewInt(1), func(v Value) any { return v.MustBigInt() }},
+		"BigRat": {"1/2", big.NewRat(1, 2), func(v Value) any { return v.MustBigRat() }},
+		"Url": {"https://foo.bar", &url.URL{Scheme: "https", Host: "foo.bar"},
+			func(v Value) any { return v.MustUrl() }},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.fn(tc.input))
+			assert.Panics(t, func() { tc.fn("invalid") })
+		})
+	}
+}
+
+func TestValue_Or(t *testing.T) {
+	tests := map[string]struct {
+		input Value
+		want  any
+		fn    func(v Value) any
+	}{
+		"String":     {"foo", "foo", func(v Value) any { return v.StringOr("def") }},
+		"Bool":       {"true", true, func(v Value) any { return v.BoolOr(false) }},
+		"Int":        {"-1", -1, func(v Value) any { return v.IntOr(9) }},
+		"Int8":       {"8", int8(8), func(v Value) any { return v.Int8Or(9) }},
+		"Int16":      {"16", int16(16), func(v Value) any { return v.Int16Or(9) }},
+		"Int32":      {"32", int32(32), func(v Value) any { return v.Int32Or(9) }},
+		"Int64":      {"64", int64(64), func(v Value) any { return v.Int64Or(9) }},
+		"Uint":       {"1", uint(1), func(v Value) any { return v.UintOr(9) }},
+		"Uint8":      {"8", uint8(8), func(v Value) any { return v.Uint8Or(9) }},
+		"Uint16":     {"16", uint16(16), func(v Value) any { return v.Uint16Or(9) }},
+		"Uint32":     {"32", uint32(32), func(v Value) any { return v.Uint32Or(9) }},
+		"Uint64":     {"64", uint64(64), func(v Value) any { return v.Uint64Or(9) }},
+		"Float32":    {"1.5", float32(1.5), func(v Value) any { return v.Float32Or(9) }},
+		"Float64":    {"1.5", 1.5, func(v Value) any { return v.Float64Or(9) }},
+		"Complex64":  {"1+2i", complex64(1 + 2i), func(v Value) any { return v.Complex64Or(9) }},
+		"Complex128": {"1+2i", 1 + 2i, func(v Value) any { return v.Complex128Or(9) }},
+		"Duration":   {"5s", 5 * time.Second, func(v Value) any { return v.DurationOr(9) }},
+		"Time": {"1997-08-29", time.Date(1997, 8, 29, 0, 0, 0, 0, time.UTC),
+			func(v Value) any { return v.TimeOr(time.Time{}) }},
+	}
+
+	defaults := map[string]any{
+		"String": "def", "Bool": false, "Int": 9, "Int8": int8(9),
+		"Int16": int16(9), "Int32": int32(9), "Int64": int64(9),
+		"Uint": uint(9), "Uint8": uint8(9), "Uint16": uint16(9),
+		"Uint32": uint32(9), "Uint64": uint64(9), "Float32": float32(9),
+		"Float64": float64(9), "Complex64": complex64(9), "Complex128": complex128(9),
+		"Duration": time.Duration(9), "Time": time.Time{},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.fn(tc.input))
+			assert.Equal(t, defaults[name], tc.fn(""))
+			if name != "String" {
+				assert.Equal(t, defaults[name], tc.fn("invalid"))
+			}
+		})
+	}
+}
+
+func TestOptions_parseBool(t *testing.T) {
+	opts := Options{
+		BoolTrueTokens:  []string{"yes", "on"},
+		BoolFalseTokens: []string{"no", "off"},
+	}
+
+	tests := map[Value]bool{
+		"yes": true, "ON": true, " On ": true, "true": true, "1": true,
+		"no": false, "Off": false, "false": false, "0": false,
+	}
+	for input, want := range tests {
+		t.Run(input.String(), func(t *testing.T) {
+			have, err := opts.parseBool(input)
+			assert.NoError(t, err)
+			assert.Equal(t, want, have)
+		})
+	}
+
+	_, err := opts.parseBool("enabled")
+	assert.ErrorIs(t, err, ErrParseFailure)
+	_, err = Options{}.parseBool("yes")
+	assert.ErrorIs(t, err, ErrParseFailure)
+}
+
+func TestOptions_formatBool(t *testing.T) {
+	opts := Options{BoolTrueTokens: []string{"on"}}
+	assert.Equal(t, "on", opts.formatBool(true))
+	assert.Equal(t, "false", opts.formatBool(false))
+	assert.Equal(t, "true", Options{}.formatBool(true))
+}
+
+func TestValue_slices(t *testing.T) {
+	t.Run("Items", func(t *testing.T) {
+		assert.Nil(t, Value("").Items())
+		assert.Equal(t, []Value{"a", "b", ""}, Value("a, b ,").Items())
+		assert.Equal(t, []Value{"a,b", "c"}, Value("a,b;c").ItemsSep(";"))
+	})
+	t.Run("StringSlice", func(t *testing.T) {
+		assert.Nil(t, Value("").StringSlice())
+		assert.Equal(t, []string{"foo", "bar"}, Value("foo,bar").StringSlice())
+		assert.Equal(t, []string{"foo", "bar"}, Value("foo | bar").StringSliceSep("|"))
+	})
+	t.Run("IntSlice", func(t *testing.T) {
+		have, err := Value("1, 2,3").IntSlice()
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3}, have)
+
+		have, err = Value("").IntSlice()
+		assert.NoError(t, err)
+		assert.Nil(t, have)
+
+		have, err = Value("1,2,x").IntSlice()
+		assert.Nil(t, have)
+		assert.ErrorIs(t, err, ErrParseFailure)
+
+		var ue *UnmarshalError
+		if assert.ErrorAs(t, err, &ue) {
+			assert.Equal(t, 2, ue.Index)
+		}
+	})
+	t.Run("Float64Slice", func(t *testing.T) {
+		have, err := Value("1.5;-2").Float64SliceSep(";")
+		assert.NoError(t, err)
+		assert.Equal(t, []float64{1.5, -2}, have)
+	})
+	t.Run("DurationSlice", func(t *testing.T) {
+		have, err := Value("1s,2m").DurationSlice()
+		assert.NoError(t, err)
+		assert.Equal(t, []time.Duration{time.Second, 2 * time.Minute}, have)
+
+		_, err = Value("1s,2").DurationSlice()
+		assert.ErrorIs(t, err, ErrParseFailure)
+	})
+}
+
+func TestValue_ByteSize(t *testing.T) {
+	tests := map[Value]ByteSize{
+		"0":                    0,
+		"512":                  512,
+		"512B":                 512,
+		"10KB":                 10 * KB,
+		"10 kb":                10 * KB,
+		"10k":                  10 * KB,
+		"4MiB":                 4 * MiB,
+		"4mi":                  4 * MiB,
+		"1.5GB":                1500 * MB,
+		"0.5KiB":               512,
+		" 2 TiB ":              2 * TiB,
+		"1.5":                  2,
+		"15EB":                 15 * EB,
+		"18446744073709551615": math.MaxUint64,
+	}
+	for input, want := range tests {
+		t.Run(input.String(), func(t *testing.T) {
+			have, err := input.ByteSize()
+			assert.NoError(t, err)
+			assert.Equal(t, want, have)
+		})
+	}
+
+	for _, input := range []Value{"", "KB", "-1KB", "1XB", "1.2.3MB"} {
+		t.Run(input.String(), func(t *testing.T) {
+			_, err := input.ByteSize()
+			assert.ErrorIs(t, err, ErrParseFailure)
+		})
+	}
+	for _, input := range []Value{"16EiB", "18446744073709551616", "20EB", "18.5EB"} {
+		t.Run(input.String(), func(t *testing.T) {
+			_, err := input.ByteSize()
+			assert.ErrorIs(t, err, ErrValidationFailure)
+		})
+	}
+}
+
+func TestByteSize_String(t *testing.T) {
+	tests := map[ByteSize]string{
+		0:              "0B",
+		512:            "512B",
+		10 * KB:        "10KB",
+		4 * MiB:        "4MiB",
+		1500 * MB:      "1500MB",
+		1000 * KiB:     "1000KiB",
+		1025:           "1025B",
+		math.MaxUint64: "18446744073709551615B",
+	}
+	for input, want := range tests {
+		t.Run(want, func(t *testing.T) {
+			assert.Equal(t, want, input.String())
+
+			val, err := Marshal(input)
+			assert.NoError(t, err)
+			assert.Equal(t, Value(want), val)
+
+			var have ByteSize
+			assert.NoError(t, Unmarshal(val, &have))
+			assert.Equal(t, input, have)
+		})
+	}
+}
+
+func TestValue_IntBase(t *testing.T) {
+	i, err := Value("-ff").IntBase(16)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-255), i)
+
+	u, err := Value("0o17").UintBase(0)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(15), u)
+
+	_, err = Value("12").IntBase(2)
+	assert.ErrorIs(t, err, ErrParseFailure)
+	_, err = Value("-1").UintBase(10)
+	assert.ErrorIs(t, err, ErrParseFailure)
+}
+
+func TestValue_FileMode(t *testing.T) {
+	tests := map[Value]fs.FileMode{
+		"0644":   0o644,
+		"755":    0o755,
+		"0o600":  0o600,
+		"0O1777": 0o1777,
+		"0":      0,
+	}
+	for input, want := range tests {
+		t.Run(input.String(), func(t *testing.T) {
+			have, err := input.FileMode()
+			assert.NoError(t, err)
+			assert.Equal(t, want, have)
+		})
+	}
+
+	for _, input := range []Value{"", "0x1ff", "rw-r--r--", "0999"} {
+		_, err := input.FileMode()
+		assert.ErrorIs(t, err, ErrParseFailure, input.String())
+	}
+}
+
+func TestValueFromFileMode(t *testing.T) {
+	tests := map[fs.FileMode]Value{
+		0:                  "0",
+		0o644:              "0644",
+		0o755:              "0755",
+		fs.ModeDir | 0o700: "020000000700",
+	}
+	for input, want := range tests {
+		t.Run(want.String(), func(t *testing.T) {
+			assert.Equal(t, want, ValueFromFileMode(input))
+
+			have, err := Marshal(input)
+			assert.NoError(t, err)
+			assert.Equal(t, want, have)
+
+			var mode fs.FileMode
+			assert.NoError(t, Unmarshal(have, &mode))
+			assert.Equal(t, input, mode)
+		})
+	}
+}
+
+func TestValue_HostPort(t *testing.T) {
+	tests := map[Value]struct {
+		host    string
+		port    uint16
+		wantErr error
+	}{
+		"localhost:8080":        {host: "localhost", port: 8080},
+		"127.0.0.1:80":          {host: "127.0.0.1", port: 80},
+		"[::1]:443":             {host: "::1", port: 443},
+		"[fe80::1%eth0]:53":     {host: "fe80::1%eth0", port: 53},
+		":9000":                 {host: "", port: 9000},
+		"::1:443":               {wantErr: ErrParseFailure},
+		"localhost":             {wantErr: ErrParseFailure},
+		"localhost:":            {wantErr: ErrParseFailure},
+		"localhost:http":        {wantErr: ErrParseFailure},
+		"localhost:65536":       {wantErr: ErrValidationFailure},
+		"[2001:db8::1]:8080":    {host: "2001:db8::1", port: 8080},
+		"example.com:0":         {host: "example.com", port: 0},
+		"[::ffff:1.2.3.4]:1234": {host: "::ffff:1.2.3.4", port: 1234},
+	}
+	for input, tc := range tests {
+		t.Run(input.String(), func(t *testing.T) {
+			host, port, err := input.HostPort()
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.host, host)
+			assert.Equal(t, tc.port, port)
+			assert.Equal(t, input, ValueFromHostPort(host, port))
+		})
+	}
+}
+
+func TestValueFromHostPort(t *testing.T) {
+	assert.Equal(t, Value("[::1]:80"), ValueFromHostPort("::1", 80))
+	assert.Equal(t, Value("[::1]:80"), ValueFromHostPort("[::1]", 80))
+	assert.Equal(t, Value("localhost:8080"), ValueFromHostPort("localhost", 8080))
+}
+
+func TestValue_Tristate(t *testing.T) {
+	tests := map[Value]struct {
+		want    Tristate
+		wantErr error
+	}{
+		"":      {want: TristateUnset},
+		"true":  {want: TristateTrue},
+		"1":     {want: TristateTrue},
+		"false": {want: TristateFalse},
+		"F":     {want: TristateFalse},
+		"maybe": {wantErr: ErrParseFailure},
+	}
+	for input, tc := range tests {
+		t.Run(input.String(), func(t *testing.T) {
+			have, err := input.Tristate()
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, have)
+
+			// an empty value must reset a previously set destination
+			dest := TristateTrue
+			assert.NoError(t, Unmarshal(input, &dest))
+			assert.Equal(t, tc.want, dest)
+		})
+	}
+}
+
+func TestTristate(t *testing.T) {
+	assert.Equal(t, TristateTrue, TristateOf(true))
+	assert.Equal(t, TristateFalse, TristateOf(false))
+
+	assert.False(t, TristateUnset.IsSet())
+	assert.True(t, TristateFalse.IsSet())
+	assert.True(t, TristateTrue.BoolOr(false))
+	assert.False(t, TristateFalse.BoolOr(true))
+	assert.True(t, TristateUnset.BoolOr(true))
+
+	for _, v := range []Tristate{TristateUnset, TristateFalse, TristateTrue} {
+		have, err := Marshal(v)
+		assert.NoError(t, err)
+		assert.Equal(t, v.String(), have.String())
+	}
+
+	t.Run("tokens", func(t *testing.T) {
+		opts := Options{BoolTrueTokens: []string{"yes"}, BoolFalseTokens: []string{"no"}}
+		u := Unmarshaler{Options: opts}
+		var have Tristate
+		assert.NoError(t, u.Unmarshal("NO", reflect.ValueOf(&have)))
+		assert.Equal(t, TristateFalse, have)
+
+		m := Marshaler{Options: opts}
+		val, err := m.Marshal(reflect.ValueOf(TristateTrue))
+		assert.NoError(t, err)
+		assert.Equal(t, Value("yes"), val)
+	})
+}
+
+func TestValue_ExtendedDuration(t *testing.T) {
+	tests := map[Value]time.Duration{
+		"0":         0,
+		"90s":       90 * time.Second,
+		"2d":        48 * time.Hour,
+		"1w":        7 * 24 * time.Hour,
+		"1.5d":      36 * time.Hour,
+		"1w2d3h30m": 9*24*time.Hour + 3*time.Hour + 30*time.Minute,
+		"3h1d":      27 * time.Hour,
+		"-1d12h":    -36 * time.Hour,
+		"+1w":       7 * 24 * time.Hour,
+	}
+	for input, want := range tests {
+		t.Run(input.String(), func(t *testing.T) {
+			have, err := input.ExtendedDuration()
+			assert.NoError(t, err)
+			assert.Equal(t, want, have)
+
+			if want != 0 {
+				have, err = Value(formatExtendedDuration(want)).ExtendedDuration()
+				assert.NoError(t, err)
+				assert.Equal(t, want, have, "round trip")
+			}
+		})
+	}
+
+	for _, input := range []Value{"", "-", "d", "1x", "1.2.3d", "2"} {
+		_, err := input.ExtendedDuration()
+		assert.ErrorIs(t, err, ErrParseFailure, string(input))
+	}
+	_, err := Value("100000000w").ExtendedDuration()
+	assert.ErrorIs(t, err, ErrValidationFailure)
+	assert.Panics(t, func() { Value("1y").MustExtendedDuration() })
+
+	assert.Equal(t, "1w2d3h0m0s", formatExtendedDuration(9*24*time.Hour+3*time.Hour))
+	assert.Equal(t, "-2d", formatExtendedDuration(-48*time.Hour))
+	assert.Equal(t, "23h0m0s", formatExtendedDuration(23*time.Hour))
+}
+
+func TestValue_Unix(t *testing.T) {
+	want := time.Date(1997, 8, 29, 13, 37, 0, 0, time.UTC)
+
+	have, err := Value("872861820").Unix()
+	assert.NoError(t, err)
+	assert.Equal(t, want, have)
+
+	have, err = Value("872861820123").UnixMilli()
+	assert.NoError(t, err)
+	assert.Equal(t, want.Add(123*time.Millisecond), have)
+
+	have, err = Value("-1").Unix()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(1969, 12, 31, 23, 59, 59, 0, time.UTC), have)
+
+	_, err = Value("1997-08-29").Unix()
+	assert.ErrorIs(t, err, ErrParseFailure)
+	_, err = Value("1.5").UnixMilli()
+	assert.ErrorIs(t, err, ErrParseFailure)
+}
+
+func TestValue_Location(t *testing.T) {
+	t.Run("var", func(t *testing.T) {
+		var have *time.Location
+		assert.NoError(t, Value("UTC").LocationVar(&have))
+		assert.Same(t, time.UTC, have)
+	})
+	t.Run("unmarshal", func(t *testing.T) {
+		tests := map[Value]*time.Location{
+			"UTC":   time.UTC,
+			"Local": time.Local,
+		}
+		for input, want := range tests {
+			var have *time.Location
+			assert.NoError(t, Unmarshal(input, &have))
+			assert.Same(t, want, have)
+			assert.Equal(t, input.String(), have.String())
+		}
+
+		var ptr **time.Location
+		assert.NoError(t, Unmarshal("UTC", &ptr))
+		assert.Same(t, time.UTC, *ptr)
+
+		assert.ErrorIs(t, Unmarshal("Nowhere/Special", &ptr), ErrParseFailure)
+	})
+	t.Run("struct", func(t *testing.T) {
+		type fixture struct{ Zone *time.Location }
+
+		var have fixture
+		assert.NoError(t, UnmarshalStruct(map[string]Value{"Zone": "Local"}, &have))
+		assert.Same(t, time.Local, have.Zone)
+
+		m, err := MarshalStruct(have)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]Value{"Zone": "Local"}, m)
+	})
+	t.Run("marshal", func(t *testing.T) {
+		val, err := Marshal(time.UTC)
+		assert.NoError(t, err)
+		assert.Equal(t, Value("UTC"), val)
+
+		val, err = Marshal((*time.Location)(nil))
+		assert.NoError(t, err)
+		assert.Equal(t, Value(""), val)
+	})
+
+	assert.Equal(t, RegisteredType, UnmarshalMechanism(reflect.TypeOf(time.UTC)))
+	assert.Equal(t, RegisteredType, MarshalMechanism(reflect.TypeOf(time.UTC)))
+}
+
+func TestValue_Regexp(t *testing.T) {
+	have, err := Value(`^\d+-[a-z]+$`).Regexp()
+	assert.NoError(t, err)
+	assert.True(t, have.MatchString("42-abc"))
+
+	_, err = Value("a(b").Regexp()
+	assert.ErrorIs(t, err, ErrParseFailure)
+	assert.Panics(t, func() { Value("[a-").MustRegexp() })
+
+	t.Run("unmarshal", func(t *testing.T) {
+		var ptr *regexp.Regexp
+		assert.NoError(t, Unmarshal("fo+", &ptr))
+		assert.Equal(t, "fo+", ptr.String())
+
+		var list []regexp.Regexp
+		assert.NoError(t, Unmarshal("a.c,^b", &list))
+		assert.Len(t, list, 2)
+		assert.True(t, list[1].MatchString("bar"))
+
+		val, err := Marshal(ptr)
+		assert.NoError(t, err)
+		assert.Equal(t, Value("fo+"), val)
+	})
+}
+
+func TestValue_ContentEncoding(t *testing.T) {
+	tests := map[Value]ContentEncoding{
+		"gzip":     EncodingGzip,
+		"X-GZIP":   EncodingGzip,
+		" br ":     EncodingBrotli,
+		"Brotli":   EncodingBrotli,
+		"zstd":     EncodingZstd,
+		"identity": EncodingIdentity,
+	}
+	for input, want := range tests {
+		t.Run(input.String(), func(t *testing.T) {
+			assert.Equal(t, want, input.MustContentEncoding())
+
+			var have ContentEncoding
+			assert.NoError(t, Unmarshal(input, &have))
+			assert.Equal(t, want, have)
+		})
+	}
+
+	var have []ContentEncoding
+	assert.ErrorIs(t, Unmarshal("gzip,lzma", &have), ErrInvalidContentEncoding)
+	assert.ErrorIs(t, Unmarshal("gzip,lzma", &have), ErrParseFailure)
+}
+
+func TestValue_Charset(t *testing.T) {
+	tests := map[Value]Charset{
+		"UTF-8":    CharsetUTF8,
+		"utf8":     CharsetUTF8,
+		"latin1":   CharsetISO88591,
+		"US-ASCII": CharsetASCII,
+		"cp1252":   CharsetWindows1252,
+	}
+	for input, want := range tests {
+		t.Run(input.String(), func(t *testing.T) {
+			assert.Equal(t, want, input.MustCharset())
+
+			var have Charset
+			assert.NoError(t, Unmarshal(input, &have))
+			assert.Equal(t, want, have)
+
+			val, err := Marshal(have)
+			assert.NoError(t, err)
+			assert.Equal(t, Value(want), val)
+		})
+	}
+
+	var have Charset
+	assert.ErrorIs(t, Unmarshal("klingon", &have), ErrInvalidCharset)
+	assert.Panics(t, func() { Value("").MustCharset() })
+}
+
+func TestJoinValues(t *testing.T) {
+	tests := map[string]struct {
+		items []Value
+		opts  Options
+		want  Value
+	}{
+		"empty":     {want: ""},
+		"single":    {items: []Value{"a"}, want: "a"},
+		"default":   {items: []Value{"a", "b", ""}, want: "a,b,"},
+		"separator": {items: []Value{"a", "b"}, opts: Options{ItemsSeparator: ";"}, want: "a;b"},
+		"quote": {
+			items: []Value{"a,b", `say "hi"`, " c", "d"},
+			opts:  Options{Quote: true},
+			want:  `"a,b","say ""hi"""," c",d`,
+		},
+		"escape": {
+			items: []Value{"a,b", `c\d`, "e=f"},
+			opts:  Options{Escape: true},
+			want:  `a\,b,c\\d,e\=f`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			have := JoinValues(tc.items, tc.opts)
+			assert.Equal(t, tc.want, have)
+
+			m := Marshaler{Options: tc.opts}
+			want, err := m.Marshal(reflect.ValueOf(tc.items))
+			assert.NoError(t, err)
+			assert.Equal(t, want, have, "must equal Marshal")
+
+			if len(tc.items) < 2 {
+				return
+			}
+			var items []Value
+			u := Unmarshaler{Options: tc.opts}
+			assert.NoError(t, u.Unmarshal(have, reflect.ValueOf(&items)))
+			assert.Equal(t, tc.items, items, "must equal Unmarshal")
+		})
+	}
+}
+
+func TestValue_Map(t *testing.T) {
+	tests := map[string]struct {
+		input   Value
+		itemSep string
+		kvSep   string
+		want    map[string]Value
+		wantErr error
+	}{
+		"empty": {
+			want: map[string]Value{},
+		},
+		"defaults": {
+			input: "host=localhost, port = 8080,url=http://x?a=b",
+			want:  map[string]Value{"host": "localhost", "port": "8080", "url": "http://x?a=b"},
+		},
+		"separators": {
+			input:   "a:1;b:;a:3",
+			itemSep: ";",
+			kvSep:   ":",
+			want:    map[string]Value{"a": "3", "b": ""},
+		},
+		"invalid": {
+			input:   "a=1,b",
+			wantErr: ErrMapInvalidFormat,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			have, err := tc.input.Map(tc.itemSep, tc.kvSep)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Nil(t, have)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, have)
+		})
+	}
+}
+
+func TestValue_allocations(t *testing.T) {
+	tests := map[string]func(){
+		"Bool":       func() { _, _ = Value("true").Bool() },
+		"Int":        func() { _, _ = Value("-123").Int() },
+		"Int64":      func() { _, _ = Value("0x7f").Int64() },
+		"Uint":       func() { _, _ = Value("123").Uint() },
+		"Uint8":      func() { _, _ = Value("255").Uint8() },
+		"Float32":    func() { _, _ = Value("1.5").Float32() },
+		"Float64":    func() { _, _ = Value("1e10").Float64() },
+		"Complex128": func() { _, _ = Value("1+2i").Complex128() },
+		"Duration":   func() { _, _ = Value("1m30s").Duration() },
+	}
+	for name, fn := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Zero(t, testing.AllocsPerRun(100, fn))
+		})
+	}
+}
+
+func BenchmarkValue_Bool(b *testing.B) {
+	v := Value("true")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = v.Bool()
+	}
+}
+
+func BenchmarkValue_Int(b *testing.B) {
+	v := Value("-1234567")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = v.Int()
+	}
+}
+
+func BenchmarkValue_Uint(b *testing.B) {
+	v := Value("1234567")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = v.Uint()
+	}
+}
+
+func BenchmarkValue_Float64(b *testing.B) {
+	v := Value("3.14159")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = v.Float64()
+	}
+}
+
+func TestValue_MarshalText(t *testing.T) {
+	have, err := Value("foo").MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("foo"), have)
+
+	var v Value
+	assert.NoError(t, v.UnmarshalText([]byte("bar")))
+	assert.Equal(t, Value("bar"), v)
+}
+
+func TestValue_MarshalJSON(t *testing.T) {
+	type fixture struct {
+		Val  Value
+		List []Value
+	}
+
+	have, err := json.Marshal(fixture{Val: `say "hi"`, List: []Value{"a", "1"}})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"Val":"say \"hi\"","List":["a","1"]}`, string(have))
+
+	t.Run("map key", func(t *testing.T) {
+		have, err := json.Marshal(map[Value]Value{"k": "v"})
+		assert.NoError(t, err)
+		assert.Equal(t, `{"k":"v"}`, string(have))
+	})
+}
+
+func TestValue_UnmarshalJSON(t *testing.T) {
+	tests := map[string]Value{
+		`"foo"`:        "foo",
+		`"say \"hi\""`: `say "hi"`,
+		`42`:           "42",
+		`-1.5e3`:       "-1.5e3",
+		`true`:         "true",
+		`null`:         "",
+		` {"a": 1} `:   `{"a": 1}`,
+		`[1,2]`:        "[1,2]",
+		`"é"`:          "é",
+	}
+	for input, want := range tests {
+		t.Run(input, func(t *testing.T) {
+			have := Value("initial")
+			assert.NoError(t, json.Unmarshal([]byte(input), &have))
+			assert.Equal(t, want, have)
+		})
+	}
+
+	for _, input := range []string{``, `tru`, `"unterminated`, `{"a":}`} {
+		t.Run("invalid "+input, func(t *testing.T) {
+			var have Value
+			assert.ErrorIs(t, have.UnmarshalJSON([]byte(input)), ErrInvalidJSON)
+		})
+	}
+}
+
+func TestValue_rawconv(t *testing.T) {
+	type fixture struct {
+		Val Value
+	}
+
+	var have fixture
+	assert.NoError(t, UnmarshalStruct(map[string]Value{"Val": "foo"}, &have))
+	assert.Equal(t, Value("foo"), have.Val)
+
+	var list []Value
+	assert.NoError(t, Unmarshal("a,b", &list))
+	assert.Equal(t, []Value{"a", "b"}, list)
+
+	val, err := Marshal(list)
+	assert.NoError(t, err)
+	assert.Equal(t, Value("a,b"), val)
+
+	assert.Equal(t, RegisteredInterface, UnmarshalMechanism(reflect.TypeOf(Value(""))))
+	assert.Equal(t, RegisteredInterface, MarshalMechanism(reflect.TypeOf(Value(""))))
+}
+
+func TestValue_JSONNumber(t *testing.T) {
+	for _, input := range []Value{"0", "42", "-1.5", "1e3", "1.5E-10"} {
+		have, err := input.JSONNumber()
+		assert.NoError(t, err)
+		assert.Equal(t, json.Number(input), have)
+	}
+	for _, input := range []Value{"", "0x10", "+1", ".5", "01", "Inf", "NaN", "1_000", " 1", `"1"`, "[1]"} {
+		_, err := input.JSONNumber()
+		assert.ErrorIs(t, err, ErrParseFailure, input.String())
+	}
+}
+
+func TestValue_JSONValue(t *testing.T) {
+	tests := map[Value][2]any{
+		"true":  {true, true},
+		"false": {false, false},
+		"null":  {nil, nil},
+		"42":    {float64(42), json.Number("42")},
+		"-1e3":  {float64(-1000), json.Number("-1e3")},
+		"0x10":  {"0x10", "0x10"},
+		"":      {"", ""},
+		"True":  {"True", "True"},
+		"foo":   {"foo", "foo"},
+	}
+	for input, want := range tests {
+		t.Run(input.String(), func(t *testing.T) {
+			have, err := input.JSONValue(false)
+			assert.NoError(t, err)
+			assert.Equal(t, want[0], have)
+
+			have, err = input.JSONValue(true)
+			assert.NoError(t, err)
+			assert.Equal(t, want[1], have)
+
+			// must equal the result of encoding/json for the same scalar
+			if _, ok := want[0].(string); !ok {
+				var fromJSON any
+				assert.NoError(t, json.Unmarshal(input.Bytes(), &fromJSON))
+				assert.Equal(t, fromJSON, want[0])
+			}
+		})
+	}
+
+	_, err := Value("1e400").JSONValue(false)
+	assert.ErrorIs(t, err, ErrValidationFailure)
+}
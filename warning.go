@@ -0,0 +1,39 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"reflect"
+
+	"github.com/go-pogo/errors"
+)
+
+const WarnDuplicateKey errors.Msg = "duplicate map key, last value is kept"
+
+// Warning describes a non-fatal condition which occurred while unmarshaling.
+// See Options.OnWarning.
+type Warning struct {
+	// Path describes the location of the value within its collection, see
+	// Options.OnSkipEmpty for details.
+	Path string
+	// Type is the type of the destination.
+	Type reflect.Type
+	// Err describes the condition, e.g. WarnDuplicateKey.
+	Err error
+}
+
+func (w Warning) String() string {
+	if w.Path == "" {
+		return w.Err.Error()
+	}
+	return w.Path + ": " + w.Err.Error()
+}
+
+// warn calls Options.OnWarning, when set, with a Warning.
+func (u *Unmarshaler) warn(path string, typ reflect.Type, err error) {
+	if u.OnWarning != nil {
+		u.OnWarning(Warning{Path: path, Type: typ, Err: err})
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rawconv
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshaler_OnWarning(t *testing.T) {
+	var have []Warning
+	var u Unmarshaler
+	u.OnWarning = func(w Warning) { have = append(have, w) }
+
+	var m map[string]int
+	assert.NoError(t, u.Unmarshal("a=1,b=2,a=3", reflect.ValueOf(&m)))
+	assert.Equal(t, map[string]int{"a": 3, "b": 2}, m)
+
+	if assert.Len(t, have, 1) {
+		assert.Equal(t, "[a]", have[0].Path)
+		assert.Equal(t, reflect.TypeOf(0), have[0].Type)
+		assert.ErrorIs(t, have[0].Err, WarnDuplicateKey)
+		assert.Equal(t, "[a]: "+WarnDuplicateKey.Error(), have[0].String())
+	}
+}